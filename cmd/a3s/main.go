@@ -7,34 +7,78 @@ import (
 	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/johnoct/a3s/internal/config"
 	"github.com/johnoct/a3s/internal/model"
+	"github.com/johnoct/a3s/internal/ui/styles"
 	"golang.org/x/term"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		runConfigCommand(os.Args[2:])
+		return
+	}
+
 	var (
-		profile = flag.String("profile", "", "AWS profile to use")
-		region  = flag.String("region", "", "AWS region to use")
-		help    = flag.Bool("help", false, "Show help")
+		profile  = flag.String("profile", "", "AWS profile to use")
+		region   = flag.String("region", "", "AWS region to use")
+		backend  = flag.String("backend", "aws", "IAM data source: aws or fixture:<file>")
+		record   = flag.String("record", "", "Record AWS API responses to this directory")
+		replay   = flag.String("replay", "", "Replay AWS API responses from this directory")
+		refresh  = flag.Duration("refresh", 0, "Watch mode: refresh the list at this interval (e.g. 30s)")
+		readOnly = flag.Bool("read-only", false, "Block AWS API calls that could change resources")
+		help     = flag.Bool("help", false, "Show help")
 	)
 
 	flag.Parse()
 
+	setFlags := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		setFlags[f.Name] = true
+	})
+
 	if *help {
 		printHelp()
 		os.Exit(0)
 	}
 
-	// Use environment variables if flags not provided
+	if *record != "" && *replay != "" {
+		log.Fatal("-record and -replay cannot be used together")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Use environment variables if flags not provided, then the config file
 	if *profile == "" {
 		*profile = os.Getenv("AWS_PROFILE")
 	}
+	if *profile == "" {
+		*profile = cfg.Profile
+	}
 	if *region == "" {
 		*region = os.Getenv("AWS_REGION")
 		if *region == "" {
 			*region = os.Getenv("AWS_DEFAULT_REGION")
 		}
 	}
+	if *region == "" {
+		*region = cfg.Region
+	}
+	if !setFlags["refresh"] {
+		*refresh = cfg.Refresh
+	}
+	if !setFlags["read-only"] {
+		*readOnly = cfg.ReadOnly
+	}
+
+	if cfg.Theme != "" {
+		if err := styles.ApplyTheme(cfg.Theme); err != nil {
+			log.Fatal(err)
+		}
+	}
 
 	// Get initial terminal size
 	width, height := 80, 24 // defaults
@@ -42,7 +86,20 @@ func main() {
 		width, height = w, h
 	}
 
-	app, err := model.NewAppWithSize(*profile, *region, width, height)
+	app, err := model.NewAppWithOptions(model.Options{
+		Profile:         *profile,
+		Region:          *region,
+		Backend:         *backend,
+		RecordDir:       *record,
+		ReplayDir:       *replay,
+		RefreshInterval: *refresh,
+		Columns:         cfg.Columns,
+		KeyBindings:     cfg.KeyBindings,
+		Scenarios:       cfg.Scenarios,
+		ReadOnly:        *readOnly,
+		Width:           width,
+		Height:          height,
+	})
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -56,15 +113,53 @@ func main() {
 	}
 }
 
+// runConfigCommand implements "a3s config <init|path>"
+func runConfigCommand(args []string) {
+	fs := flag.NewFlagSet("config", flag.ExitOnError)
+	force := fs.Bool("force", false, "Overwrite an existing config file")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), `Usage:
+  a3s config init [-force]   Write a commented default config file
+  a3s config path            Print the config file location`)
+	}
+
+	if len(args) == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	fs.Parse(args[1:])
+
+	switch args[0] {
+	case "init":
+		path, err := config.Init(*force)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println("Wrote", path)
+	case "path":
+		fmt.Println(config.Path())
+	default:
+		fs.Usage()
+		os.Exit(2)
+	}
+}
+
 func printHelp() {
 	fmt.Println(`a3s - AWS Terminal User Interface
 
 Usage:
   a3s [flags]
+  a3s config init [-force]   Write a commented default config file
+  a3s config path            Print the config file location
 
 Flags:
   -profile string   AWS profile to use (default: from environment)
   -region string    AWS region to use (default: from environment)
+  -backend string   IAM data source: aws (default) or fixture:<file>
+  -record dir       Record all AWS API responses to dir
+  -replay dir       Replay AWS API responses recorded with -record (no credentials needed)
+  -refresh duration Watch mode: refresh the list at this interval, e.g. 30s
+  -read-only        Block AWS API calls that could change resources
   -help            Show this help message
 
 Environment Variables:
@@ -72,19 +167,39 @@ Environment Variables:
   AWS_REGION        Default AWS region
   AWS_DEFAULT_REGION Alternative for AWS region
 
+Config File:
+  ~/.config/a3s/config.yaml ($XDG_CONFIG_HOME/a3s/config.yaml) sets default
+  profile, region, refresh interval, columns, key bindings, theme and
+  read-only mode. Flags and environment variables take precedence.
+
 Keyboard Shortcuts:
   j/k or ↑/↓       Navigate up/down
   Enter            View role details
   /                Search roles
+  N/C/L            Sort by name, created or last used (again to reverse)
   Tab/Shift+Tab    Switch between tabs in detail view
+  u                List who uses the selected managed policy (Policies tab)
+  b                View the role's permissions boundary
+  c                Ask whether the role can do an action on a resource
+  s                Run the policy simulator against the role
   Esc              Go back
   q                Quit
   r                Refresh
+  R                Switch region
+  s                Toggle policy scope in the policy list
+  :                Command mode (:roles, :users, :groups, :policies [scope],
+                   :instanceprofiles, :oidc, :saml, :profile [name],
+                   :region [region], :columns [ids], :simulate [name],
+                   :watch [interval], :q)
   ?                Show help
 
 Examples:
   a3s                           # Use default profile and region
   a3s -profile prod             # Use 'prod' profile
   a3s -region us-west-2         # Use specific region
-  a3s -profile dev -region eu-west-1`)
+  a3s -profile dev -region eu-west-1
+  a3s -backend fixture:examples/fixture.json  # Offline, no AWS credentials
+  a3s -record ./session                       # Capture API traffic for a bug report
+  a3s -replay ./session                       # Reproduce a captured session
+  a3s -refresh 30s                            # Watch roles, highlighting changes`)
 }
@@ -12,33 +12,115 @@ type AWSClient struct {
 	Config  aws.Config
 	Profile string
 	Region  string
+
+	// opts are kept so profile and region switches preserve recording/replay
+	opts []Option
 }
 
-func New(ctx context.Context, profile, region string) (*AWSClient, error) {
-	var opts []func(*config.LoadOptions) error
+// Option customises how New builds the AWS configuration.
+type Option func(*options)
+
+type options struct {
+	recordDir string
+	replayDir string
+	readOnly  bool
+}
 
-	if profile != "" {
-		opts = append(opts, config.WithSharedConfigProfile(profile))
+// WithRecording captures every API response made through the client's
+// Config into dir.
+func WithRecording(dir string) Option {
+	return func(o *options) {
+		o.recordDir = dir
+	}
+}
+
+// WithReplay serves API responses from a directory written by WithRecording
+// instead of calling AWS. No credentials are needed.
+func WithReplay(dir string) Option {
+	return func(o *options) {
+		o.replayDir = dir
+	}
+}
+
+// WithReadOnly rejects every API call that could change AWS resources,
+// returning ErrReadOnly without contacting AWS.
+func WithReadOnly() Option {
+	return func(o *options) {
+		o.readOnly = true
+	}
+}
+
+func New(ctx context.Context, profile, region string, opts ...Option) (*AWSClient, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.recordDir != "" && o.replayDir != "" {
+		return nil, fmt.Errorf("recording and replay cannot be combined")
+	}
+
+	var loadOpts []func(*config.LoadOptions) error
+
+	if o.replayDir != "" {
+		// Replay never talks to AWS, so the recorded profile is only a label
+		// and requests go out unsigned
+		s, err := readSession(o.replayDir)
+		if err != nil {
+			return nil, err
+		}
+		if profile == "" {
+			profile = s.Profile
+		}
+		if region == "" {
+			region = s.Region
+		}
+		if region == "" {
+			region = "us-east-1"
+		}
+		loadOpts = append(loadOpts, config.WithCredentialsProvider(aws.AnonymousCredentials{}))
+	} else if profile != "" {
+		loadOpts = append(loadOpts, config.WithSharedConfigProfile(profile))
 	}
 
 	if region != "" {
-		opts = append(opts, config.WithRegion(region))
+		loadOpts = append(loadOpts, config.WithRegion(region))
 	}
 
-	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	cfg, err := config.LoadDefaultConfig(ctx, loadOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("unable to load SDK config: %w", err)
 	}
 
+	if o.readOnly {
+		cfg.APIOptions = append(cfg.APIOptions, addReadOnlyGuard)
+	}
+
+	// The SDK's own HTTP client is wrapped rather than replaced so settings
+	// such as AWS_CA_BUNDLE keep working
+	if o.replayDir != "" {
+		cfg.HTTPClient = &replayer{dir: o.replayDir}
+	}
+	if o.recordDir != "" {
+		rec, err := newRecorder(o.recordDir, cfg.HTTPClient)
+		if err != nil {
+			return nil, err
+		}
+		cfg.HTTPClient = rec
+		if err := writeSession(o.recordDir, session{Profile: profile, Region: cfg.Region}); err != nil {
+			return nil, fmt.Errorf("failed to write recording session: %w", err)
+		}
+	}
+
 	return &AWSClient{
 		Config:  cfg,
 		Profile: profile,
 		Region:  cfg.Region,
+		opts:    opts,
 	}, nil
 }
 
 func (c *AWSClient) SwitchProfile(ctx context.Context, profile string) error {
-	newClient, err := New(ctx, profile, c.Region)
+	newClient, err := New(ctx, profile, c.Region, c.opts...)
 	if err != nil {
 		return err
 	}
@@ -47,7 +129,7 @@ func (c *AWSClient) SwitchProfile(ctx context.Context, profile string) error {
 }
 
 func (c *AWSClient) SwitchRegion(ctx context.Context, region string) error {
-	newClient, err := New(ctx, c.Profile, region)
+	newClient, err := New(ctx, c.Profile, region, c.opts...)
 	if err != nil {
 		return err
 	}
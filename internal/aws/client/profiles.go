@@ -0,0 +1,226 @@
+package client
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Profile is a named profile from the shared AWS config and credentials files
+type Profile struct {
+	Name   string
+	Region string
+
+	// SSO settings, resolved through an [sso-session] section when present
+	SSOSession   string
+	SSOStartURL  string
+	SSOAccountID string
+	SSORoleName  string
+
+	// Assume-role settings
+	RoleARN           string
+	SourceProfile     string
+	CredentialSource  string
+	WebIdentityToken  string
+	CredentialProcess string
+
+	HasStaticKeys bool
+}
+
+// Kind summarises how the profile obtains credentials
+func (p Profile) Kind() string {
+	switch {
+	case p.SSOSession != "" || p.SSOStartURL != "":
+		return "sso"
+	case p.RoleARN != "" && p.WebIdentityToken != "":
+		return "web-identity"
+	case p.RoleARN != "":
+		return "assume-role"
+	case p.CredentialProcess != "":
+		return "process"
+	case p.HasStaticKeys:
+		return "static"
+	default:
+		return "config"
+	}
+}
+
+// Target describes the account or role the profile resolves to
+func (p Profile) Target() string {
+	switch {
+	case p.SSOAccountID != "" && p.SSORoleName != "":
+		return p.SSOAccountID + "/" + p.SSORoleName
+	case p.RoleARN != "":
+		source := p.SourceProfile
+		if source == "" {
+			source = p.CredentialSource
+		}
+		if source != "" {
+			return p.RoleARN + " via " + source
+		}
+		return p.RoleARN
+	case p.SSOAccountID != "":
+		return p.SSOAccountID
+	}
+	return ""
+}
+
+// ConfigFilePath returns the shared config file location, honouring AWS_CONFIG_FILE
+func ConfigFilePath() string {
+	if path := os.Getenv("AWS_CONFIG_FILE"); path != "" {
+		return path
+	}
+	return filepath.Join(awsDir(), "config")
+}
+
+// CredentialsFilePath returns the shared credentials file location, honouring
+// AWS_SHARED_CREDENTIALS_FILE
+func CredentialsFilePath() string {
+	if path := os.Getenv("AWS_SHARED_CREDENTIALS_FILE"); path != "" {
+		return path
+	}
+	return filepath.Join(awsDir(), "credentials")
+}
+
+func awsDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".aws"
+	}
+	return filepath.Join(home, ".aws")
+}
+
+// LoadProfiles merges the profiles declared in the shared config and
+// credentials files, sorted by name. Missing files are not an error.
+func LoadProfiles() ([]Profile, error) {
+	configSections, err := readINI(ConfigFilePath())
+	if err != nil {
+		return nil, err
+	}
+	credentialSections, err := readINI(CredentialsFilePath())
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := make(map[string]*Profile)
+	get := func(name string) *Profile {
+		p, ok := profiles[name]
+		if !ok {
+			p = &Profile{Name: name}
+			profiles[name] = p
+		}
+		return p
+	}
+
+	ssoSessions := make(map[string]map[string]string)
+	for _, section := range configSections {
+		switch {
+		case section.name == "default":
+			applyConfig(get("default"), section.values)
+		case strings.HasPrefix(section.name, "profile "):
+			name := strings.TrimSpace(strings.TrimPrefix(section.name, "profile "))
+			applyConfig(get(name), section.values)
+		case strings.HasPrefix(section.name, "sso-session "):
+			name := strings.TrimSpace(strings.TrimPrefix(section.name, "sso-session "))
+			ssoSessions[name] = section.values
+		}
+	}
+
+	for _, section := range credentialSections {
+		p := get(section.name)
+		if section.values["aws_access_key_id"] != "" {
+			p.HasStaticKeys = true
+		}
+		// Credentials files may also carry role settings
+		applyConfig(p, section.values)
+	}
+
+	result := make([]Profile, 0, len(profiles))
+	for _, p := range profiles {
+		if session, ok := ssoSessions[p.SSOSession]; ok && p.SSOStartURL == "" {
+			p.SSOStartURL = session["sso_start_url"]
+		}
+		result = append(result, *p)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result, nil
+}
+
+func applyConfig(p *Profile, values map[string]string) {
+	set := func(dst *string, key string) {
+		if v, ok := values[key]; ok {
+			*dst = v
+		}
+	}
+	set(&p.Region, "region")
+	set(&p.SSOSession, "sso_session")
+	set(&p.SSOStartURL, "sso_start_url")
+	set(&p.SSOAccountID, "sso_account_id")
+	set(&p.SSORoleName, "sso_role_name")
+	set(&p.RoleARN, "role_arn")
+	set(&p.SourceProfile, "source_profile")
+	set(&p.CredentialSource, "credential_source")
+	set(&p.WebIdentityToken, "web_identity_token_file")
+	set(&p.CredentialProcess, "credential_process")
+}
+
+type iniSection struct {
+	name   string
+	values map[string]string
+}
+
+// readINI parses the subset of INI used by the AWS shared files. Indented
+// lines belong to nested settings (such as s3 = ...) and are skipped.
+func readINI(path string) ([]iniSection, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var sections []iniSection
+	var current *iniSection
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		raw := scanner.Text()
+		line := strings.TrimSpace(raw)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			sections = append(sections, iniSection{
+				name:   strings.TrimSpace(line[1 : len(line)-1]),
+				values: make(map[string]string),
+			})
+			current = &sections[len(sections)-1]
+			continue
+		}
+
+		if current == nil || raw[0] == ' ' || raw[0] == '\t' {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		current.values[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return sections, nil
+}
@@ -0,0 +1,40 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// ErrReadOnly is returned for API calls blocked by read-only mode
+var ErrReadOnly = errors.New("blocked by read-only mode")
+
+// readOnlyPrefixes are the operation name prefixes that never change
+// resources. Generate* operations only start report jobs.
+var readOnlyPrefixes = []string{"Get", "List", "Describe", "Simulate", "Generate"}
+
+func isReadOnlyOperation(name string) bool {
+	for _, prefix := range readOnlyPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// addReadOnlyGuard rejects mutating operations before they are sent
+func addReadOnlyGuard(stack *middleware.Stack) error {
+	return stack.Serialize.Add(middleware.SerializeMiddlewareFunc("A3SReadOnlyGuard",
+		func(ctx context.Context, in middleware.SerializeInput, next middleware.SerializeHandler) (
+			middleware.SerializeOutput, middleware.Metadata, error,
+		) {
+			if op := awsmiddleware.GetOperationName(ctx); !isReadOnlyOperation(op) {
+				return middleware.SerializeOutput{}, middleware.Metadata{}, ErrReadOnly
+			}
+			return next.HandleSerialize(ctx, in)
+		},
+	), middleware.Before)
+}
@@ -0,0 +1,217 @@
+package client
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// sessionFile holds the profile and region a recording was made with, so a
+// replay shows the same context as the original session.
+const sessionFile = "session.json"
+
+type session struct {
+	Profile string `json:"profile"`
+	Region  string `json:"region"`
+}
+
+// exchange is a single recorded API call as stored on disk.
+type exchange struct {
+	Service string      `json:"service"`
+	Action  string      `json:"action"`
+	Request string      `json:"request"`
+	Status  int         `json:"status"`
+	Header  http.Header `json:"header"`
+	Body    string      `json:"body"`
+}
+
+// recorder wraps the SDK's HTTP client and writes every response to dir.
+type recorder struct {
+	dir  string
+	next aws.HTTPClient
+	mu   sync.Mutex
+}
+
+func newRecorder(dir string, next aws.HTTPClient) (*recorder, error) {
+	// Recordings hold account data such as ARNs and policies, so they are
+	// readable by the owner only
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create recording directory: %w", err)
+	}
+	return &recorder{dir: dir, next: next}, nil
+}
+
+func (t *recorder) Do(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.next.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("record: failed to read response: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	service, action, key := exchangeKey(req, body)
+	ex := exchange{
+		Service: service,
+		Action:  action,
+		Request: string(body),
+		Status:  resp.StatusCode,
+		Header:  resp.Header.Clone(),
+		Body:    string(respBody),
+	}
+
+	data, err := json.MarshalIndent(ex, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("record: failed to encode exchange: %w", err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := os.WriteFile(filepath.Join(t.dir, key+".json"), data, 0o600); err != nil {
+		return nil, fmt.Errorf("record: failed to write exchange: %w", err)
+	}
+
+	return resp, nil
+}
+
+// replayer answers requests from a directory written by recorder without
+// touching the network.
+type replayer struct {
+	dir string
+}
+
+func (t *replayer) Do(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	_, action, key := exchangeKey(req, body)
+	data, err := os.ReadFile(filepath.Join(t.dir, key+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		// Answer with a non-retryable API error rather than a transport
+		// error so the SDK fails fast with a readable message
+		return replayMiss(req, action, key), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("replay: %w", err)
+	}
+
+	var ex exchange
+	if err := json.Unmarshal(data, &ex); err != nil {
+		return nil, fmt.Errorf("replay: failed to decode %s: %w", key, err)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", ex.Status, http.StatusText(ex.Status)),
+		StatusCode:    ex.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        ex.Header,
+		Body:          io.NopCloser(strings.NewReader(ex.Body)),
+		ContentLength: int64(len(ex.Body)),
+		Request:       req,
+	}, nil
+}
+
+func replayMiss(req *http.Request, action, key string) *http.Response {
+	body := fmt.Sprintf(`<ErrorResponse><Error><Type>Sender</Type><Code>ReplayMiss</Code>`+
+		`<Message>no recorded response for %s (%s.json)</Message></Error></ErrorResponse>`, action, key)
+	header := http.Header{}
+	header.Set("Content-Type", "text/xml")
+	return &http.Response{
+		Status:        "400 Bad Request",
+		StatusCode:    http.StatusBadRequest,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// readRequestBody drains the request body and puts it back so the request
+// can still be sent.
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	return body, nil
+}
+
+// exchangeKey derives a stable file name for a request. IAM and STS use the
+// query protocol, so the form body (action, parameters and pagination marker)
+// identifies the call; the host is reduced to the service name so recordings
+// replay regardless of the endpoint region.
+func exchangeKey(req *http.Request, body []byte) (service, action, key string) {
+	service = strings.SplitN(req.URL.Hostname(), ".", 2)[0]
+	if form, err := url.ParseQuery(string(body)); err == nil {
+		action = form.Get("Action")
+	}
+	if action == "" {
+		action = "Request"
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n%s\n%s\n", service, req.Method, req.URL.Path, req.URL.RawQuery)
+	h.Write(body)
+	sum := hex.EncodeToString(h.Sum(nil))
+
+	return service, action, fmt.Sprintf("%s-%s-%s", service, action, sum[:12])
+}
+
+func writeSession(dir string, s session) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, sessionFile), data, 0o600)
+}
+
+func readSession(dir string) (session, error) {
+	var s session
+	data, err := os.ReadFile(filepath.Join(dir, sessionFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, fmt.Errorf("failed to read replay session: %w", err)
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return s, fmt.Errorf("failed to parse replay session: %w", err)
+	}
+	return s, nil
+}
@@ -0,0 +1,86 @@
+package client
+
+// Partition is an AWS partition and the regions it contains
+type Partition struct {
+	ID      string
+	Name    string
+	Regions []Region
+}
+
+// Region is a region code with its display name
+type Region struct {
+	Code string
+	Name string
+}
+
+// Partitions is a static table of the public AWS partitions. Regions that
+// require opt-in are included; they only answer once enabled on the account.
+var Partitions = []Partition{
+	{
+		ID:   "aws",
+		Name: "AWS Commercial",
+		Regions: []Region{
+			{"us-east-1", "US East (N. Virginia)"},
+			{"us-east-2", "US East (Ohio)"},
+			{"us-west-1", "US West (N. California)"},
+			{"us-west-2", "US West (Oregon)"},
+			{"af-south-1", "Africa (Cape Town)"},
+			{"ap-east-1", "Asia Pacific (Hong Kong)"},
+			{"ap-south-1", "Asia Pacific (Mumbai)"},
+			{"ap-south-2", "Asia Pacific (Hyderabad)"},
+			{"ap-southeast-1", "Asia Pacific (Singapore)"},
+			{"ap-southeast-2", "Asia Pacific (Sydney)"},
+			{"ap-southeast-3", "Asia Pacific (Jakarta)"},
+			{"ap-southeast-4", "Asia Pacific (Melbourne)"},
+			{"ap-southeast-5", "Asia Pacific (Malaysia)"},
+			{"ap-southeast-7", "Asia Pacific (Thailand)"},
+			{"ap-northeast-1", "Asia Pacific (Tokyo)"},
+			{"ap-northeast-2", "Asia Pacific (Seoul)"},
+			{"ap-northeast-3", "Asia Pacific (Osaka)"},
+			{"ca-central-1", "Canada (Central)"},
+			{"ca-west-1", "Canada West (Calgary)"},
+			{"eu-central-1", "Europe (Frankfurt)"},
+			{"eu-central-2", "Europe (Zurich)"},
+			{"eu-west-1", "Europe (Ireland)"},
+			{"eu-west-2", "Europe (London)"},
+			{"eu-west-3", "Europe (Paris)"},
+			{"eu-south-1", "Europe (Milan)"},
+			{"eu-south-2", "Europe (Spain)"},
+			{"eu-north-1", "Europe (Stockholm)"},
+			{"il-central-1", "Israel (Tel Aviv)"},
+			{"me-south-1", "Middle East (Bahrain)"},
+			{"me-central-1", "Middle East (UAE)"},
+			{"mx-central-1", "Mexico (Central)"},
+			{"sa-east-1", "South America (São Paulo)"},
+		},
+	},
+	{
+		ID:   "aws-us-gov",
+		Name: "AWS GovCloud (US)",
+		Regions: []Region{
+			{"us-gov-east-1", "AWS GovCloud (US-East)"},
+			{"us-gov-west-1", "AWS GovCloud (US-West)"},
+		},
+	},
+	{
+		ID:   "aws-cn",
+		Name: "AWS China",
+		Regions: []Region{
+			{"cn-north-1", "China (Beijing)"},
+			{"cn-northwest-1", "China (Ningxia)"},
+		},
+	},
+}
+
+// PartitionOf returns the partition containing region, defaulting to the
+// commercial partition for unknown regions
+func PartitionOf(region string) Partition {
+	for _, p := range Partitions {
+		for _, r := range p.Regions {
+			if r.Code == region {
+				return p
+			}
+		}
+	}
+	return Partitions[0]
+}
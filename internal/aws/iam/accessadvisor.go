@@ -0,0 +1,105 @@
+package iam
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+// accessAdvisorPollInterval is the delay between checks of a running
+// service last accessed job
+const accessAdvisorPollInterval = 2 * time.Second
+
+// ServiceAccess is the last accessed information for one service a
+// principal's policies grant access to
+type ServiceAccess struct {
+	ServiceName string
+	Namespace   string
+	// LastAccessed is nil when the service was not used in the tracking period
+	LastAccessed *time.Time
+	// LastAccessedEntity is the ARN of the role or user that accessed the
+	// service, LastAccessedRegion where it did so
+	LastAccessedEntity string
+	LastAccessedRegion string
+}
+
+// Used reports whether the service was accessed in the tracking period
+func (s ServiceAccess) Used() bool {
+	return s.LastAccessed != nil
+}
+
+// SortServiceAccess orders services most recently used first, followed by
+// the never used ones by name
+func SortServiceAccess(services []ServiceAccess) {
+	sort.SliceStable(services, func(i, j int) bool {
+		a, b := services[i], services[j]
+		if a.Used() != b.Used() {
+			return a.Used()
+		}
+		if a.Used() && !a.LastAccessed.Equal(*b.LastAccessed) {
+			return a.LastAccessed.After(*b.LastAccessed)
+		}
+		return strings.ToLower(a.ServiceName) < strings.ToLower(b.ServiceName)
+	})
+}
+
+// GetServiceLastAccessed starts a service last accessed job for the role and
+// polls it until the report is ready. It lists every service the role's
+// policies grant, used or not. Cancel ctx to stop waiting.
+func (s *RoleService) GetServiceLastAccessed(ctx context.Context, roleArn string) ([]ServiceAccess, error) {
+	job, err := s.client.GenerateServiceLastAccessedDetails(ctx, &iam.GenerateServiceLastAccessedDetailsInput{
+		Arn:         &roleArn,
+		Granularity: types.AccessAdvisorUsageGranularityTypeServiceLevel,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate service last accessed details: %w", err)
+	}
+
+	var services []ServiceAccess
+	input := &iam.GetServiceLastAccessedDetailsInput{JobId: job.JobId}
+	for {
+		output, err := s.client.GetServiceLastAccessedDetails(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get service last accessed details: %w", err)
+		}
+
+		switch output.JobStatus {
+		case types.JobStatusTypeInProgress:
+			select {
+			case <-ctx.Done():
+				return nil, fmt.Errorf("failed to get service last accessed details: %w", ctx.Err())
+			case <-time.After(accessAdvisorPollInterval):
+			}
+			continue
+		case types.JobStatusTypeFailed:
+			reason := "job failed"
+			if output.Error != nil {
+				reason = aws.ToString(output.Error.Message)
+			}
+			return nil, fmt.Errorf("failed to get service last accessed details: %s", reason)
+		}
+
+		for _, svc := range output.ServicesLastAccessed {
+			services = append(services, ServiceAccess{
+				ServiceName:        aws.ToString(svc.ServiceName),
+				Namespace:          aws.ToString(svc.ServiceNamespace),
+				LastAccessed:       svc.LastAuthenticated,
+				LastAccessedEntity: aws.ToString(svc.LastAuthenticatedEntity),
+				LastAccessedRegion: aws.ToString(svc.LastAuthenticatedRegion),
+			})
+		}
+		if !output.IsTruncated {
+			break
+		}
+		input.Marker = output.Marker
+	}
+
+	SortServiceAccess(services)
+	return services, nil
+}
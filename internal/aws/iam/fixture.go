@@ -0,0 +1,935 @@
+package iam
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/johnoct/a3s/internal/aws/identity"
+	"github.com/johnoct/a3s/internal/policy"
+)
+
+// Fixture is the on-disk format of an offline IAM account. It is loaded with
+// LoadFixture and served by FixtureBackend.
+type Fixture struct {
+	Identity *FixtureIdentity `json:"identity,omitempty"`
+	Roles    []FixtureRole    `json:"roles"`
+	Users    []FixtureUser    `json:"users,omitempty"`
+	Groups   []FixtureGroup   `json:"groups,omitempty"`
+	Policies []FixturePolicy  `json:"policies,omitempty"`
+
+	InstanceProfiles []FixtureInstanceProfile `json:"instanceProfiles,omitempty"`
+	OIDCProviders    []FixtureOIDCProvider    `json:"oidcProviders,omitempty"`
+	SAMLProviders    []FixtureSAMLProvider    `json:"samlProviders,omitempty"`
+}
+
+// FixtureIdentity is the caller identity reported in fixture mode.
+type FixtureIdentity struct {
+	Account string `json:"account"`
+	UserID  string `json:"userId"`
+	ARN     string `json:"arn"`
+}
+
+// FixtureRole describes a role together with its tags and policies.
+// Policy documents may be given either as JSON objects or as JSON strings.
+type FixtureRole struct {
+	Name               string                     `json:"name"`
+	ARN                string                     `json:"arn"`
+	RoleID             string                     `json:"roleId"`
+	Path               string                     `json:"path"`
+	CreateDate         time.Time                  `json:"createDate"`
+	Description        string                     `json:"description,omitempty"`
+	MaxSessionDuration int32                      `json:"maxSessionDuration,omitempty"`
+	LastUsed           *time.Time                 `json:"lastUsed,omitempty"`
+	LastUsedRegion     string                     `json:"lastUsedRegion,omitempty"`
+	Tags               []Tag                      `json:"tags,omitempty"`
+	TrustPolicy        json.RawMessage            `json:"trustPolicy,omitempty"`
+	ManagedPolicies    []string                   `json:"managedPolicies,omitempty"`
+	InlinePolicies     map[string]json.RawMessage `json:"inlinePolicies,omitempty"`
+	// PermissionsBoundary is the ARN of the role's boundary policy
+	PermissionsBoundary string `json:"permissionsBoundary,omitempty"`
+	// ServiceLastAccessed records service use by namespace. Services the
+	// role's policies grant but that are not listed were never used.
+	ServiceLastAccessed map[string]FixtureServiceAccess `json:"serviceLastAccessed,omitempty"`
+}
+
+// FixtureServiceAccess is the last use of one service by a FixtureRole
+type FixtureServiceAccess struct {
+	ServiceName  string     `json:"serviceName,omitempty"`
+	LastAccessed *time.Time `json:"lastAccessed,omitempty"`
+	Region       string     `json:"region,omitempty"`
+}
+
+// FixtureUser describes a user with its credentials, groups and policies.
+type FixtureUser struct {
+	Name             string                     `json:"name"`
+	ARN              string                     `json:"arn"`
+	UserID           string                     `json:"userId"`
+	Path             string                     `json:"path"`
+	CreateDate       time.Time                  `json:"createDate"`
+	PasswordLastUsed *time.Time                 `json:"passwordLastUsed,omitempty"`
+	Tags             []Tag                      `json:"tags,omitempty"`
+	Groups           []string                   `json:"groups,omitempty"`
+	ManagedPolicies  []string                   `json:"managedPolicies,omitempty"`
+	InlinePolicies   map[string]json.RawMessage `json:"inlinePolicies,omitempty"`
+	AccessKeys       []FixtureAccessKey         `json:"accessKeys,omitempty"`
+	MFADevices       []MFADevice                `json:"mfaDevices,omitempty"`
+	LoginProfile     *LoginProfile              `json:"loginProfile,omitempty"`
+}
+
+// FixtureAccessKey is an access key of a FixtureUser.
+type FixtureAccessKey struct {
+	ID              string     `json:"id"`
+	Status          string     `json:"status"`
+	CreateDate      time.Time  `json:"createDate"`
+	LastUsed        *time.Time `json:"lastUsed,omitempty"`
+	LastUsedService string     `json:"lastUsedService,omitempty"`
+	LastUsedRegion  string     `json:"lastUsedRegion,omitempty"`
+}
+
+// FixtureGroup describes a group and its policies. Members are taken from
+// the groups listed on each FixtureUser.
+type FixtureGroup struct {
+	Name            string                     `json:"name"`
+	ARN             string                     `json:"arn"`
+	GroupID         string                     `json:"groupId"`
+	Path            string                     `json:"path"`
+	CreateDate      time.Time                  `json:"createDate"`
+	ManagedPolicies []string                   `json:"managedPolicies,omitempty"`
+	InlinePolicies  map[string]json.RawMessage `json:"inlinePolicies,omitempty"`
+}
+
+// FixtureInstanceProfile describes an instance profile and the roles it
+// contains, referenced by name.
+type FixtureInstanceProfile struct {
+	Name              string    `json:"name"`
+	ARN               string    `json:"arn"`
+	InstanceProfileID string    `json:"instanceProfileId"`
+	Path              string    `json:"path"`
+	CreateDate        time.Time `json:"createDate"`
+	Roles             []string  `json:"roles,omitempty"`
+	Tags              []Tag     `json:"tags,omitempty"`
+}
+
+// FixtureOIDCProvider describes an OpenID Connect identity provider. URL
+// defaults to the host and path in the ARN.
+type FixtureOIDCProvider struct {
+	ARN         string    `json:"arn"`
+	URL         string    `json:"url,omitempty"`
+	ClientIDs   []string  `json:"clientIds,omitempty"`
+	Thumbprints []string  `json:"thumbprints,omitempty"`
+	CreateDate  time.Time `json:"createDate"`
+	Tags        []Tag     `json:"tags,omitempty"`
+}
+
+// FixtureSAMLProvider describes a SAML identity provider
+type FixtureSAMLProvider struct {
+	ARN        string    `json:"arn"`
+	CreateDate time.Time `json:"createDate"`
+	ValidUntil time.Time `json:"validUntil"`
+	Tags       []Tag     `json:"tags,omitempty"`
+}
+
+// FixturePolicy is a managed policy referenced by ARN from the
+// ManagedPolicies of roles, users and groups. A policy has either a single
+// Document, served as version v1, or a list of Versions.
+type FixturePolicy struct {
+	Name        string                 `json:"name"`
+	ARN         string                 `json:"arn"`
+	PolicyID    string                 `json:"policyId,omitempty"`
+	Path        string                 `json:"path,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	CreateDate  time.Time              `json:"createDate,omitempty"`
+	UpdateDate  time.Time              `json:"updateDate,omitempty"`
+	Document    json.RawMessage        `json:"document,omitempty"`
+	Versions    []FixturePolicyVersion `json:"versions,omitempty"`
+	// DefaultVersion names the default entry of Versions; it defaults to the
+	// newest (first) one
+	DefaultVersion string `json:"defaultVersion,omitempty"`
+}
+
+// FixturePolicyVersion is one version of a FixturePolicy, newest first.
+type FixturePolicyVersion struct {
+	ID         string          `json:"id"`
+	CreateDate time.Time       `json:"createDate"`
+	Document   json.RawMessage `json:"document"`
+}
+
+// FixtureBackend serves IAM data from memory. It needs no AWS credentials and
+// is used for demos, local development and tests.
+type FixtureBackend struct {
+	identity *identity.Identity
+	roles    []Role
+	byName   map[string]int
+	inline   map[string]map[string]string
+	managed  map[string]string
+	access   map[string]map[string]FixtureServiceAccess
+
+	users      []User
+	userByName map[string]int
+	userInline map[string]map[string]string
+
+	groups      []Group
+	groupByName map[string]int
+	groupInline map[string]map[string]string
+
+	policies    []Policy
+	policyByARN map[string]int
+	versions    map[string]map[string]string
+
+	instanceProfiles []InstanceProfile
+	profileByName    map[string]int
+
+	oidcProviders []OIDCProvider
+	samlProviders []SAMLProvider
+}
+
+var (
+	_ RoleAPI   = (*FixtureBackend)(nil)
+	_ UserAPI   = (*FixtureBackend)(nil)
+	_ GroupAPI  = (*FixtureBackend)(nil)
+	_ PolicyAPI = (*FixtureBackend)(nil)
+
+	_ InstanceProfileAPI = (*FixtureBackend)(nil)
+	_ ProviderAPI        = (*FixtureBackend)(nil)
+)
+
+// LoadFixture reads a JSON fixture file and returns a backend serving it.
+func LoadFixture(path string) (*FixtureBackend, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+
+	var f Fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+	}
+
+	return NewFixtureBackend(&f)
+}
+
+// NewFixtureBackend builds a backend from an in-memory fixture.
+func NewFixtureBackend(f *Fixture) (*FixtureBackend, error) {
+	b := &FixtureBackend{
+		byName:     make(map[string]int),
+		inline:     make(map[string]map[string]string),
+		access:     make(map[string]map[string]FixtureServiceAccess),
+		managed:    make(map[string]string),
+		userByName: make(map[string]int),
+		userInline: make(map[string]map[string]string),
+
+		groupByName: make(map[string]int),
+		groupInline: make(map[string]map[string]string),
+
+		policyByARN: make(map[string]int),
+		versions:    make(map[string]map[string]string),
+
+		profileByName: make(map[string]int),
+	}
+
+	if f.Identity != nil {
+		b.identity = identity.New(f.Identity.Account, f.Identity.UserID, f.Identity.ARN)
+	}
+
+	policyNames := make(map[string]string)
+	for _, fp := range f.Policies {
+		if fp.ARN == "" {
+			return nil, fmt.Errorf("fixture policy %q has no ARN", fp.Name)
+		}
+		if _, dup := b.policyByARN[fp.ARN]; dup {
+			return nil, fmt.Errorf("fixture policy %s is defined twice", fp.ARN)
+		}
+
+		policy, docs, err := fixturePolicy(fp)
+		if err != nil {
+			return nil, err
+		}
+		b.managed[fp.ARN] = docs[policy.DefaultVersion]
+		b.versions[fp.ARN] = docs
+		policyNames[fp.ARN] = fp.Name
+
+		b.policyByARN[fp.ARN] = len(b.policies)
+		b.policies = append(b.policies, policy)
+	}
+
+	for _, fr := range f.Roles {
+		if fr.Name == "" {
+			return nil, fmt.Errorf("fixture role with ARN %q has no name", fr.ARN)
+		}
+		if _, dup := b.byName[fr.Name]; dup {
+			return nil, fmt.Errorf("fixture role %q is defined twice", fr.Name)
+		}
+
+		role := Role{
+			Name:               fr.Name,
+			ARN:                fr.ARN,
+			CreateDate:         fr.CreateDate,
+			Description:        fr.Description,
+			MaxSessionDuration: fr.MaxSessionDuration,
+			Path:               fr.Path,
+			RoleID:             fr.RoleID,
+			Tags:               fr.Tags,
+			TrustPolicy:        fixtureDocument(fr.TrustPolicy),
+			LastUsed:           fr.LastUsed,
+			LastUsedRegion:     fr.LastUsedRegion,
+		}
+		if role.Path == "" {
+			role.Path = "/"
+		}
+		if role.MaxSessionDuration == 0 {
+			role.MaxSessionDuration = 3600
+		}
+
+		role.ManagedPolicies = fixturePolicyInfos(fr.ManagedPolicies, policyNames)
+		b.attach(fr.ManagedPolicies, func(p *Policy) { p.AttachedRoles = append(p.AttachedRoles, fr.Name) })
+		role.InlinePolicies, b.inline[role.Name] = fixtureInlinePolicies(fr.InlinePolicies)
+		if fr.PermissionsBoundary != "" {
+			role.PermissionsBoundary = &fixturePolicyInfos([]string{fr.PermissionsBoundary}, policyNames)[0]
+			if i, ok := b.policyByARN[fr.PermissionsBoundary]; ok {
+				b.policies[i].BoundaryCount++
+			}
+		}
+
+		b.access[role.Name] = fr.ServiceLastAccessed
+
+		b.byName[role.Name] = len(b.roles)
+		b.roles = append(b.roles, role)
+	}
+
+	for _, fg := range f.Groups {
+		if fg.Name == "" {
+			return nil, fmt.Errorf("fixture group with ARN %q has no name", fg.ARN)
+		}
+		if _, dup := b.groupByName[fg.Name]; dup {
+			return nil, fmt.Errorf("fixture group %q is defined twice", fg.Name)
+		}
+
+		group := Group{
+			Name:       fg.Name,
+			ARN:        fg.ARN,
+			GroupID:    fg.GroupID,
+			Path:       fg.Path,
+			CreateDate: fg.CreateDate,
+		}
+		if group.Path == "" {
+			group.Path = "/"
+		}
+
+		group.ManagedPolicies = fixturePolicyInfos(fg.ManagedPolicies, policyNames)
+		b.attach(fg.ManagedPolicies, func(p *Policy) { p.AttachedGroups = append(p.AttachedGroups, fg.Name) })
+		group.InlinePolicies, b.groupInline[group.Name] = fixtureInlinePolicies(fg.InlinePolicies)
+
+		b.groupByName[group.Name] = len(b.groups)
+		b.groups = append(b.groups, group)
+	}
+
+	for _, fu := range f.Users {
+		if fu.Name == "" {
+			return nil, fmt.Errorf("fixture user with ARN %q has no name", fu.ARN)
+		}
+		if _, dup := b.userByName[fu.Name]; dup {
+			return nil, fmt.Errorf("fixture user %q is defined twice", fu.Name)
+		}
+
+		user := User{
+			Name:             fu.Name,
+			ARN:              fu.ARN,
+			UserID:           fu.UserID,
+			Path:             fu.Path,
+			CreateDate:       fu.CreateDate,
+			PasswordLastUsed: fu.PasswordLastUsed,
+			Tags:             fu.Tags,
+			Groups:           fu.Groups,
+			MFADevices:       fu.MFADevices,
+			LoginProfile:     fu.LoginProfile,
+		}
+		if user.Path == "" {
+			user.Path = "/"
+		}
+		for _, k := range fu.AccessKeys {
+			user.AccessKeys = append(user.AccessKeys, AccessKey(k))
+		}
+		for _, g := range fu.Groups {
+			i, ok := b.groupByName[g]
+			if !ok {
+				return nil, fmt.Errorf("fixture user %q is a member of undefined group %q", fu.Name, g)
+			}
+			b.groups[i].Members = append(b.groups[i].Members, fu.Name)
+		}
+
+		user.ManagedPolicies = fixturePolicyInfos(fu.ManagedPolicies, policyNames)
+		b.attach(fu.ManagedPolicies, func(p *Policy) { p.AttachedUsers = append(p.AttachedUsers, fu.Name) })
+		user.InlinePolicies, b.userInline[user.Name] = fixtureInlinePolicies(fu.InlinePolicies)
+
+		b.userByName[user.Name] = len(b.users)
+		b.users = append(b.users, user)
+	}
+
+	for _, fp := range f.InstanceProfiles {
+		if fp.Name == "" {
+			return nil, fmt.Errorf("fixture instance profile with ARN %q has no name", fp.ARN)
+		}
+		if _, dup := b.profileByName[fp.Name]; dup {
+			return nil, fmt.Errorf("fixture instance profile %q is defined twice", fp.Name)
+		}
+
+		profile := InstanceProfile(fp)
+		if profile.Path == "" {
+			profile.Path = "/"
+		}
+		for _, r := range fp.Roles {
+			if _, ok := b.byName[r]; !ok {
+				return nil, fmt.Errorf("fixture instance profile %q contains undefined role %q", fp.Name, r)
+			}
+		}
+
+		b.profileByName[profile.Name] = len(b.instanceProfiles)
+		b.instanceProfiles = append(b.instanceProfiles, profile)
+	}
+	// Roles reference their profiles, like ListInstanceProfilesForRole
+	for _, p := range b.instanceProfiles {
+		for _, r := range p.Roles {
+			i := b.byName[r]
+			b.roles[i].InstanceProfiles = append(b.roles[i].InstanceProfiles, p)
+		}
+	}
+
+	for _, fp := range f.OIDCProviders {
+		provider := OIDCProvider(fp)
+		if provider.URL == "" {
+			provider.URL = ProviderName(fp.ARN)
+		}
+		b.oidcProviders = append(b.oidcProviders, provider)
+	}
+	for _, fp := range f.SAMLProviders {
+		b.samlProviders = append(b.samlProviders, SAMLProvider{
+			ARN:        fp.ARN,
+			Name:       ProviderName(fp.ARN),
+			CreateDate: fp.CreateDate,
+			ValidUntil: fp.ValidUntil,
+			Tags:       fp.Tags,
+		})
+	}
+
+	return b, nil
+}
+
+// fixturePolicy converts a fixture policy and returns its version documents
+// keyed by version ID
+func fixturePolicy(fp FixturePolicy) (Policy, map[string]string, error) {
+	policy := Policy{
+		Name:        fp.Name,
+		ARN:         fp.ARN,
+		PolicyID:    fp.PolicyID,
+		Path:        fp.Path,
+		Description: fp.Description,
+		CreateDate:  fp.CreateDate,
+		UpdateDate:  fp.UpdateDate,
+	}
+	if policy.Path == "" {
+		policy.Path = "/"
+	}
+	if policy.UpdateDate.IsZero() {
+		policy.UpdateDate = policy.CreateDate
+	}
+
+	versions := fp.Versions
+	if len(versions) == 0 {
+		versions = []FixturePolicyVersion{{ID: "v1", CreateDate: fp.CreateDate, Document: fp.Document}}
+	}
+	policy.DefaultVersion = fp.DefaultVersion
+	if policy.DefaultVersion == "" {
+		policy.DefaultVersion = versions[0].ID
+	}
+
+	docs := make(map[string]string, len(versions))
+	for _, v := range versions {
+		docs[v.ID] = fixtureDocument(v.Document)
+		policy.Versions = append(policy.Versions, PolicyVersion{
+			ID:         v.ID,
+			IsDefault:  v.ID == policy.DefaultVersion,
+			CreateDate: v.CreateDate,
+		})
+	}
+	if _, ok := docs[policy.DefaultVersion]; !ok {
+		return Policy{}, nil, fmt.Errorf("fixture policy %s has no version %s", fp.ARN, policy.DefaultVersion)
+	}
+	return policy, docs, nil
+}
+
+// attach records an attachment on every fixture policy in arns
+func (b *FixtureBackend) attach(arns []string, add func(p *Policy)) {
+	for _, arn := range arns {
+		if i, ok := b.policyByARN[arn]; ok {
+			add(&b.policies[i])
+			b.policies[i].AttachmentCount++
+		}
+	}
+}
+
+// fixturePolicyInfos resolves managed policy ARNs to names, falling back to
+// the last ARN segment for policies the fixture does not define
+func fixturePolicyInfos(arns []string, names map[string]string) []PolicyInfo {
+	var infos []PolicyInfo
+	for _, arn := range arns {
+		name := names[arn]
+		if name == "" {
+			name = arn[strings.LastIndex(arn, "/")+1:]
+		}
+		infos = append(infos, PolicyInfo{Name: name, ARN: arn})
+	}
+	return infos
+}
+
+// fixtureInlinePolicies returns the sorted inline policy names and their documents
+func fixtureInlinePolicies(policies map[string]json.RawMessage) ([]string, map[string]string) {
+	names := make([]string, 0, len(policies))
+	docs := make(map[string]string, len(policies))
+	for name, doc := range policies {
+		docs[name] = fixtureDocument(doc)
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, docs
+}
+
+// Identity returns the caller identity declared in the fixture, or nil.
+func (b *FixtureBackend) Identity() *identity.Identity {
+	return b.identity
+}
+
+func (b *FixtureBackend) ListRoles(ctx context.Context) ([]Role, error) {
+	// Like the real ListRoles call, only summary fields are returned; the
+	// last use comes from GetRole
+	roles := make([]Role, len(b.roles))
+	for i, r := range b.roles {
+		r.LastUsed = nil
+		r.LastUsedRegion = ""
+		r.Tags = nil
+		r.ManagedPolicies = nil
+		r.InlinePolicies = nil
+		r.InstanceProfiles = nil
+		r.PermissionsBoundary = nil
+		roles[i] = r
+	}
+	return roles, nil
+}
+
+// RefreshRoles lists roles like ListRoles; the fixture has no snapshot
+func (b *FixtureBackend) RefreshRoles(ctx context.Context) ([]Role, error) {
+	return b.ListRoles(ctx)
+}
+
+// ListRoleSummaries lists roles like ListRoles; the fixture has no snapshot
+func (b *FixtureBackend) ListRoleSummaries(ctx context.Context) ([]Role, error) {
+	return b.ListRoles(ctx)
+}
+
+func (b *FixtureBackend) GetRole(ctx context.Context, roleName string) (*Role, error) {
+	i, ok := b.byName[roleName]
+	if !ok {
+		return nil, fmt.Errorf("failed to get role: role %q not found", roleName)
+	}
+	role := b.roles[i]
+	role.Tags = nil
+	role.ManagedPolicies = nil
+	role.InlinePolicies = nil
+	role.InstanceProfiles = nil
+	return &role, nil
+}
+
+func (b *FixtureBackend) GetRoleDetails(ctx context.Context, roleName string) (*Role, error) {
+	i, ok := b.byName[roleName]
+	if !ok {
+		return nil, fmt.Errorf("failed to get role: role %q not found", roleName)
+	}
+	role := b.roles[i]
+	return &role, nil
+}
+
+func (b *FixtureBackend) GetInlinePolicy(ctx context.Context, roleName, policyName string) (string, error) {
+	doc, ok := b.inline[roleName][policyName]
+	if !ok {
+		return "", fmt.Errorf("failed to get inline policy: %q not found on role %q", policyName, roleName)
+	}
+	return doc, nil
+}
+
+func (b *FixtureBackend) GetManagedPolicyDocument(ctx context.Context, policyArn string) (string, error) {
+	doc, ok := b.managed[policyArn]
+	if !ok {
+		return "", fmt.Errorf("failed to get policy: %s not found", policyArn)
+	}
+	return doc, nil
+}
+
+func (b *FixtureBackend) GetServiceLastAccessed(ctx context.Context, roleArn string) ([]ServiceAccess, error) {
+	var role *Role
+	for i := range b.roles {
+		if b.roles[i].ARN == roleArn {
+			role = &b.roles[i]
+		}
+	}
+	if role == nil {
+		return nil, fmt.Errorf("failed to generate service last accessed details: role %s not found", roleArn)
+	}
+	access := b.access[role.Name]
+
+	// Like the real report, list every service the role's policies grant;
+	// a "*" grant covers the services the fixture records use of
+	var documents []string
+	for _, p := range role.ManagedPolicies {
+		documents = append(documents, b.managed[p.ARN])
+	}
+	for _, doc := range b.inline[role.Name] {
+		documents = append(documents, doc)
+	}
+	granted := make(map[string]bool)
+	for _, document := range documents {
+		doc, err := policy.Parse(document)
+		if err != nil {
+			continue
+		}
+		actions, _ := doc.GrantedActions()
+		for _, action := range actions {
+			namespace, _, found := strings.Cut(action, ":")
+			if !found || namespace == "*" {
+				for ns := range access {
+					granted[ns] = true
+				}
+				continue
+			}
+			granted[strings.ToLower(namespace)] = true
+		}
+	}
+
+	var services []ServiceAccess
+	for namespace := range granted {
+		a := access[namespace]
+		service := ServiceAccess{
+			ServiceName:  a.ServiceName,
+			Namespace:    namespace,
+			LastAccessed: a.LastAccessed,
+		}
+		if service.ServiceName == "" {
+			service.ServiceName = namespace
+		}
+		if a.LastAccessed != nil {
+			service.LastAccessedEntity = role.ARN
+			service.LastAccessedRegion = a.Region
+		}
+		services = append(services, service)
+	}
+	SortServiceAccess(services)
+	return services, nil
+}
+
+// SimulatePrincipalPolicy emulates the policy simulator by evaluating the
+// role's identity policies and boundary locally
+func (b *FixtureBackend) SimulatePrincipalPolicy(ctx context.Context, roleArn string, sim Simulation) ([]SimulationResult, error) {
+	var role *Role
+	for i := range b.roles {
+		if b.roles[i].ARN == roleArn {
+			role = &b.roles[i]
+		}
+	}
+	if role == nil {
+		return nil, fmt.Errorf("failed to simulate principal policy: role %s not found", roleArn)
+	}
+
+	type source struct {
+		id, typ, document string
+	}
+	var sources []source
+	var policies []policy.Policy
+	add := func(s source) error {
+		doc, err := policy.Parse(s.document)
+		if err != nil {
+			return fmt.Errorf("failed to simulate principal policy: %s: %w", s.id, err)
+		}
+		sources = append(sources, s)
+		policies = append(policies, policy.Policy{Name: s.id, Document: doc})
+		return nil
+	}
+	for _, p := range role.ManagedPolicies {
+		typ := "user-managed"
+		if IsAWSManagedPolicy(p.ARN) {
+			typ = "aws-managed"
+		}
+		if err := add(source{p.Name, typ, b.managed[p.ARN]}); err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range role.InlinePolicies {
+		if err := add(source{name, "role", b.inline[role.Name][name]}); err != nil {
+			return nil, err
+		}
+	}
+	var boundary []policy.Policy
+	if role.PermissionsBoundary != nil {
+		doc, err := policy.Parse(b.managed[role.PermissionsBoundary.ARN])
+		if err != nil {
+			return nil, fmt.Errorf("failed to simulate principal policy: %s: %w", role.PermissionsBoundary.Name, err)
+		}
+		boundary = []policy.Policy{{Name: role.PermissionsBoundary.Name, Document: doc}}
+	}
+
+	reqContext := policy.Context{
+		"aws:PrincipalArn":  {role.ARN},
+		"aws:PrincipalType": {"AssumedRole"},
+	}
+	for _, e := range sim.Context {
+		reqContext.Set(e.Key, e.Values...)
+	}
+	resources := sim.Resources
+	if len(resources) == 0 {
+		resources = []string{"*"}
+	}
+
+	var results []SimulationResult
+	for _, action := range sim.Actions {
+		for _, resource := range resources {
+			req := policy.Request{Principal: role.ARN, Action: action, Resource: resource, Context: reqContext}
+			eval := policy.Evaluate(req, policies)
+			result := SimulationResult{Action: action, Resource: resource, Decision: "implicitDeny"}
+
+			matched := eval.Allows
+			switch eval.Decision {
+			case policy.ExplicitDeny:
+				result.Decision = "explicitDeny"
+				matched = eval.Denies
+			case policy.Allowed:
+				result.Decision = "allowed"
+			}
+			for _, m := range matched {
+				for _, s := range sources {
+					if s.id == m.Policy {
+						line, column, end := statementPosition(s.document, m.Statement.Raw)
+						result.Statements = append(result.Statements, MatchedStatement{
+							PolicyID: s.id, PolicyType: s.typ, StartLine: line, StartColumn: column, EndLine: end,
+							Source: statementAt(s.document, line, column),
+						})
+					}
+				}
+			}
+
+			missing := make(map[string]bool)
+			for _, m := range eval.Unmet {
+				for _, keys := range m.Statement.Condition {
+					for key := range keys {
+						if reqContext.Values(key) == nil {
+							missing[key] = true
+						}
+					}
+				}
+			}
+			for key := range missing {
+				result.MissingContext = append(result.MissingContext, key)
+			}
+			sort.Strings(result.MissingContext)
+
+			if boundary != nil {
+				bound := policy.Evaluate(req, boundary)
+				allowed := bound.Decision == policy.Allowed
+				result.AllowedByBoundary = &allowed
+				if !allowed && result.Decision == "allowed" {
+					result.Decision = "implicitDeny"
+					if bound.Decision == policy.ExplicitDeny {
+						result.Decision = "explicitDeny"
+					}
+					result.Statements = nil
+				}
+			}
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+// statementPosition locates a statement's source in its document as the
+// simulator reports it: start line and column and end line, from 1
+func statementPosition(document string, raw json.RawMessage) (line, column, end int) {
+	i := strings.Index(document, string(raw))
+	if i < 0 || len(raw) == 0 {
+		return 0, 0, 0
+	}
+	line = strings.Count(document[:i], "\n") + 1
+	column = i - strings.LastIndex(document[:i], "\n")
+	return line, column, line + strings.Count(string(raw), "\n")
+}
+
+func (b *FixtureBackend) ListUsers(ctx context.Context) ([]User, error) {
+	// Like UserService.ListUsers, only summary fields and the access keys,
+	// without their last use, are returned
+	users := make([]User, len(b.users))
+	for i, u := range b.users {
+		users[i] = User{
+			Name:             u.Name,
+			ARN:              u.ARN,
+			UserID:           u.UserID,
+			Path:             u.Path,
+			CreateDate:       u.CreateDate,
+			PasswordLastUsed: u.PasswordLastUsed,
+		}
+		for _, k := range u.AccessKeys {
+			users[i].AccessKeys = append(users[i].AccessKeys, AccessKey{ID: k.ID, Status: k.Status, CreateDate: k.CreateDate})
+		}
+	}
+	return users, nil
+}
+
+func (b *FixtureBackend) GetUserDetails(ctx context.Context, userName string) (*User, error) {
+	i, ok := b.userByName[userName]
+	if !ok {
+		return nil, fmt.Errorf("failed to get user: user %q not found", userName)
+	}
+	user := b.users[i]
+	return &user, nil
+}
+
+func (b *FixtureBackend) GetUserInlinePolicy(ctx context.Context, userName, policyName string) (string, error) {
+	doc, ok := b.userInline[userName][policyName]
+	if !ok {
+		return "", fmt.Errorf("failed to get inline policy: %q not found on user %q", policyName, userName)
+	}
+	return doc, nil
+}
+
+func (b *FixtureBackend) ListGroups(ctx context.Context) ([]Group, error) {
+	// Like the real ListGroups call, only summary fields are returned
+	groups := make([]Group, len(b.groups))
+	for i, g := range b.groups {
+		groups[i] = Group{
+			Name:       g.Name,
+			ARN:        g.ARN,
+			GroupID:    g.GroupID,
+			Path:       g.Path,
+			CreateDate: g.CreateDate,
+		}
+	}
+	return groups, nil
+}
+
+func (b *FixtureBackend) GetGroupDetails(ctx context.Context, groupName string) (*Group, error) {
+	i, ok := b.groupByName[groupName]
+	if !ok {
+		return nil, fmt.Errorf("failed to get group: group %q not found", groupName)
+	}
+	group := b.groups[i]
+	return &group, nil
+}
+
+func (b *FixtureBackend) GetGroupInlinePolicy(ctx context.Context, groupName, policyName string) (string, error) {
+	doc, ok := b.groupInline[groupName][policyName]
+	if !ok {
+		return "", fmt.Errorf("failed to get inline policy: %q not found on group %q", policyName, groupName)
+	}
+	return doc, nil
+}
+
+func (b *FixtureBackend) ListPolicies(ctx context.Context, scope PolicyScope) ([]Policy, error) {
+	// Like the real ListPolicies call, versions and entities are not returned
+	var policies []Policy
+	for _, p := range b.policies {
+		if (scope == PolicyScopeLocal && p.AWSManaged()) || (scope == PolicyScopeAWS && !p.AWSManaged()) {
+			continue
+		}
+		p.Versions = nil
+		p.AttachedRoles, p.AttachedUsers, p.AttachedGroups = nil, nil, nil
+		policies = append(policies, p)
+	}
+	return policies, nil
+}
+
+func (b *FixtureBackend) GetPolicyDetails(ctx context.Context, policyArn string) (*Policy, error) {
+	i, ok := b.policyByARN[policyArn]
+	if !ok {
+		return nil, fmt.Errorf("failed to get policy: %s not found", policyArn)
+	}
+	policy := b.policies[i]
+	return &policy, nil
+}
+
+func (b *FixtureBackend) GetPolicyVersionDocument(ctx context.Context, policyArn, versionID string) (string, error) {
+	doc, ok := b.versions[policyArn][versionID]
+	if !ok {
+		return "", fmt.Errorf("failed to get policy version: %s of %s not found", versionID, policyArn)
+	}
+	return doc, nil
+}
+
+// fixtureDocument normalises a policy document given either as a JSON object
+// or as a (possibly URL-encoded) JSON string.
+func fixtureDocument(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		decoded, _ := url.QueryUnescape(s)
+		return formatJSON(decoded)
+	}
+	return formatJSON(string(raw))
+}
+
+func (b *FixtureBackend) ListInstanceProfiles(ctx context.Context) ([]InstanceProfile, error) {
+	// Like the real ListInstanceProfiles call, roles are included but tags are not
+	profiles := make([]InstanceProfile, len(b.instanceProfiles))
+	for i, p := range b.instanceProfiles {
+		p.Tags = nil
+		profiles[i] = p
+	}
+	return profiles, nil
+}
+
+func (b *FixtureBackend) GetInstanceProfile(ctx context.Context, name string) (*InstanceProfile, error) {
+	i, ok := b.profileByName[name]
+	if !ok {
+		return nil, fmt.Errorf("failed to get instance profile: %q not found", name)
+	}
+	profile := b.instanceProfiles[i]
+	return &profile, nil
+}
+
+func (b *FixtureBackend) ListOIDCProviders(ctx context.Context) ([]OIDCProvider, error) {
+	// Like the real ListOpenIDConnectProviders call, only ARNs are returned
+	providers := make([]OIDCProvider, len(b.oidcProviders))
+	for i, p := range b.oidcProviders {
+		providers[i] = OIDCProvider{ARN: p.ARN, URL: ProviderName(p.ARN)}
+	}
+	return providers, nil
+}
+
+func (b *FixtureBackend) GetOIDCProvider(ctx context.Context, providerArn string) (*OIDCProvider, error) {
+	for _, p := range b.oidcProviders {
+		if p.ARN == providerArn {
+			return &p, nil
+		}
+	}
+	return nil, fmt.Errorf("failed to get OIDC provider: %s not found", providerArn)
+}
+
+func (b *FixtureBackend) ListSAMLProviders(ctx context.Context) ([]SAMLProvider, error) {
+	providers := make([]SAMLProvider, len(b.samlProviders))
+	for i, p := range b.samlProviders {
+		p.Tags = nil
+		providers[i] = p
+	}
+	return providers, nil
+}
+
+func (b *FixtureBackend) GetSAMLProvider(ctx context.Context, providerArn string) (*SAMLProvider, error) {
+	for _, p := range b.samlProviders {
+		if p.ARN == providerArn {
+			return &p, nil
+		}
+	}
+	return nil, fmt.Errorf("failed to get SAML provider: %s not found", providerArn)
+}
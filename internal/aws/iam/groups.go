@@ -0,0 +1,137 @@
+package iam
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/johnoct/a3s/internal/aws/client"
+)
+
+// GroupAPI is the set of group operations the UI depends on. GroupService
+// implements it against AWS and FixtureBackend implements it in memory.
+type GroupAPI interface {
+	ListGroups(ctx context.Context) ([]Group, error)
+	GetGroupDetails(ctx context.Context, groupName string) (*Group, error)
+	GetGroupInlinePolicy(ctx context.Context, groupName, policyName string) (string, error)
+	GetManagedPolicyDocument(ctx context.Context, policyArn string) (string, error)
+}
+
+var _ GroupAPI = (*GroupService)(nil)
+
+type GroupService struct {
+	client *iam.Client
+}
+
+func NewGroupService(awsClient *client.AWSClient) *GroupService {
+	return &GroupService{
+		client: iam.NewFromConfig(awsClient.Config),
+	}
+}
+
+type Group struct {
+	Name            string
+	ARN             string
+	GroupID         string
+	Path            string
+	CreateDate      time.Time
+	Members         []string
+	ManagedPolicies []PolicyInfo
+	InlinePolicies  []string
+}
+
+func (s *GroupService) ListGroups(ctx context.Context) ([]Group, error) {
+	var groups []Group
+	paginator := iam.NewListGroupsPaginator(s.client, &iam.ListGroupsInput{})
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list groups: %w", err)
+		}
+
+		for _, g := range output.Groups {
+			groups = append(groups, newGroup(g))
+		}
+	}
+
+	return groups, nil
+}
+
+func newGroup(g types.Group) Group {
+	return Group{
+		Name:       aws.ToString(g.GroupName),
+		ARN:        aws.ToString(g.Arn),
+		GroupID:    aws.ToString(g.GroupId),
+		Path:       aws.ToString(g.Path),
+		CreateDate: aws.ToTime(g.CreateDate),
+	}
+}
+
+func (s *GroupService) GetGroupDetails(ctx context.Context, groupName string) (*Group, error) {
+	// GetGroup returns the group together with its members
+	var group *Group
+	paginator := iam.NewGetGroupPaginator(s.client, &iam.GetGroupInput{
+		GroupName: &groupName,
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get group: %w", err)
+		}
+		if group == nil {
+			g := newGroup(*output.Group)
+			group = &g
+		}
+		for _, u := range output.Users {
+			group.Members = append(group.Members, aws.ToString(u.UserName))
+		}
+	}
+	if group == nil {
+		return nil, fmt.Errorf("failed to get group: %s not found", groupName)
+	}
+
+	// Get attached managed policies
+	managedPolicies, err := s.client.ListAttachedGroupPolicies(ctx, &iam.ListAttachedGroupPoliciesInput{
+		GroupName: &groupName,
+	})
+	if err == nil {
+		for _, p := range managedPolicies.AttachedPolicies {
+			group.ManagedPolicies = append(group.ManagedPolicies, PolicyInfo{
+				Name: aws.ToString(p.PolicyName),
+				ARN:  aws.ToString(p.PolicyArn),
+			})
+		}
+	}
+
+	// Get inline policies
+	inlinePolicies, err := s.client.ListGroupPolicies(ctx, &iam.ListGroupPoliciesInput{
+		GroupName: &groupName,
+	})
+	if err == nil {
+		group.InlinePolicies = inlinePolicies.PolicyNames
+	}
+
+	return group, nil
+}
+
+func (s *GroupService) GetGroupInlinePolicy(ctx context.Context, groupName, policyName string) (string, error) {
+	output, err := s.client.GetGroupPolicy(ctx, &iam.GetGroupPolicyInput{
+		GroupName:  &groupName,
+		PolicyName: &policyName,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get inline policy: %w", err)
+	}
+
+	decoded, _ := url.QueryUnescape(*output.PolicyDocument)
+	return formatJSON(decoded), nil
+}
+
+func (s *GroupService) GetManagedPolicyDocument(ctx context.Context, policyArn string) (string, error) {
+	return getManagedPolicyDocument(ctx, s.client, policyArn)
+}
@@ -0,0 +1,115 @@
+package iam
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/johnoct/a3s/internal/aws/client"
+)
+
+// InstanceProfileAPI is the set of instance profile operations the UI
+// depends on. InstanceProfileService implements it against AWS and
+// FixtureBackend implements it in memory.
+type InstanceProfileAPI interface {
+	ListInstanceProfiles(ctx context.Context) ([]InstanceProfile, error)
+	GetInstanceProfile(ctx context.Context, name string) (*InstanceProfile, error)
+}
+
+var _ InstanceProfileAPI = (*InstanceProfileService)(nil)
+
+type InstanceProfileService struct {
+	client *iam.Client
+}
+
+func NewInstanceProfileService(awsClient *client.AWSClient) *InstanceProfileService {
+	return &InstanceProfileService{
+		client: iam.NewFromConfig(awsClient.Config),
+	}
+}
+
+type InstanceProfile struct {
+	Name              string
+	ARN               string
+	InstanceProfileID string
+	Path              string
+	CreateDate        time.Time
+	Roles             []string
+	Tags              []Tag
+}
+
+func (s *InstanceProfileService) ListInstanceProfiles(ctx context.Context) ([]InstanceProfile, error) {
+	var profiles []InstanceProfile
+	paginator := iam.NewListInstanceProfilesPaginator(s.client, &iam.ListInstanceProfilesInput{})
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list instance profiles: %w", err)
+		}
+
+		for _, p := range output.InstanceProfiles {
+			profiles = append(profiles, newInstanceProfile(p))
+		}
+	}
+
+	return profiles, nil
+}
+
+func (s *InstanceProfileService) GetInstanceProfile(ctx context.Context, name string) (*InstanceProfile, error) {
+	output, err := s.client.GetInstanceProfile(ctx, &iam.GetInstanceProfileInput{
+		InstanceProfileName: &name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instance profile: %w", err)
+	}
+
+	profile := newInstanceProfile(*output.InstanceProfile)
+	return &profile, nil
+}
+
+// newInstanceProfile converts an SDK instance profile, which always carries
+// its roles
+func newInstanceProfile(p types.InstanceProfile) InstanceProfile {
+	profile := InstanceProfile{
+		Name:              aws.ToString(p.InstanceProfileName),
+		ARN:               aws.ToString(p.Arn),
+		InstanceProfileID: aws.ToString(p.InstanceProfileId),
+		Path:              aws.ToString(p.Path),
+		CreateDate:        aws.ToTime(p.CreateDate),
+	}
+	for _, r := range p.Roles {
+		profile.Roles = append(profile.Roles, aws.ToString(r.RoleName))
+	}
+	for _, t := range p.Tags {
+		profile.Tags = append(profile.Tags, Tag{
+			Key:   aws.ToString(t.Key),
+			Value: aws.ToString(t.Value),
+		})
+	}
+	return profile
+}
+
+// listInstanceProfilesForRole returns the instance profiles that contain a role
+func listInstanceProfilesForRole(ctx context.Context, client *iam.Client, roleName string) ([]InstanceProfile, error) {
+	var profiles []InstanceProfile
+	paginator := iam.NewListInstanceProfilesForRolePaginator(client, &iam.ListInstanceProfilesForRoleInput{
+		RoleName: &roleName,
+	})
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list instance profiles for role: %w", err)
+		}
+
+		for _, p := range output.InstanceProfiles {
+			profiles = append(profiles, newInstanceProfile(p))
+		}
+	}
+
+	return profiles, nil
+}
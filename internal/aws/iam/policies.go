@@ -0,0 +1,177 @@
+package iam
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/johnoct/a3s/internal/aws/client"
+)
+
+// PolicyAPI is the set of managed policy operations the UI depends on.
+// PolicyService implements it against AWS and FixtureBackend implements it in
+// memory.
+type PolicyAPI interface {
+	ListPolicies(ctx context.Context, scope PolicyScope) ([]Policy, error)
+	GetPolicyDetails(ctx context.Context, policyArn string) (*Policy, error)
+	GetPolicyVersionDocument(ctx context.Context, policyArn, versionID string) (string, error)
+}
+
+var _ PolicyAPI = (*PolicyService)(nil)
+
+// PolicyScope selects which managed policies ListPolicies returns
+type PolicyScope string
+
+const (
+	// PolicyScopeLocal is customer managed policies
+	PolicyScopeLocal PolicyScope = "Local"
+	// PolicyScopeAWS is AWS managed policies
+	PolicyScopeAWS PolicyScope = "AWS"
+	// PolicyScopeAll is both
+	PolicyScopeAll PolicyScope = "All"
+)
+
+// PolicyScopes lists the scopes in toggle order
+var PolicyScopes = []PolicyScope{PolicyScopeLocal, PolicyScopeAWS, PolicyScopeAll}
+
+type PolicyService struct {
+	client *iam.Client
+}
+
+func NewPolicyService(awsClient *client.AWSClient) *PolicyService {
+	return &PolicyService{
+		client: iam.NewFromConfig(awsClient.Config),
+	}
+}
+
+type Policy struct {
+	Name            string
+	ARN             string
+	PolicyID        string
+	Path            string
+	Description     string
+	DefaultVersion  string
+	AttachmentCount int32
+	BoundaryCount   int32
+	CreateDate      time.Time
+	UpdateDate      time.Time
+	Versions        []PolicyVersion
+	AttachedRoles   []string
+	AttachedUsers   []string
+	AttachedGroups  []string
+}
+
+type PolicyVersion struct {
+	ID         string
+	IsDefault  bool
+	CreateDate time.Time
+}
+
+// AWSManaged reports whether the policy is maintained by AWS
+func (p Policy) AWSManaged() bool {
+	return IsAWSManagedPolicy(p.ARN)
+}
+
+// IsAWSManagedPolicy reports whether a policy ARN names an AWS managed policy
+func IsAWSManagedPolicy(policyArn string) bool {
+	return strings.Contains(policyArn, ":iam::aws:policy/")
+}
+
+func (s *PolicyService) ListPolicies(ctx context.Context, scope PolicyScope) ([]Policy, error) {
+	var policies []Policy
+	paginator := iam.NewListPoliciesPaginator(s.client, &iam.ListPoliciesInput{
+		Scope: types.PolicyScopeType(scope),
+	})
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list policies: %w", err)
+		}
+
+		for _, p := range output.Policies {
+			policies = append(policies, newPolicy(p))
+		}
+	}
+
+	return policies, nil
+}
+
+func newPolicy(p types.Policy) Policy {
+	return Policy{
+		Name:            aws.ToString(p.PolicyName),
+		ARN:             aws.ToString(p.Arn),
+		PolicyID:        aws.ToString(p.PolicyId),
+		Path:            aws.ToString(p.Path),
+		Description:     aws.ToString(p.Description),
+		DefaultVersion:  aws.ToString(p.DefaultVersionId),
+		AttachmentCount: aws.ToInt32(p.AttachmentCount),
+		BoundaryCount:   aws.ToInt32(p.PermissionsBoundaryUsageCount),
+		CreateDate:      aws.ToTime(p.CreateDate),
+		UpdateDate:      aws.ToTime(p.UpdateDate),
+	}
+}
+
+func (s *PolicyService) GetPolicyDetails(ctx context.Context, policyArn string) (*Policy, error) {
+	output, err := s.client.GetPolicy(ctx, &iam.GetPolicyInput{
+		PolicyArn: &policyArn,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get policy: %w", err)
+	}
+	policy := newPolicy(*output.Policy)
+
+	// Get every version, newest first
+	versions, err := s.client.ListPolicyVersions(ctx, &iam.ListPolicyVersionsInput{
+		PolicyArn: &policyArn,
+	})
+	if err == nil {
+		for _, v := range versions.Versions {
+			policy.Versions = append(policy.Versions, PolicyVersion{
+				ID:         aws.ToString(v.VersionId),
+				IsDefault:  v.IsDefaultVersion,
+				CreateDate: aws.ToTime(v.CreateDate),
+			})
+		}
+	}
+
+	// Get the roles, users and groups the policy is attached to
+	paginator := iam.NewListEntitiesForPolicyPaginator(s.client, &iam.ListEntitiesForPolicyInput{
+		PolicyArn: &policyArn,
+	})
+	for paginator.HasMorePages() {
+		entities, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list entities for policy: %w", err)
+		}
+		for _, r := range entities.PolicyRoles {
+			policy.AttachedRoles = append(policy.AttachedRoles, aws.ToString(r.RoleName))
+		}
+		for _, u := range entities.PolicyUsers {
+			policy.AttachedUsers = append(policy.AttachedUsers, aws.ToString(u.UserName))
+		}
+		for _, g := range entities.PolicyGroups {
+			policy.AttachedGroups = append(policy.AttachedGroups, aws.ToString(g.GroupName))
+		}
+	}
+
+	return &policy, nil
+}
+
+func (s *PolicyService) GetPolicyVersionDocument(ctx context.Context, policyArn, versionID string) (string, error) {
+	output, err := s.client.GetPolicyVersion(ctx, &iam.GetPolicyVersionInput{
+		PolicyArn: &policyArn,
+		VersionId: &versionID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get policy version: %w", err)
+	}
+
+	decoded, _ := url.QueryUnescape(*output.PolicyVersion.Document)
+	return formatJSON(decoded), nil
+}
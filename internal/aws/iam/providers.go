@@ -0,0 +1,146 @@
+package iam
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/johnoct/a3s/internal/aws/client"
+)
+
+// ProviderAPI is the set of identity provider operations the UI depends on.
+// ProviderService implements it against AWS and FixtureBackend implements
+// it in memory.
+type ProviderAPI interface {
+	ListOIDCProviders(ctx context.Context) ([]OIDCProvider, error)
+	GetOIDCProvider(ctx context.Context, providerArn string) (*OIDCProvider, error)
+	ListSAMLProviders(ctx context.Context) ([]SAMLProvider, error)
+	GetSAMLProvider(ctx context.Context, providerArn string) (*SAMLProvider, error)
+}
+
+var _ ProviderAPI = (*ProviderService)(nil)
+
+type ProviderService struct {
+	client *iam.Client
+}
+
+func NewProviderService(awsClient *client.AWSClient) *ProviderService {
+	return &ProviderService{
+		client: iam.NewFromConfig(awsClient.Config),
+	}
+}
+
+// OIDCProvider is an OpenID Connect identity provider, e.g. GitHub Actions
+type OIDCProvider struct {
+	ARN         string
+	URL         string
+	ClientIDs   []string
+	Thumbprints []string
+	CreateDate  time.Time
+	Tags        []Tag
+}
+
+// SAMLProvider is a SAML 2.0 identity provider
+type SAMLProvider struct {
+	ARN        string
+	Name       string
+	CreateDate time.Time
+	// ValidUntil is when the provider's metadata document expires
+	ValidUntil time.Time
+	Tags       []Tag
+}
+
+// ProviderName returns the name part of an OIDC or SAML provider ARN: the
+// issuer host and path for OIDC, the provider name for SAML
+func ProviderName(providerArn string) string {
+	for _, marker := range []string{":oidc-provider/", ":saml-provider/"} {
+		if i := strings.Index(providerArn, marker); i >= 0 {
+			return providerArn[i+len(marker):]
+		}
+	}
+	return providerArn
+}
+
+// ListOIDCProviders lists the OIDC providers. ListOpenIDConnectProviders only
+// returns ARNs; the URL is taken from the ARN and the client IDs and
+// thumbprints need GetOIDCProvider.
+func (s *ProviderService) ListOIDCProviders(ctx context.Context) ([]OIDCProvider, error) {
+	output, err := s.client.ListOpenIDConnectProviders(ctx, &iam.ListOpenIDConnectProvidersInput{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list OIDC providers: %w", err)
+	}
+
+	providers := make([]OIDCProvider, 0, len(output.OpenIDConnectProviderList))
+	for _, p := range output.OpenIDConnectProviderList {
+		arn := aws.ToString(p.Arn)
+		providers = append(providers, OIDCProvider{ARN: arn, URL: ProviderName(arn)})
+	}
+	return providers, nil
+}
+
+func (s *ProviderService) GetOIDCProvider(ctx context.Context, providerArn string) (*OIDCProvider, error) {
+	output, err := s.client.GetOpenIDConnectProvider(ctx, &iam.GetOpenIDConnectProviderInput{
+		OpenIDConnectProviderArn: &providerArn,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get OIDC provider: %w", err)
+	}
+
+	return &OIDCProvider{
+		ARN:         providerArn,
+		URL:         aws.ToString(output.Url),
+		ClientIDs:   output.ClientIDList,
+		Thumbprints: output.ThumbprintList,
+		CreateDate:  aws.ToTime(output.CreateDate),
+		Tags:        convertTags(output.Tags),
+	}, nil
+}
+
+func (s *ProviderService) ListSAMLProviders(ctx context.Context) ([]SAMLProvider, error) {
+	output, err := s.client.ListSAMLProviders(ctx, &iam.ListSAMLProvidersInput{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list SAML providers: %w", err)
+	}
+
+	providers := make([]SAMLProvider, 0, len(output.SAMLProviderList))
+	for _, p := range output.SAMLProviderList {
+		arn := aws.ToString(p.Arn)
+		providers = append(providers, SAMLProvider{
+			ARN:        arn,
+			Name:       ProviderName(arn),
+			CreateDate: aws.ToTime(p.CreateDate),
+			ValidUntil: aws.ToTime(p.ValidUntil),
+		})
+	}
+	return providers, nil
+}
+
+func (s *ProviderService) GetSAMLProvider(ctx context.Context, providerArn string) (*SAMLProvider, error) {
+	output, err := s.client.GetSAMLProvider(ctx, &iam.GetSAMLProviderInput{
+		SAMLProviderArn: &providerArn,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SAML provider: %w", err)
+	}
+
+	return &SAMLProvider{
+		ARN:        providerArn,
+		Name:       ProviderName(providerArn),
+		CreateDate: aws.ToTime(output.CreateDate),
+		ValidUntil: aws.ToTime(output.ValidUntil),
+		Tags:       convertTags(output.Tags),
+	}, nil
+}
+
+// convertTags converts SDK tags to Tags
+func convertTags(tags []types.Tag) []Tag {
+	var out []Tag
+	for _, t := range tags {
+		out = append(out, Tag{Key: aws.ToString(t.Key), Value: aws.ToString(t.Value)})
+	}
+	return out
+}
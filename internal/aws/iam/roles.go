@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -12,13 +13,35 @@ import (
 	"github.com/johnoct/a3s/internal/aws/client"
 )
 
+// RoleAPI is the set of role operations the UI depends on. RoleService
+// implements it against AWS and FixtureBackend implements it in memory.
+type RoleAPI interface {
+	ListRoles(ctx context.Context) ([]Role, error)
+	RefreshRoles(ctx context.Context) ([]Role, error)
+	ListRoleSummaries(ctx context.Context) ([]Role, error)
+	GetRole(ctx context.Context, roleName string) (*Role, error)
+	GetRoleDetails(ctx context.Context, roleName string) (*Role, error)
+	GetInlinePolicy(ctx context.Context, roleName, policyName string) (string, error)
+	GetManagedPolicyDocument(ctx context.Context, policyArn string) (string, error)
+	GetServiceLastAccessed(ctx context.Context, roleArn string) ([]ServiceAccess, error)
+	SimulatePrincipalPolicy(ctx context.Context, roleArn string, sim Simulation) ([]SimulationResult, error)
+}
+
+var _ RoleAPI = (*RoleService)(nil)
+
+// RoleService lists roles from an account snapshot when
+// GetAccountAuthorizationDetails is permitted, so opening a role needs no
+// further calls. Roles created since the last listing, or every role when
+// no snapshot could be loaded, are fetched one by one.
 type RoleService struct {
-	client *iam.Client
+	client    *iam.Client
+	snapshots *SnapshotStore
 }
 
-func NewRoleService(awsClient *client.AWSClient) *RoleService {
+func NewRoleService(awsClient *client.AWSClient, snapshots *SnapshotStore) *RoleService {
 	return &RoleService{
-		client: iam.NewFromConfig(awsClient.Config),
+		client:    iam.NewFromConfig(awsClient.Config),
+		snapshots: snapshots,
 	}
 }
 
@@ -33,8 +56,13 @@ type Role struct {
 	Tags               []Tag
 	TrustPolicy        string
 	LastUsed           *time.Time
+	LastUsedRegion     string
 	ManagedPolicies    []PolicyInfo
 	InlinePolicies     []string
+	InstanceProfiles   []InstanceProfile
+	// PermissionsBoundary is the managed policy capping the role's
+	// permissions, nil when the role has none. ListRoles does not return it.
+	PermissionsBoundary *PolicyInfo
 }
 
 type PolicyInfo struct {
@@ -47,7 +75,50 @@ type Tag struct {
 	Value string
 }
 
+// ListRoles returns the roles of the account snapshot, details included,
+// loading the snapshot on first use. Without a snapshot it falls back to
+// ListRoles, which reports neither last use nor details.
 func (s *RoleService) ListRoles(ctx context.Context) ([]Role, error) {
+	if s.snapshots != nil {
+		if snap, err := s.snapshots.Get(ctx); err == nil {
+			return snap.Roles(), nil
+		}
+	}
+	return s.listRoles(ctx)
+}
+
+// RefreshRoles reloads the account snapshot and returns its roles, falling
+// back to ListRoles like ListRoles does
+func (s *RoleService) RefreshRoles(ctx context.Context) ([]Role, error) {
+	if s.snapshots != nil {
+		if snap, err := s.snapshots.Load(ctx); err == nil {
+			return snap.Roles(), nil
+		}
+	}
+	return s.listRoles(ctx)
+}
+
+// ListRoleSummaries lists roles with the ListRoles API alone, for polling
+// and for lookups that only read summary fields such as the trust policy.
+// The snapshot is neither loaded nor reloaded, but each listed role is
+// synced into it: unchanged roles keep their details and the last use
+// GetRole last reported, changed ones are dropped from it.
+func (s *RoleService) ListRoleSummaries(ctx context.Context) ([]Role, error) {
+	roles, err := s.listRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	snap := s.snapshots.Current()
+	for i := range roles {
+		if known, ok := snap.syncRole(&roles[i], false); ok {
+			roles[i] = *known
+		}
+	}
+	return roles, nil
+}
+
+// listRoles pages through the ListRoles API
+func (s *RoleService) listRoles(ctx context.Context) ([]Role, error) {
 	var roles []Role
 	paginator := iam.NewListRolesPaginator(s.client, &iam.ListRolesInput{})
 
@@ -78,6 +149,7 @@ func (s *RoleService) ListRoles(ctx context.Context) ([]Role, error) {
 
 			if r.RoleLastUsed != nil && r.RoleLastUsed.LastUsedDate != nil {
 				role.LastUsed = r.RoleLastUsed.LastUsedDate
+				role.LastUsedRegion = aws.ToString(r.RoleLastUsed.Region)
 			}
 
 			roles = append(roles, role)
@@ -87,7 +159,11 @@ func (s *RoleService) ListRoles(ctx context.Context) ([]Role, error) {
 	return roles, nil
 }
 
-func (s *RoleService) GetRoleDetails(ctx context.Context, roleName string) (*Role, error) {
+// GetRole returns the role as reported by GetRole alone: unlike ListRoles it
+// includes the last use and the permissions boundary, but no tags, policies
+// or instance profiles. It always calls IAM, and syncs the result into the
+// snapshot so later reads see the live last use.
+func (s *RoleService) GetRole(ctx context.Context, roleName string) (*Role, error) {
 	getRoleOutput, err := s.client.GetRole(ctx, &iam.GetRoleInput{
 		RoleName: &roleName,
 	})
@@ -116,6 +192,29 @@ func (s *RoleService) GetRoleDetails(ctx context.Context, roleName string) (*Rol
 
 	if r.RoleLastUsed != nil && r.RoleLastUsed.LastUsedDate != nil {
 		role.LastUsed = r.RoleLastUsed.LastUsedDate
+		role.LastUsedRegion = aws.ToString(r.RoleLastUsed.Region)
+	}
+
+	if r.PermissionsBoundary != nil && r.PermissionsBoundary.PermissionsBoundaryArn != nil {
+		arn := *r.PermissionsBoundary.PermissionsBoundaryArn
+		role.PermissionsBoundary = &PolicyInfo{
+			Name: arn[strings.LastIndex(arn, "/")+1:],
+			ARN:  arn,
+		}
+	}
+
+	s.snapshots.Current().syncRole(role, true)
+	return role, nil
+}
+
+func (s *RoleService) GetRoleDetails(ctx context.Context, roleName string) (*Role, error) {
+	if role, ok := s.snapshots.Current().Role(roleName); ok {
+		return role, nil
+	}
+
+	role, err := s.GetRole(ctx, roleName)
+	if err != nil {
+		return nil, err
 	}
 
 	// Get tags
@@ -152,10 +251,20 @@ func (s *RoleService) GetRoleDetails(ctx context.Context, roleName string) (*Rol
 		role.InlinePolicies = inlinePolicies.PolicyNames
 	}
 
+	// Get the instance profiles EC2 instances use to assume the role
+	profiles, err := listInstanceProfilesForRole(ctx, s.client, roleName)
+	if err == nil {
+		role.InstanceProfiles = profiles
+	}
+
 	return role, nil
 }
 
 func (s *RoleService) GetInlinePolicy(ctx context.Context, roleName, policyName string) (string, error) {
+	if document, ok := s.snapshots.Current().RoleInlinePolicy(roleName, policyName); ok {
+		return document, nil
+	}
+
 	output, err := s.client.GetRolePolicy(ctx, &iam.GetRolePolicyInput{
 		RoleName:   &roleName,
 		PolicyName: &policyName,
@@ -169,8 +278,28 @@ func (s *RoleService) GetInlinePolicy(ctx context.Context, roleName, policyName
 }
 
 func (s *RoleService) GetManagedPolicyDocument(ctx context.Context, policyArn string) (string, error) {
+	if document, ok := s.snapshots.Current().PolicyDocument(policyArn); ok {
+		return document, nil
+	}
+	return getManagedPolicyDocument(ctx, s.client, policyArn)
+}
+
+// getManagedPolicyDocument returns the default version of a managed policy,
+// shared by every service that shows attached policies
+func getManagedPolicyDocument(ctx context.Context, api *iam.Client, policyArn string) (string, error) {
+	document, err := rawManagedPolicyDocument(ctx, api, policyArn)
+	if err != nil {
+		return "", err
+	}
+	return formatJSON(document), nil
+}
+
+// rawManagedPolicyDocument returns the default version of a managed policy
+// as IAM stores it, only URL decoded. IAM encodes documents per RFC 3986,
+// where "+" is literal, so statement offsets into the result hold.
+func rawManagedPolicyDocument(ctx context.Context, api *iam.Client, policyArn string) (string, error) {
 	// First get the policy to find the default version
-	policy, err := s.client.GetPolicy(ctx, &iam.GetPolicyInput{
+	policy, err := api.GetPolicy(ctx, &iam.GetPolicyInput{
 		PolicyArn: &policyArn,
 	})
 	if err != nil {
@@ -178,7 +307,7 @@ func (s *RoleService) GetManagedPolicyDocument(ctx context.Context, policyArn st
 	}
 
 	// Get the policy document for the default version
-	policyVersion, err := s.client.GetPolicyVersion(ctx, &iam.GetPolicyVersionInput{
+	policyVersion, err := api.GetPolicyVersion(ctx, &iam.GetPolicyVersionInput{
 		PolicyArn: &policyArn,
 		VersionId: policy.Policy.DefaultVersionId,
 	})
@@ -186,8 +315,8 @@ func (s *RoleService) GetManagedPolicyDocument(ctx context.Context, policyArn st
 		return "", fmt.Errorf("failed to get policy version: %w", err)
 	}
 
-	decoded, _ := url.QueryUnescape(*policyVersion.PolicyVersion.Document)
-	return formatJSON(decoded), nil
+	decoded, _ := url.PathUnescape(*policyVersion.PolicyVersion.Document)
+	return decoded, nil
 }
 
 func formatJSON(jsonStr string) string {
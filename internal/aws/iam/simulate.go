@@ -0,0 +1,317 @@
+package iam
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+// Simulation is a set of API calls to run through the IAM policy simulator:
+// every action is simulated against every resource
+type Simulation struct {
+	Actions []string
+	// Resources defaults to "*" when empty
+	Resources []string
+	Context   []ContextEntry
+}
+
+// ContextEntry is a condition key supplied to a simulation
+type ContextEntry struct {
+	Key string
+	// Type is a simulator context key type, e.g. string, stringList,
+	// numeric, boolean, date or ip
+	Type   string
+	Values []string
+}
+
+// SimulationResult is the simulator's decision for one action on one
+// resource
+type SimulationResult struct {
+	Action   string
+	Resource string
+	// Decision is allowed, explicitDeny or implicitDeny
+	Decision   string
+	Statements []MatchedStatement
+	// MissingContext lists condition keys the decision depended on that the
+	// simulation did not supply
+	MissingContext []string
+	// AllowedByBoundary is nil when the role has no permissions boundary
+	AllowedByBoundary *bool
+}
+
+// Allowed reports whether the simulator allowed the call
+func (r SimulationResult) Allowed() bool {
+	return r.Decision == string(types.PolicyEvaluationDecisionTypeAllowed)
+}
+
+// MatchedStatement is a policy statement that decided a simulation result
+type MatchedStatement struct {
+	PolicyID string
+	// PolicyType is the simulator's source policy type, e.g. role,
+	// aws-managed or user-managed
+	PolicyType string
+	// StartLine and EndLine locate the statement in the policy document as
+	// IAM stores it, from 1; 0 when unknown
+	StartLine   int
+	StartColumn int
+	EndLine     int
+	// Source is the statement, indented; empty when the policy document
+	// could not be found
+	Source string
+}
+
+// SimulatePrincipalPolicy runs a simulation against the identity policies
+// and permissions boundary of a role
+func (s *RoleService) SimulatePrincipalPolicy(ctx context.Context, roleArn string, sim Simulation) ([]SimulationResult, error) {
+	input := &iam.SimulatePrincipalPolicyInput{
+		PolicySourceArn: &roleArn,
+		ActionNames:     sim.Actions,
+		ResourceArns:    sim.Resources,
+	}
+	for _, e := range sim.Context {
+		input.ContextEntries = append(input.ContextEntries, types.ContextEntry{
+			ContextKeyName:   aws.String(e.Key),
+			ContextKeyType:   types.ContextKeyTypeEnum(e.Type),
+			ContextKeyValues: e.Values,
+		})
+	}
+
+	var results []SimulationResult
+	paginator := iam.NewSimulatePrincipalPolicyPaginator(s.client, input)
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to simulate principal policy: %w", err)
+		}
+		for _, r := range output.EvaluationResults {
+			results = append(results, simulationResults(r)...)
+		}
+	}
+	s.statementSources(ctx, roleArn, results)
+	return results, nil
+}
+
+// statementSources fills in the source of the matched statements. The
+// simulator reports statements by position in the documents as IAM stores
+// them, so these are fetched unformatted rather than from the snapshot.
+// Statements whose policy cannot be fetched are left without source.
+func (s *RoleService) statementSources(ctx context.Context, roleArn string, results []SimulationResult) {
+	var role *Role
+	documents := make(map[string]string)
+	for i := range results {
+		for j := range results[i].Statements {
+			st := &results[i].Statements[j]
+			if role == nil {
+				var err error
+				if role, err = s.GetRoleDetails(ctx, roleArn[strings.LastIndex(roleArn, "/")+1:]); err != nil {
+					return
+				}
+			}
+			key := st.PolicyType + "/" + st.PolicyID
+			document, ok := documents[key]
+			if !ok {
+				document = s.matchedPolicyDocument(ctx, role, *st)
+				documents[key] = document
+			}
+			st.Source = statementAt(document, st.StartLine, st.StartColumn)
+		}
+	}
+}
+
+// matchedPolicyDocument fetches the role policy a matched statement comes
+// from, "" when none matches its source policy ID
+func (s *RoleService) matchedPolicyDocument(ctx context.Context, role *Role, st MatchedStatement) string {
+	switch st.PolicyType {
+	case string(types.PolicySourceTypeRole):
+		i := matchPolicyID(st.PolicyID, "role_"+role.Name+"_", role.InlinePolicies)
+		if i < 0 {
+			return ""
+		}
+		output, err := s.client.GetRolePolicy(ctx, &iam.GetRolePolicyInput{RoleName: &role.Name, PolicyName: &role.InlinePolicies[i]})
+		if err != nil {
+			return ""
+		}
+		// IAM encodes documents per RFC 3986, where "+" is literal
+		decoded, _ := url.PathUnescape(aws.ToString(output.PolicyDocument))
+		return decoded
+	case string(types.PolicySourceTypeAwsManaged), string(types.PolicySourceTypeUserManaged):
+		candidates := slices.Clone(role.ManagedPolicies)
+		if role.PermissionsBoundary != nil {
+			candidates = append(candidates, *role.PermissionsBoundary)
+		}
+		var ids []string
+		for _, p := range candidates {
+			if p.ARN == st.PolicyID {
+				ids = append(ids, p.ARN)
+			} else {
+				ids = append(ids, p.Name)
+			}
+		}
+		i := matchPolicyID(st.PolicyID, "", ids)
+		if i < 0 {
+			return ""
+		}
+		document, err := rawManagedPolicyDocument(ctx, s.client, candidates[i].ARN)
+		if err != nil {
+			return ""
+		}
+		return document
+	}
+	return ""
+}
+
+// matchPolicyID finds the policy a simulator source policy ID names, -1 when
+// none. The ID is the policy name or ARN, prefixed with the entity for inline
+// policies, e.g. role_<role>_<policy>; prefix is that entity part.
+func matchPolicyID(id, prefix string, names []string) int {
+	id = strings.TrimPrefix(id, prefix)
+	for i, name := range names {
+		if id == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// statementAt returns the statement starting at a line and column, both
+// from 1, of a policy document, indented with its keys in written order
+func statementAt(document string, line, column int) string {
+	if document == "" || line < 1 || column < 1 {
+		return ""
+	}
+	offset := 0
+	for i := 1; i < line; i++ {
+		next := strings.IndexByte(document[offset:], '\n')
+		if next < 0 {
+			return ""
+		}
+		offset += next + 1
+	}
+	offset += column - 1
+	if offset >= len(document) {
+		return ""
+	}
+	rest := strings.TrimLeft(document[offset:], " \t\r\n")
+	if !strings.HasPrefix(rest, "{") {
+		return ""
+	}
+
+	var raw json.RawMessage
+	if err := json.NewDecoder(strings.NewReader(rest)).Decode(&raw); err != nil {
+		return ""
+	}
+	var b bytes.Buffer
+	if err := json.Indent(&b, raw, "", "  "); err != nil {
+		return ""
+	}
+	return b.String()
+}
+
+// simulationResults flattens an evaluation result into one result per
+// resource
+func simulationResults(r types.EvaluationResult) []SimulationResult {
+	action := aws.ToString(r.EvalActionName)
+	if len(r.ResourceSpecificResults) == 0 {
+		result := SimulationResult{
+			Action:         action,
+			Resource:       aws.ToString(r.EvalResourceName),
+			Decision:       string(r.EvalDecision),
+			Statements:     matchedStatements(r.MatchedStatements),
+			MissingContext: r.MissingContextValues,
+		}
+		if r.PermissionsBoundaryDecisionDetail != nil {
+			result.AllowedByBoundary = aws.Bool(r.PermissionsBoundaryDecisionDetail.AllowedByPermissionsBoundary)
+		}
+		return []SimulationResult{result}
+	}
+
+	var results []SimulationResult
+	for _, rr := range r.ResourceSpecificResults {
+		result := SimulationResult{
+			Action:         action,
+			Resource:       aws.ToString(rr.EvalResourceName),
+			Decision:       string(rr.EvalResourceDecision),
+			Statements:     matchedStatements(rr.MatchedStatements),
+			MissingContext: rr.MissingContextValues,
+		}
+		if rr.PermissionsBoundaryDecisionDetail != nil {
+			result.AllowedByBoundary = aws.Bool(rr.PermissionsBoundaryDecisionDetail.AllowedByPermissionsBoundary)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func matchedStatements(statements []types.Statement) []MatchedStatement {
+	var out []MatchedStatement
+	for _, st := range statements {
+		m := MatchedStatement{
+			PolicyID:   aws.ToString(st.SourcePolicyId),
+			PolicyType: string(st.SourcePolicyType),
+		}
+		if st.StartPosition != nil {
+			m.StartLine = int(st.StartPosition.Line)
+			m.StartColumn = int(st.StartPosition.Column)
+		}
+		if st.EndPosition != nil {
+			m.EndLine = int(st.EndPosition.Line)
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+// InferContextType picks the simulator type of a context key from its
+// values: ip, boolean, numeric or date when every value parses as one,
+// string otherwise. Several values make it a list type, e.g. stringList.
+func InferContextType(values []string) string {
+	typ := "string"
+	for _, candidate := range []struct {
+		name  string
+		parse func(string) bool
+	}{
+		{"ip", func(v string) bool {
+			_, _, err := net.ParseCIDR(v)
+			return err == nil || net.ParseIP(v) != nil
+		}},
+		{"boolean", func(v string) bool {
+			_, err := strconv.ParseBool(v)
+			return err == nil && (v == "true" || v == "false")
+		}},
+		{"numeric", func(v string) bool {
+			_, err := strconv.ParseFloat(v, 64)
+			return err == nil
+		}},
+		{"date", func(v string) bool {
+			_, err := time.Parse(time.RFC3339, v)
+			return err == nil
+		}},
+	} {
+		all := len(values) > 0
+		for _, v := range values {
+			if !candidate.parse(v) {
+				all = false
+				break
+			}
+		}
+		if all {
+			typ = candidate.name
+			break
+		}
+	}
+	if len(values) > 1 {
+		typ += "List"
+	}
+	return typ
+}
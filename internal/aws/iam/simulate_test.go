@@ -0,0 +1,67 @@
+package iam
+
+import (
+	"testing"
+)
+
+func TestStatementAt(t *testing.T) {
+	pretty := "{\n    \"Version\": \"2012-10-17\",\n    \"Statement\": [\n        {\n            \"Sid\": \"Read\",\n            \"Effect\": \"Allow\",\n            \"Action\": \"s3:GetObject\",\n            \"Resource\": \"*\"\n        },\n        {\n            \"Effect\": \"Deny\",\n            \"Action\": \"s3:DeleteObject\",\n            \"Resource\": \"*\"\n        }\n    ]\n}"
+	minified := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"},{"Effect":"Deny","Action":"iam:*","Resource":"*"}]}`
+
+	tests := []struct {
+		name         string
+		document     string
+		line, column int
+		want         string
+	}{
+		{
+			name:     "second statement of an indented document",
+			document: pretty, line: 10, column: 9,
+			want: "{\n  \"Effect\": \"Deny\",\n  \"Action\": \"s3:DeleteObject\",\n  \"Resource\": \"*\"\n}",
+		},
+		{
+			name:     "keys keep their written order",
+			document: pretty, line: 4, column: 9,
+			want: "{\n  \"Sid\": \"Read\",\n  \"Effect\": \"Allow\",\n  \"Action\": \"s3:GetObject\",\n  \"Resource\": \"*\"\n}",
+		},
+		{
+			name:     "statement of a single-line document",
+			document: minified, line: 1, column: 96,
+			want: "{\n  \"Effect\": \"Deny\",\n  \"Action\": \"iam:*\",\n  \"Resource\": \"*\"\n}",
+		},
+		{name: "position outside a statement", document: pretty, line: 2, column: 5},
+		{name: "line past the end", document: pretty, line: 40, column: 1},
+		{name: "unknown position", document: pretty},
+		{name: "no document", line: 1, column: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := statementAt(tt.document, tt.line, tt.column); got != tt.want {
+				t.Errorf("statementAt() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchPolicyID(t *testing.T) {
+	names := []string{"my_policy", "policy", "ReadOnly"}
+	tests := []struct {
+		id     string
+		prefix string
+		want   int
+	}{
+		{"policy", "", 1},
+		{"role_app_policy", "role_app_", 1},
+		{"role_app_my_policy", "role_app_", 0},
+		{"role_x_my_policy", "role_x_", 0},
+		{"role_x_my_policy", "role_app_", -1},
+		{"ReadOnly", "", 2},
+		{"S3ReadOnly", "", -1},
+		{"other", "", -1},
+	}
+	for _, tt := range tests {
+		if got := matchPolicyID(tt.id, tt.prefix, names); got != tt.want {
+			t.Errorf("matchPolicyID(%q, %q) = %d, want %d", tt.id, tt.prefix, got, tt.want)
+		}
+	}
+}
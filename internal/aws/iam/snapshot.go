@@ -0,0 +1,306 @@
+package iam
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/johnoct/a3s/internal/aws/client"
+)
+
+// Snapshot is an in-memory index of an account's roles and managed
+// policies, including every policy document, as returned by
+// GetAccountAuthorizationDetails. Only role entries change once loaded: live
+// GetRole and ListRoles results refresh them through syncRole.
+type Snapshot struct {
+	// mu guards roles, roleByName and inline
+	mu         sync.Mutex
+	roles      []Role
+	roleByName map[string]int
+
+	// inline holds role inline policy documents by role name, then policy
+	// name
+	inline map[string]map[string]string
+	// policies holds the default version document of managed policies by
+	// ARN
+	policies map[string]string
+}
+
+// snapshotFilter limits GetAccountAuthorizationDetails to what the snapshot
+// indexes; users and groups are listed by their own services
+var snapshotFilter = []types.EntityType{
+	types.EntityTypeRole,
+	types.EntityTypeLocalManagedPolicy,
+	types.EntityTypeAWSManagedPolicy,
+}
+
+// LoadSnapshot pulls the account's roles and managed policies in one
+// paginated pass. GetAccountAuthorizationDetails omits role descriptions and
+// maximum session durations, so those are filled in from ListRoles.
+func LoadSnapshot(ctx context.Context, api *iam.Client) (*Snapshot, error) {
+	snap := &Snapshot{
+		roleByName: make(map[string]int),
+		inline:     make(map[string]map[string]string),
+		policies:   make(map[string]string),
+	}
+
+	paginator := iam.NewGetAccountAuthorizationDetailsPaginator(api, &iam.GetAccountAuthorizationDetailsInput{
+		Filter: snapshotFilter,
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get account authorization details: %w", err)
+		}
+		for _, r := range output.RoleDetailList {
+			snap.addRole(r)
+		}
+		for _, p := range output.Policies {
+			snap.addPolicy(p)
+		}
+	}
+
+	roles := iam.NewListRolesPaginator(api, &iam.ListRolesInput{})
+	for roles.HasMorePages() {
+		output, err := roles.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list roles: %w", err)
+		}
+		for _, r := range output.Roles {
+			if i, ok := snap.roleByName[aws.ToString(r.RoleName)]; ok {
+				snap.roles[i].Description = aws.ToString(r.Description)
+				snap.roles[i].MaxSessionDuration = aws.ToInt32(r.MaxSessionDuration)
+			}
+		}
+	}
+
+	return snap, nil
+}
+
+func (s *Snapshot) addRole(r types.RoleDetail) {
+	role := Role{
+		Name:            aws.ToString(r.RoleName),
+		ARN:             aws.ToString(r.Arn),
+		CreateDate:      aws.ToTime(r.CreateDate),
+		Path:            aws.ToString(r.Path),
+		RoleID:          aws.ToString(r.RoleId),
+		TrustPolicy:     decodeDocument(r.AssumeRolePolicyDocument),
+		Tags:            convertTags(r.Tags),
+		ManagedPolicies: attachedPolicies(r.AttachedManagedPolicies),
+	}
+	if r.RoleLastUsed != nil && r.RoleLastUsed.LastUsedDate != nil {
+		role.LastUsed = r.RoleLastUsed.LastUsedDate
+		role.LastUsedRegion = aws.ToString(r.RoleLastUsed.Region)
+	}
+	if r.PermissionsBoundary != nil && r.PermissionsBoundary.PermissionsBoundaryArn != nil {
+		arn := aws.ToString(r.PermissionsBoundary.PermissionsBoundaryArn)
+		role.PermissionsBoundary = &PolicyInfo{Name: arn[strings.LastIndex(arn, "/")+1:], ARN: arn}
+	}
+	for _, p := range r.InstanceProfileList {
+		role.InstanceProfiles = append(role.InstanceProfiles, newInstanceProfile(p))
+	}
+	for _, p := range r.RolePolicyList {
+		role.InlinePolicies = append(role.InlinePolicies, aws.ToString(p.PolicyName))
+		if s.inline[role.Name] == nil {
+			s.inline[role.Name] = make(map[string]string)
+		}
+		s.inline[role.Name][aws.ToString(p.PolicyName)] = decodeDocument(p.PolicyDocument)
+	}
+
+	s.roleByName[role.Name] = len(s.roles)
+	s.roles = append(s.roles, role)
+}
+
+// addPolicy indexes the default version of a managed policy
+func (s *Snapshot) addPolicy(p types.ManagedPolicyDetail) {
+	for _, v := range p.PolicyVersionList {
+		if v.IsDefaultVersion {
+			s.policies[aws.ToString(p.Arn)] = decodeDocument(v.Document)
+		}
+	}
+}
+
+// Roles returns every role with its details, in listing order
+func (s *Snapshot) Roles() []Role {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	roles := make([]Role, 0, len(s.roleByName))
+	for i, r := range s.roles {
+		if j, ok := s.roleByName[r.Name]; ok && i == j {
+			roles = append(roles, r)
+		}
+	}
+	return roles
+}
+
+// Role returns a role with its details. Lookups on a nil Snapshot find
+// nothing, so callers can use SnapshotStore.Current directly.
+func (s *Snapshot) Role(name string) (*Role, bool) {
+	if s == nil {
+		return nil, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, ok := s.roleByName[name]
+	if !ok {
+		return nil, false
+	}
+	role := s.roles[i]
+	return &role, true
+}
+
+// syncRole reconciles a role's entry with a live GetRole or ListRoles result
+// and returns the entry, false when the snapshot no longer holds the role.
+// The live last use replaces the snapshot's when reported. A role recreated
+// or changed since the snapshot was loaded is dropped, so its details and
+// inline policies are fetched again; the boundary is only compared for
+// GetRole results since ListRoles omits it.
+func (s *Snapshot) syncRole(live *Role, getRole bool) (*Role, bool) {
+	if s == nil {
+		return nil, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, ok := s.roleByName[live.Name]
+	if !ok {
+		return nil, false
+	}
+	known := &s.roles[i]
+	changed := known.ARN != live.ARN ||
+		known.TrustPolicy != live.TrustPolicy ||
+		known.Description != live.Description ||
+		known.MaxSessionDuration != live.MaxSessionDuration
+	if getRole {
+		changed = changed || boundaryARN(known) != boundaryARN(live)
+	}
+	if changed {
+		delete(s.roleByName, live.Name)
+		delete(s.inline, live.Name)
+		return nil, false
+	}
+	if live.LastUsed != nil {
+		known.LastUsed = live.LastUsed
+		known.LastUsedRegion = live.LastUsedRegion
+	}
+	role := *known
+	return &role, true
+}
+
+func boundaryARN(role *Role) string {
+	if role.PermissionsBoundary == nil {
+		return ""
+	}
+	return role.PermissionsBoundary.ARN
+}
+
+// PolicyDocument returns the default version of a managed policy
+func (s *Snapshot) PolicyDocument(arn string) (string, bool) {
+	if s == nil {
+		return "", false
+	}
+	document, ok := s.policies[arn]
+	return document, ok
+}
+
+func (s *Snapshot) RoleInlinePolicy(roleName, policyName string) (string, bool) {
+	if s == nil {
+		return "", false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	document, ok := s.inline[roleName][policyName]
+	return document, ok
+}
+
+// SnapshotStore holds the latest Snapshot of an account, loaded on demand
+// and shared by everything reading roles through the same RoleService
+type SnapshotStore struct {
+	client *iam.Client
+
+	// loading serialises loads. mu only guards the fields below, so readers
+	// keep using the previous snapshot while a new one is pulled.
+	loading  sync.Mutex
+	mu       sync.Mutex
+	snapshot *Snapshot
+	// loaded is set once a load was attempted, whether or not it succeeded
+	loaded bool
+}
+
+func NewSnapshotStore(awsClient *client.AWSClient) *SnapshotStore {
+	return &SnapshotStore{
+		client: iam.NewFromConfig(awsClient.Config),
+	}
+}
+
+// Load replaces the snapshot with a fresh one. On failure, e.g. when
+// GetAccountAuthorizationDetails is not permitted, the store is emptied so
+// callers fall back to per-entity calls.
+func (s *SnapshotStore) Load(ctx context.Context) (*Snapshot, error) {
+	s.loading.Lock()
+	defer s.loading.Unlock()
+	return s.load(ctx)
+}
+
+// Get returns the snapshot, loading it on first use. Once a load has failed
+// it is not retried until the next Load.
+func (s *SnapshotStore) Get(ctx context.Context) (*Snapshot, error) {
+	s.loading.Lock()
+	defer s.loading.Unlock()
+
+	s.mu.Lock()
+	snap, loaded := s.snapshot, s.loaded
+	s.mu.Unlock()
+	if !loaded {
+		return s.load(ctx)
+	}
+	if snap == nil {
+		return nil, errNoSnapshot
+	}
+	return snap, nil
+}
+
+var errNoSnapshot = errors.New("no account snapshot loaded")
+
+// load pulls a snapshot and swaps it in. The caller holds loading.
+func (s *SnapshotStore) load(ctx context.Context) (*Snapshot, error) {
+	snap, err := LoadSnapshot(ctx, s.client)
+	s.mu.Lock()
+	s.snapshot = snap
+	s.loaded = true
+	s.mu.Unlock()
+	return snap, err
+}
+
+// Current returns the latest snapshot, nil when none is loaded
+func (s *SnapshotStore) Current() *Snapshot {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.snapshot
+}
+
+func attachedPolicies(policies []types.AttachedPolicy) []PolicyInfo {
+	var out []PolicyInfo
+	for _, p := range policies {
+		out = append(out, PolicyInfo{Name: aws.ToString(p.PolicyName), ARN: aws.ToString(p.PolicyArn)})
+	}
+	return out
+}
+
+// decodeDocument URL-decodes and indents a policy document as returned by
+// the IAM API
+func decodeDocument(document *string) string {
+	if document == nil {
+		return ""
+	}
+	decoded, _ := url.QueryUnescape(*document)
+	return formatJSON(decoded)
+}
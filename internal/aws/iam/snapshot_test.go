@@ -0,0 +1,73 @@
+package iam
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSnapshotSyncRole(t *testing.T) {
+	before := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	after := before.Add(24 * time.Hour)
+	boundary := &PolicyInfo{Name: "boundary", ARN: "arn:aws:iam::123456789012:policy/boundary"}
+	known := Role{
+		Name:            "app",
+		ARN:             "arn:aws:iam::123456789012:role/app",
+		TrustPolicy:     `{"Statement": []}`,
+		Description:     "app role",
+		LastUsed:        &before,
+		ManagedPolicies: []PolicyInfo{{Name: "ReadOnlyAccess", ARN: "arn:aws:iam::aws:policy/ReadOnlyAccess"}},
+	}
+
+	tests := []struct {
+		name     string
+		live     func(r *Role)
+		getRole  bool
+		wantKept bool
+		wantUsed *time.Time
+	}{
+		{"unchanged listing keeps the last use", func(r *Role) { r.LastUsed = nil }, false, true, &before},
+		{"GetRole updates the last use", func(r *Role) { r.LastUsed = &after }, true, true, &after},
+		{"description changed", func(r *Role) { r.Description = "changed" }, false, false, nil},
+		{"trust policy changed", func(r *Role) { r.TrustPolicy = `{}` }, false, false, nil},
+		{"role recreated", func(r *Role) { r.ARN += "-2" }, false, false, nil},
+		{"boundary added", func(r *Role) { r.PermissionsBoundary = boundary }, true, false, nil},
+		{"ListRoles omits the boundary", func(r *Role) { r.PermissionsBoundary = boundary }, false, true, &before},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snap := &Snapshot{
+				roles:      []Role{known},
+				roleByName: map[string]int{known.Name: 0},
+				inline:     map[string]map[string]string{"app": {"inline": "{}"}},
+			}
+			live := known
+			live.ManagedPolicies = nil
+			tt.live(&live)
+
+			got, ok := snap.syncRole(&live, tt.getRole)
+			if ok != tt.wantKept {
+				t.Fatalf("syncRole() kept = %v, want %v", ok, tt.wantKept)
+			}
+			_, inline := snap.RoleInlinePolicy("app", "inline")
+			if (len(snap.Roles()) == 1) != tt.wantKept || inline != tt.wantKept {
+				t.Errorf("snapshot roles = %v, inline policy kept = %v, want kept = %v", snap.Roles(), inline, tt.wantKept)
+			}
+			if !ok {
+				return
+			}
+			if len(got.ManagedPolicies) != 1 || !sameTestTime(got.LastUsed, tt.wantUsed) {
+				t.Errorf("syncRole() = %+v, want the snapshot details with last use %v", got, tt.wantUsed)
+			}
+			if stored, _ := snap.Role("app"); !sameTestTime(stored.LastUsed, tt.wantUsed) {
+				t.Errorf("stored last use = %v, want %v", stored.LastUsed, tt.wantUsed)
+			}
+		})
+	}
+}
+
+func sameTestTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,116 @@
+package iam
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/johnoct/a3s/internal/policy"
+)
+
+// Principal is one principal named in a trust policy statement
+type Principal struct {
+	// Type is the principal key: AWS, Service, Federated or CanonicalUser.
+	// An anonymous "Principal": "*" is reported as AWS "*".
+	Type  string
+	Value string
+}
+
+// TrustedPrincipals returns the principals allowed to assume a role by its
+// trust policy, in document order without duplicates. Deny statements are
+// ignored. An unparseable document yields no principals.
+func TrustedPrincipals(trustPolicy string) []Principal {
+	doc, err := policy.Parse(trustPolicy)
+	if err != nil {
+		return nil
+	}
+
+	var principals []Principal
+	seen := make(map[Principal]bool)
+	for _, s := range doc.Statements {
+		if s.Effect != policy.Allow {
+			continue
+		}
+		types := make([]string, 0, len(s.Principal))
+		for t := range s.Principal {
+			types = append(types, t)
+		}
+		sort.Strings(types)
+
+		for _, t := range types {
+			for _, v := range s.Principal[t] {
+				p := Principal{Type: t, Value: v}
+				if !seen[p] {
+					seen[p] = true
+					principals = append(principals, p)
+				}
+			}
+		}
+	}
+	return principals
+}
+
+// Short returns a compact label for the principal: service names without the
+// amazonaws.com suffix, the account ID for account principals and the
+// provider host for federated principals
+func (p Principal) Short() string {
+	switch p.Type {
+	case "Service":
+		return strings.TrimSuffix(p.Value, ".amazonaws.com")
+	case "AWS":
+		if p.Value == "*" {
+			return "*"
+		}
+		parts := strings.Split(p.Value, ":")
+		if len(parts) < 6 {
+			// A bare account ID
+			return p.Value
+		}
+		if parts[5] == "root" {
+			return parts[4]
+		}
+		return parts[4] + "/" + parts[5][strings.LastIndex(parts[5], "/")+1:]
+	case "Federated":
+		if i := strings.Index(p.Value, ":oidc-provider/"); i >= 0 {
+			return p.Value[i+len(":oidc-provider/"):]
+		}
+		if i := strings.Index(p.Value, ":saml-provider/"); i >= 0 {
+			return "saml:" + p.Value[i+len(":saml-provider/"):]
+		}
+		return p.Value
+	}
+	return p.Value
+}
+
+// TrustSummary summarises who can assume a role, e.g. "lambda" or
+// "123456789012, ec2"
+func TrustSummary(trustPolicy string) string {
+	principals := TrustedPrincipals(trustPolicy)
+	labels := make([]string, 0, len(principals))
+	for _, p := range principals {
+		labels = append(labels, p.Short())
+	}
+	return strings.Join(labels, ", ")
+}
+
+// FederationConditions reports whether a trust policy lets the identity
+// provider with the given ARN federate into the role, and returns the
+// conditions of the statements that allow it as "key operator value", e.g.
+// "token.actions.githubusercontent.com:sub StringLike repo:org/app:*"
+func FederationConditions(trustPolicy, providerArn string) (bool, []string) {
+	doc, err := policy.Parse(trustPolicy)
+	if err != nil {
+		return false, nil
+	}
+
+	federates := false
+	var conditions []string
+	for _, s := range doc.Statements {
+		if s.Effect != policy.Allow || !slices.Contains(s.Principal["Federated"], providerArn) {
+			continue
+		}
+		federates = true
+		conditions = append(conditions, s.Conditions()...)
+	}
+	return federates, conditions
+}
@@ -0,0 +1,51 @@
+package iam
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTrustedPrincipals(t *testing.T) {
+	trust := `{"Statement": [
+		{"Effect": "Allow", "Principal": {"Service": ["lambda.amazonaws.com", "ec2.amazonaws.com"]}, "Action": "sts:AssumeRole"},
+		{"Effect": "Allow", "Principal": {"AWS": "arn:aws:iam::123456789012:root"}, "Action": "sts:AssumeRole"},
+		{"Effect": "Allow", "Principal": "*", "Action": "sts:AssumeRole"},
+		{"Effect": "Deny", "Principal": {"AWS": "arn:aws:iam::210987654321:role/x"}, "Action": "sts:AssumeRole"},
+		{"Effect": "Allow", "Principal": {"Service": "lambda.amazonaws.com"}, "Action": "sts:TagSession"}]}`
+	want := []Principal{
+		{Type: "Service", Value: "lambda.amazonaws.com"},
+		{Type: "Service", Value: "ec2.amazonaws.com"},
+		{Type: "AWS", Value: "arn:aws:iam::123456789012:root"},
+		{Type: "AWS", Value: "*"},
+	}
+	if got := TrustedPrincipals(trust); !reflect.DeepEqual(got, want) {
+		t.Errorf("TrustedPrincipals() = %v, want %v", got, want)
+	}
+	if got, want := TrustSummary(trust), "lambda, ec2, 123456789012, *"; got != want {
+		t.Errorf("TrustSummary() = %q, want %q", got, want)
+	}
+	if got := TrustedPrincipals("not json"); got != nil {
+		t.Errorf("TrustedPrincipals(invalid) = %v, want nil", got)
+	}
+}
+
+func TestFederationConditions(t *testing.T) {
+	const provider = "arn:aws:iam::123456789012:oidc-provider/token.actions.githubusercontent.com"
+	trust := `{"Statement": [
+		{"Effect": "Allow", "Principal": {"Federated": "` + provider + `"}, "Action": "sts:AssumeRoleWithWebIdentity",
+		 "Condition": {"StringLike": {"token.actions.githubusercontent.com:sub": "repo:org/app:*"},
+		               "StringEquals": {"token.actions.githubusercontent.com:aud": "sts.amazonaws.com"}}},
+		{"Effect": "Allow", "Principal": {"Service": "ec2.amazonaws.com"}, "Action": "sts:AssumeRole"}]}`
+
+	federates, conditions := FederationConditions(trust, provider)
+	want := []string{
+		"token.actions.githubusercontent.com:aud StringEquals sts.amazonaws.com",
+		"token.actions.githubusercontent.com:sub StringLike repo:org/app:*",
+	}
+	if !federates || !reflect.DeepEqual(conditions, want) {
+		t.Errorf("FederationConditions() = %v, %q, want true, %q", federates, conditions, want)
+	}
+	if federates, _ := FederationConditions(trust, "arn:aws:iam::123456789012:saml-provider/okta"); federates {
+		t.Errorf("FederationConditions(other provider) = true, want false")
+	}
+}
@@ -0,0 +1,256 @@
+package iam
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/johnoct/a3s/internal/aws/client"
+)
+
+// UserAPI is the set of user operations the UI depends on. UserService
+// implements it against AWS and FixtureBackend implements it in memory.
+type UserAPI interface {
+	ListUsers(ctx context.Context) ([]User, error)
+	GetUserDetails(ctx context.Context, userName string) (*User, error)
+	GetUserInlinePolicy(ctx context.Context, userName, policyName string) (string, error)
+	GetManagedPolicyDocument(ctx context.Context, policyArn string) (string, error)
+}
+
+var _ UserAPI = (*UserService)(nil)
+
+type UserService struct {
+	client *iam.Client
+}
+
+func NewUserService(awsClient *client.AWSClient) *UserService {
+	return &UserService{
+		client: iam.NewFromConfig(awsClient.Config),
+	}
+}
+
+type User struct {
+	Name       string
+	ARN        string
+	UserID     string
+	Path       string
+	CreateDate time.Time
+	// PasswordLastUsed is nil when the console password was never used
+	PasswordLastUsed *time.Time
+	Tags             []Tag
+	Groups           []string
+	ManagedPolicies  []PolicyInfo
+	InlinePolicies   []string
+	AccessKeys       []AccessKey
+	// AccessKeysErr is set when the access keys could not be listed
+	AccessKeysErr error
+	MFADevices    []MFADevice
+	// LoginProfile is nil when the user has no console password, or when
+	// LoginProfileErr is set because it could not be read
+	LoginProfile    *LoginProfile
+	LoginProfileErr error
+}
+
+type AccessKey struct {
+	ID         string
+	Status     string
+	CreateDate time.Time
+	// LastUsed is nil when the key was never used
+	LastUsed        *time.Time
+	LastUsedService string
+	LastUsedRegion  string
+}
+
+type MFADevice struct {
+	SerialNumber string
+	EnableDate   time.Time
+}
+
+type LoginProfile struct {
+	CreateDate            time.Time
+	PasswordResetRequired bool
+}
+
+// accessKeyConcurrency bounds the ListAccessKeys calls ListUsers makes at
+// once
+const accessKeyConcurrency = 4
+
+// ListUsers lists every user with its access keys, but not when each key
+// was last used
+func (s *UserService) ListUsers(ctx context.Context) ([]User, error) {
+	var users []User
+	paginator := iam.NewListUsersPaginator(s.client, &iam.ListUsersInput{})
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list users: %w", err)
+		}
+
+		for _, u := range output.Users {
+			users = append(users, newUser(u))
+		}
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, accessKeyConcurrency)
+	for i := range users {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(u *User) {
+			defer func() { <-sem; wg.Done() }()
+			u.AccessKeys, u.AccessKeysErr = s.listAccessKeys(ctx, u.Name)
+		}(&users[i])
+	}
+	wg.Wait()
+
+	return users, nil
+}
+
+func (s *UserService) listAccessKeys(ctx context.Context, userName string) ([]AccessKey, error) {
+	var keys []AccessKey
+	paginator := iam.NewListAccessKeysPaginator(s.client, &iam.ListAccessKeysInput{
+		UserName: &userName,
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list access keys: %w", err)
+		}
+		for _, k := range output.AccessKeyMetadata {
+			keys = append(keys, AccessKey{
+				ID:         aws.ToString(k.AccessKeyId),
+				Status:     string(k.Status),
+				CreateDate: aws.ToTime(k.CreateDate),
+			})
+		}
+	}
+	return keys, nil
+}
+
+func newUser(u types.User) User {
+	return User{
+		Name:             aws.ToString(u.UserName),
+		ARN:              aws.ToString(u.Arn),
+		UserID:           aws.ToString(u.UserId),
+		Path:             aws.ToString(u.Path),
+		CreateDate:       aws.ToTime(u.CreateDate),
+		PasswordLastUsed: u.PasswordLastUsed,
+	}
+}
+
+func (s *UserService) GetUserDetails(ctx context.Context, userName string) (*User, error) {
+	getUserOutput, err := s.client.GetUser(ctx, &iam.GetUserInput{
+		UserName: &userName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	user := newUser(*getUserOutput.User)
+	for _, t := range getUserOutput.User.Tags {
+		user.Tags = append(user.Tags, Tag{
+			Key:   aws.ToString(t.Key),
+			Value: aws.ToString(t.Value),
+		})
+	}
+
+	// Get groups
+	groups, err := s.client.ListGroupsForUser(ctx, &iam.ListGroupsForUserInput{
+		UserName: &userName,
+	})
+	if err == nil {
+		for _, g := range groups.Groups {
+			user.Groups = append(user.Groups, aws.ToString(g.GroupName))
+		}
+	}
+
+	// Get attached managed policies
+	managedPolicies, err := s.client.ListAttachedUserPolicies(ctx, &iam.ListAttachedUserPoliciesInput{
+		UserName: &userName,
+	})
+	if err == nil {
+		for _, p := range managedPolicies.AttachedPolicies {
+			user.ManagedPolicies = append(user.ManagedPolicies, PolicyInfo{
+				Name: aws.ToString(p.PolicyName),
+				ARN:  aws.ToString(p.PolicyArn),
+			})
+		}
+	}
+
+	// Get inline policies
+	inlinePolicies, err := s.client.ListUserPolicies(ctx, &iam.ListUserPoliciesInput{
+		UserName: &userName,
+	})
+	if err == nil {
+		user.InlinePolicies = inlinePolicies.PolicyNames
+	}
+
+	// Get access keys and when each was last used
+	user.AccessKeys, user.AccessKeysErr = s.listAccessKeys(ctx, userName)
+	for i := range user.AccessKeys {
+		key := &user.AccessKeys[i]
+		lastUsed, err := s.client.GetAccessKeyLastUsed(ctx, &iam.GetAccessKeyLastUsedInput{
+			AccessKeyId: &key.ID,
+		})
+		if err == nil && lastUsed.AccessKeyLastUsed != nil {
+			key.LastUsed = lastUsed.AccessKeyLastUsed.LastUsedDate
+			key.LastUsedService = aws.ToString(lastUsed.AccessKeyLastUsed.ServiceName)
+			key.LastUsedRegion = aws.ToString(lastUsed.AccessKeyLastUsed.Region)
+		}
+	}
+
+	// Get MFA devices
+	mfaDevices, err := s.client.ListMFADevices(ctx, &iam.ListMFADevicesInput{
+		UserName: &userName,
+	})
+	if err == nil {
+		for _, d := range mfaDevices.MFADevices {
+			user.MFADevices = append(user.MFADevices, MFADevice{
+				SerialNumber: aws.ToString(d.SerialNumber),
+				EnableDate:   aws.ToTime(d.EnableDate),
+			})
+		}
+	}
+
+	// Get the console login profile; NoSuchEntity means no console password,
+	// any other error leaves it unknown rather than failing the whole detail
+	loginProfile, err := s.client.GetLoginProfile(ctx, &iam.GetLoginProfileInput{
+		UserName: &userName,
+	})
+	var noSuchEntity *types.NoSuchEntityException
+	switch {
+	case err == nil:
+		user.LoginProfile = &LoginProfile{
+			CreateDate:            aws.ToTime(loginProfile.LoginProfile.CreateDate),
+			PasswordResetRequired: loginProfile.LoginProfile.PasswordResetRequired,
+		}
+	case !errors.As(err, &noSuchEntity):
+		user.LoginProfileErr = fmt.Errorf("failed to get login profile: %w", err)
+	}
+
+	return &user, nil
+}
+
+func (s *UserService) GetUserInlinePolicy(ctx context.Context, userName, policyName string) (string, error) {
+	output, err := s.client.GetUserPolicy(ctx, &iam.GetUserPolicyInput{
+		UserName:   &userName,
+		PolicyName: &policyName,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get inline policy: %w", err)
+	}
+
+	decoded, _ := url.QueryUnescape(*output.PolicyDocument)
+	return formatJSON(decoded), nil
+}
+
+func (s *UserService) GetManagedPolicyDocument(ctx context.Context, policyArn string) (string, error) {
+	return getManagedPolicyDocument(ctx, s.client, policyArn)
+}
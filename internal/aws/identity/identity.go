@@ -24,10 +24,15 @@ func GetCallerIdentity(ctx context.Context, awsClient *client.AWSClient) (*Ident
 		return nil, fmt.Errorf("failed to get caller identity: %w", err)
 	}
 
+	return New(*result.Account, *result.UserId, *result.Arn), nil
+}
+
+// New builds an Identity from the raw STS fields, deriving DisplayName from the ARN.
+func New(account, userID, arn string) *Identity {
 	identity := &Identity{
-		Account: *result.Account,
-		UserID:  *result.UserId,
-		ARN:     *result.Arn,
+		Account: account,
+		UserID:  userID,
+		ARN:     arn,
 	}
 
 	// Extract display name from ARN
@@ -47,5 +52,5 @@ func GetCallerIdentity(ctx context.Context, awsClient *client.AWSClient) (*Ident
 		}
 	}
 
-	return identity, nil
+	return identity
 }
@@ -0,0 +1,191 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the user configuration stored in config.yaml. Command-line
+// flags and AWS environment variables take precedence over these values.
+type Config struct {
+	// Profile and Region are used when neither a flag nor AWS_PROFILE /
+	// AWS_REGION selects one
+	Profile string `yaml:"profile,omitempty"`
+	Region  string `yaml:"region,omitempty"`
+	// Refresh starts the role list in watch mode at this interval
+	Refresh time.Duration `yaml:"refresh,omitempty"`
+	// Columns lists the role list columns in display order, e.g.
+	// [name, created, tag:Owner]. Empty means the built-in defaults.
+	Columns []string `yaml:"columns,omitempty"`
+	// KeyBindings maps action names to replacement keys, e.g. refresh: ctrl+r
+	KeyBindings map[string]string `yaml:"keybindings,omitempty"`
+	// Theme names a built-in colour theme
+	Theme string `yaml:"theme,omitempty"`
+	// ReadOnly blocks every AWS API call that could change resources
+	ReadOnly bool `yaml:"read_only,omitempty"`
+	// Scenarios are saved policy simulations, by name, for re-running
+	// against any role with :simulate <name>
+	Scenarios map[string]Scenario `yaml:"scenarios,omitempty"`
+}
+
+// Scenario is a saved policy simulation: every action is simulated against
+// every resource with the given context keys
+type Scenario struct {
+	Actions []string `yaml:"actions"`
+	// Resources defaults to "*" when empty
+	Resources []string       `yaml:"resources,omitempty"`
+	Context   []ContextEntry `yaml:"context,omitempty"`
+}
+
+// ContextEntry is a condition key supplied to a simulation
+type ContextEntry struct {
+	Key string `yaml:"key"`
+	// Type is a simulator key type such as string, stringList or ip; empty
+	// means it is inferred from the values
+	Type   string   `yaml:"type,omitempty"`
+	Values []string `yaml:"values"`
+}
+
+// Path returns the config file location: $XDG_CONFIG_HOME/a3s/config.yaml,
+// falling back to ~/.config/a3s/config.yaml
+func Path() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			home = "."
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "a3s", "config.yaml")
+}
+
+// Load reads the config file. A missing file yields an empty Config.
+func Load() (*Config, error) {
+	path := Path()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// Init writes the commented default config file and returns its path. An
+// existing file is only replaced when force is set.
+func Init(force bool) (string, error) {
+	path := Path()
+	if _, err := os.Stat(path); err == nil && !force {
+		return "", fmt.Errorf("config file %s already exists", path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(DefaultFile), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write config: %w", err)
+	}
+	return path, nil
+}
+
+// SaveColumns stores the role list column selection, leaving the rest of the
+// file, including comments, untouched
+func SaveColumns(columns []string) error {
+	return update("columns", columns)
+}
+
+// SaveScenario stores a policy simulation under name, replacing any
+// scenario of the same name
+func SaveScenario(name string, scenario Scenario) error {
+	cfg, err := Load()
+	if err != nil {
+		return err
+	}
+	scenarios := cfg.Scenarios
+	if scenarios == nil {
+		scenarios = make(map[string]Scenario)
+	}
+	scenarios[name] = scenario
+	return update("scenarios", scenarios)
+}
+
+// update sets a single top-level key in the config file, creating the file
+// when needed. Editing the YAML node tree keeps user comments and ordering.
+func update(key string, value any) error {
+	path := Path()
+
+	var doc yaml.Node
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return fmt.Errorf("failed to read config: %w", err)
+	default:
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("failed to parse config %s: %w", path, err)
+		}
+	}
+
+	// An empty or comment-only file has no document to edit; the new key is
+	// appended to the existing text instead so its comments survive
+	var prefix []byte
+	if len(doc.Content) == 0 {
+		prefix = data
+		if len(prefix) > 0 && prefix[len(prefix)-1] != '\n' {
+			prefix = append(prefix, '\n')
+		}
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("failed to update config %s: top level is not a mapping", path)
+	}
+
+	var valueNode yaml.Node
+	if err := valueNode.Encode(value); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", key, err)
+	}
+	if valueNode.Kind == yaml.SequenceNode {
+		valueNode.Style = yaml.FlowStyle
+	}
+
+	replaced := false
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == key {
+			// Keep any comment attached to the old value
+			valueNode.LineComment = root.Content[i+1].LineComment
+			root.Content[i+1] = &valueNode
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		root.Content = append(root.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: key},
+			&valueNode,
+		)
+	}
+
+	out, err := yaml.Marshal(&doc)
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(prefix, out...), 0o644); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	return nil
+}
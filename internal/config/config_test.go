@@ -0,0 +1,130 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeConfig points XDG_CONFIG_HOME at a temporary directory holding a
+// config file with the given content, or none when content is empty
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	path := Path()
+	if content == "" {
+		return path
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestUpdate(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		value   []string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "missing file",
+			value: []string{"name"},
+			want:  "columns: [name]\n",
+		},
+		{
+			name:    "replace keeps comments and other keys",
+			content: "# my settings\nprofile: dev # work account\ncolumns: [name, arn] # trimmed\nregion: eu-west-1\n",
+			value:   []string{"name", "created"},
+			want:    "# my settings\nprofile: dev # work account\ncolumns: [name, created] # trimmed\nregion: eu-west-1\n",
+		},
+		{
+			name:    "append after existing keys",
+			content: "# my settings\nprofile: dev\n",
+			value:   []string{"name"},
+			want:    "# my settings\nprofile: dev\ncolumns: [name]\n",
+		},
+		{
+			name:    "comment-only file",
+			content: "# columns: [name, arn]\n# theme: dark",
+			value:   []string{"name"},
+			want:    "# columns: [name, arn]\n# theme: dark\ncolumns: [name]\n",
+		},
+		{
+			name:    "non-mapping root",
+			content: "- name\n- arn\n",
+			value:   []string{"name"},
+			want:    "- name\n- arn\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfig(t, tt.content)
+
+			err := update("columns", tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("update() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("config file = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSaveKeepsComments(t *testing.T) {
+	path := writeConfig(t, DefaultFile+"profile: dev # work account\n")
+
+	if err := SaveColumns([]string{"name", "tag:Owner"}); err != nil {
+		t.Fatalf("SaveColumns() error = %v", err)
+	}
+	first := Scenario{Actions: []string{"s3:GetObject"}}
+	if err := SaveScenario("read", first); err != nil {
+		t.Fatalf("SaveScenario() error = %v", err)
+	}
+	second := Scenario{
+		Actions:   []string{"s3:PutObject"},
+		Resources: []string{"arn:aws:s3:::uploads/*"},
+		Context:   []ContextEntry{{Key: "aws:SecureTransport", Values: []string{"true"}}},
+	}
+	if err := SaveScenario("write", second); err != nil {
+		t.Fatalf("SaveScenario() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"# work account", "# a3s configuration"} {
+		if !strings.Contains(string(data), line) {
+			t.Errorf("config file lost %q:\n%s", line, data)
+		}
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Profile != "dev" {
+		t.Errorf("Profile = %q, want dev", cfg.Profile)
+	}
+	if want := []string{"name", "tag:Owner"}; !reflect.DeepEqual(cfg.Columns, want) {
+		t.Errorf("Columns = %v, want %v", cfg.Columns, want)
+	}
+	want := map[string]Scenario{"read": first, "write": second}
+	if !reflect.DeepEqual(cfg.Scenarios, want) {
+		t.Errorf("Scenarios = %+v, want %+v", cfg.Scenarios, want)
+	}
+}
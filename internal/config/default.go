@@ -0,0 +1,49 @@
+package config
+
+// DefaultFile is the commented config written by "a3s config init". Every
+// setting is commented out, so the file starts out with no effect.
+const DefaultFile = `# a3s configuration
+#
+# Command-line flags and the AWS_PROFILE, AWS_REGION and AWS_DEFAULT_REGION
+# environment variables take precedence over the values below.
+
+# Profile and region used when none is given on the command line or in the
+# environment.
+# profile: default
+# region: us-east-1
+
+# Refresh the role list on this interval and highlight changes (watch mode).
+# Accepts Go durations such as 30s or 2m; must be at least 2s.
+# refresh: 30s
+
+# Role list columns, in order. Built-in columns: name, created, lastused,
+# findings, description, path, roleid, arn, maxsession, trust, managed,
+# inline, boundary.
+# tag:<Key> shows the value of a tag. Changed at runtime with :columns.
+# columns: [name, created, lastused, description, findings]
+
+# Key overrides, by action. Actions: up, down, top, bottom, search,
+# sort_name, sort_created, sort_last_used, refresh, region, command, quit,
+# next_tab, prev_tab. Keys use Bubble Tea names such as ctrl+r, f5 or x.
+# keybindings:
+#   refresh: ctrl+r
+#   command: ";"
+
+# Colour theme: aws, light or mono.
+# theme: aws
+
+# Block every AWS API call that could change resources.
+# read_only: false
+
+# Policy simulator scenarios, run against the open role with
+# :simulate <name> and saved from the last simulation with
+# :simulate save <name>. Resources default to "*"; a context key's type is
+# inferred from its values when omitted.
+# scenarios:
+#   deploy:
+#     actions: [cloudformation:CreateStack, iam:PassRole]
+#     resources: ["*"]
+#     context:
+#       - key: aws:RequestedRegion
+#         values: [us-east-1]
+`
@@ -3,12 +3,16 @@ package model
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/johnoct/a3s/internal/aws/client"
 	"github.com/johnoct/a3s/internal/aws/iam"
 	"github.com/johnoct/a3s/internal/aws/identity"
+	"github.com/johnoct/a3s/internal/config"
 	"github.com/johnoct/a3s/internal/ui/components"
+	"github.com/johnoct/a3s/internal/ui/styles"
 )
 
 type State int
@@ -17,17 +21,84 @@ const (
 	StateLoading State = iota
 	StateList
 	StateError
+	StatePicker
+	// StateResource shows the ResourceModel named by App.resource
+	StateResource
 )
 
 type App struct {
-	state       State
-	awsClient   *client.AWSClient
-	roleService *iam.RoleService
-	listModel   components.ListModel
-	identity    *identity.Identity
-	err         error
-	width       int
-	height      int
+	state           State
+	awsClient       *client.AWSClient // nil when running against a fixture
+	roleService     iam.RoleAPI
+	userService     iam.UserAPI
+	groupService    iam.GroupAPI
+	policyService   iam.PolicyAPI
+	profileService  iam.InstanceProfileAPI
+	providerService iam.ProviderAPI
+	listModel       components.ListModel
+	identity        *identity.Identity
+	profile         string
+	region          string
+	resource        string // name of the active resource command, e.g. "roles"
+	err             error
+	width           int
+	height          int
+
+	// listReady is set once roles have loaded into listModel
+	listReady bool
+	// views holds the resource views opened so far, keyed by resource name
+	views map[string]*components.ResourceModel
+	// history holds the views to return to after jumping between resources
+	history []historyEntry
+
+	// Picker shown over the current view, and the state to return to
+	picker       *components.PickerModel
+	pickerReturn State
+	// regionCount is the count shown in the open region picker, if any
+	regionCount *regionCount
+
+	// Command mode and transient status messages
+	commandBar components.CommandBar
+	flash      string
+	flashErr   bool
+	flashSeq   int
+
+	// watchInterval is the watch mode polling interval, zero when off
+	watchInterval time.Duration
+	// columns is the role list column selection
+	columns []components.Column
+	keys    keyRemap
+	// scenarios are the saved policy simulations run with :simulate
+	scenarios map[string]config.Scenario
+	// generation counts profile and region switches. Load and switch
+	// messages carry the generation they were started in, so results that
+	// arrive after a later switch are dropped.
+	generation int
+}
+
+// Options configures how the App connects to IAM.
+type Options struct {
+	Profile string
+	Region  string
+	// Backend selects the IAM data source: "" or "aws" for the live API,
+	// "fixture:<file>" for an offline JSON fixture.
+	Backend string
+	// RecordDir, when set, captures every AWS API response to disk.
+	RecordDir string
+	// ReplayDir, when set, serves AWS API responses from a recording.
+	ReplayDir string
+	// RefreshInterval, when set, starts the list in watch mode.
+	RefreshInterval time.Duration
+	// Columns selects the role list columns by ID; empty means the defaults.
+	Columns []string
+	// KeyBindings overrides keys by action name, e.g. "refresh": "ctrl+r".
+	KeyBindings map[string]string
+	// ReadOnly blocks AWS API calls that could change resources.
+	ReadOnly bool
+	// Scenarios are saved policy simulations, by name.
+	Scenarios map[string]config.Scenario
+	Width     int
+	Height    int
 }
 
 func NewApp(profile, region string) (*App, error) {
@@ -35,36 +106,125 @@ func NewApp(profile, region string) (*App, error) {
 }
 
 func NewAppWithSize(profile, region string, width, height int) (*App, error) {
-	ctx := context.Background()
+	return NewAppWithOptions(Options{
+		Profile: profile,
+		Region:  region,
+		Width:   width,
+		Height:  height,
+	})
+}
+
+func NewAppWithOptions(opts Options) (*App, error) {
+	if opts.RefreshInterval != 0 && opts.RefreshInterval < components.MinWatchInterval {
+		return nil, fmt.Errorf("refresh interval must be at least %s", components.MinWatchInterval)
+	}
+
+	columnIDs := opts.Columns
+	if len(columnIDs) == 0 {
+		columnIDs = components.DefaultColumns
+	}
+	columns, err := components.ResolveColumns(columnIDs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid column configuration: %w", err)
+	}
 
-	awsClient, err := client.New(ctx, profile, region)
+	keys, err := newKeyRemap(opts.KeyBindings)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create AWS client: %w", err)
+		return nil, fmt.Errorf("invalid key bindings: %w", err)
 	}
 
 	app := &App{
-		state:       StateLoading,
-		awsClient:   awsClient,
-		roleService: iam.NewRoleService(awsClient),
-		width:       width,
-		height:      height,
+		keys:          keys,
+		columns:       columns,
+		scenarios:     opts.Scenarios,
+		state:         StateLoading,
+		resource:      "roles",
+		width:         opts.Width,
+		height:        opts.Height,
+		watchInterval: opts.RefreshInterval,
+	}
+	app.commandBar = components.NewCommandBar(app.completeCommand)
+
+	switch {
+	case opts.Backend == "" || opts.Backend == "aws":
+		var clientOpts []client.Option
+		if opts.RecordDir != "" {
+			clientOpts = append(clientOpts, client.WithRecording(opts.RecordDir))
+		}
+		if opts.ReplayDir != "" {
+			clientOpts = append(clientOpts, client.WithReplay(opts.ReplayDir))
+		}
+		if opts.ReadOnly {
+			clientOpts = append(clientOpts, client.WithReadOnly())
+		}
+
+		awsClient, err := client.New(context.Background(), opts.Profile, opts.Region, clientOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create AWS client: %w", err)
+		}
+		app.awsClient = awsClient
+		app.roleService = iam.NewRoleService(awsClient, iam.NewSnapshotStore(awsClient))
+		app.userService = iam.NewUserService(awsClient)
+		app.groupService = iam.NewGroupService(awsClient)
+		app.policyService = iam.NewPolicyService(awsClient)
+		app.profileService = iam.NewInstanceProfileService(awsClient)
+		app.providerService = iam.NewProviderService(awsClient)
+		app.profile = awsClient.Profile
+		app.region = awsClient.Region
+
+	case strings.HasPrefix(opts.Backend, "fixture:"):
+		if opts.RecordDir != "" || opts.ReplayDir != "" {
+			return nil, fmt.Errorf("recording and replay require the aws backend")
+		}
+		fixture, err := iam.LoadFixture(strings.TrimPrefix(opts.Backend, "fixture:"))
+		if err != nil {
+			return nil, err
+		}
+		app.roleService = fixture
+		app.userService = fixture
+		app.groupService = fixture
+		app.policyService = fixture
+		app.profileService = fixture
+		app.providerService = fixture
+		app.identity = fixture.Identity()
+		app.profile = opts.Profile
+		if app.profile == "" {
+			app.profile = "fixture"
+		}
+		app.region = opts.Region
+		if app.region == "" {
+			app.region = "us-east-1"
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown backend %q (expected \"aws\" or \"fixture:<file>\")", opts.Backend)
 	}
 
 	return app, nil
 }
 
 type rolesLoadedMsg struct {
-	roles []iam.Role
+	generation int
+	roles      []iam.Role
 }
 
 type identityLoadedMsg struct {
-	identity *identity.Identity
+	generation int
+	identity   *identity.Identity
 }
 
+// errorMsg reports that the role list failed to load
 type errorMsg struct {
-	err error
+	generation int
+	err        error
+}
+
+type clearFlashMsg struct {
+	seq int
 }
 
+const flashDuration = 4 * time.Second
+
 func (a *App) Init() tea.Cmd {
 	// Return batch of initialization commands
 	return tea.Batch(
@@ -75,85 +235,415 @@ func (a *App) Init() tea.Cmd {
 }
 
 func (a *App) loadRoles() tea.Cmd {
+	roleService, generation := a.roleService, a.generation
 	return func() tea.Msg {
 		ctx := context.Background()
-		roles, err := a.roleService.ListRoles(ctx)
+		roles, err := roleService.ListRoles(ctx)
 		if err != nil {
-			return errorMsg{err: err}
+			return errorMsg{generation: generation, err: err}
 		}
-		return rolesLoadedMsg{roles: roles}
+		return rolesLoadedMsg{generation: generation, roles: roles}
 	}
 }
 
 func (a *App) loadIdentity() tea.Cmd {
+	if a.awsClient == nil {
+		// Fixture backends provide their identity up front
+		return nil
+	}
+	awsClient, generation := a.awsClient, a.generation
 	return func() tea.Msg {
 		ctx := context.Background()
-		id, err := identity.GetCallerIdentity(ctx, a.awsClient)
+		id, err := identity.GetCallerIdentity(ctx, awsClient)
 		if err != nil {
 			// Don't fail the app if we can't get identity
 			// Just return empty identity
-			return identityLoadedMsg{identity: nil}
+			return identityLoadedMsg{generation: generation, identity: nil}
 		}
-		return identityLoadedMsg{identity: id}
+		return identityLoadedMsg{generation: generation, identity: id}
 	}
 }
 
 func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// Apply key binding overrides unless the key is text being typed
+	if key, ok := msg.(tea.KeyMsg); ok && !a.commandBar.Active() && !a.capturingInput() {
+		translated, keep := a.keys.translate(key)
+		if !keep {
+			return a, nil
+		}
+		msg = translated
+	}
+
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		a.width = msg.Width
 		a.height = msg.Height
-		if a.state == StateList {
-			return a.listModel.Update(msg)
-		}
-		return a, nil
+		return a, a.broadcast(msg)
 
 	case rolesLoadedMsg:
-		a.listModel = components.NewListModelWithSize(msg.roles, a.awsClient.Profile, a.awsClient.Region, a.width, a.height)
+		if msg.generation != a.generation {
+			return a, nil
+		}
+		a.listModel = components.NewListModelWithSize(msg.roles, a.profile, a.region, a.width, a.height)
 		a.listModel.SetRoleService(a.roleService)
 		if a.identity != nil {
 			a.listModel.SetIdentity(a.identity)
 		}
-		a.state = StateList
-		return a, a.listModel.Init()
+		a.listReady = true
+		if a.resource == "roles" {
+			a.setState(StateList)
+		}
+		return a, tea.Batch(
+			a.listModel.Init(),
+			a.listModel.SetColumns(a.columns),
+			a.listModel.SetWatch(a.watchInterval),
+		)
 
 	case identityLoadedMsg:
+		if msg.generation != a.generation {
+			return a, nil
+		}
 		a.identity = msg.identity
-		if a.state == StateList {
-			a.listModel.SetIdentity(a.identity)
+		a.listModel.SetIdentity(a.identity)
+		if a.picker != nil {
+			a.picker.SetContext(a.profile, a.region, a.identity)
+		}
+		for _, view := range a.views {
+			view.SetContext(a.profile, a.region, a.identity)
 		}
 		return a, nil
 
 	case errorMsg:
+		if msg.generation != a.generation {
+			return a, nil
+		}
+		if a.resource != "roles" {
+			// The role list failed to load behind another view
+			return a, a.flashError(msg.err)
+		}
 		a.err = msg.err
-		a.state = StateError
+		a.setState(StateError)
+		return a, nil
+
+	case components.CommandMsg:
+		return a, a.runCommand(msg.Line)
+
+	case clientSwitchedMsg:
+		if msg.generation != a.generation {
+			// A later switch supersedes this one
+			return a, nil
+		}
+		if msg.err != nil {
+			// The client is unchanged, but loads started before the switch
+			// now carry a stale generation; start the pending ones again
+			cmds := []tea.Cmd{a.flashError(msg.err)}
+			if !a.listReady {
+				cmds = append(cmds, a.loadRoles())
+			}
+			if a.identity == nil {
+				cmds = append(cmds, a.loadIdentity())
+			}
+			return a, tea.Batch(cmds...)
+		}
+		a.awsClient = msg.client
+		a.roleService = iam.NewRoleService(msg.client, iam.NewSnapshotStore(msg.client))
+		a.userService = iam.NewUserService(msg.client)
+		a.groupService = iam.NewGroupService(msg.client)
+		a.policyService = iam.NewPolicyService(msg.client)
+		a.profileService = iam.NewInstanceProfileService(msg.client)
+		a.providerService = iam.NewProviderService(msg.client)
+		a.profile = msg.client.Profile
+		a.region = msg.client.Region
+		a.identity = nil
+		a.listReady = false
+		a.views = nil
+		a.history = nil
+		a.setState(StateLoading)
+		cmds := []tea.Cmd{
+			a.loadRoles(),
+			a.loadIdentity(),
+			a.flashInfo(fmt.Sprintf("switched to profile %s in %s", profileLabel(a.profile), a.region)),
+		}
+		if a.resource != "roles" {
+			cmds = append(cmds, a.showResource(a.resource))
+		}
+		return a, tea.Batch(cmds...)
+
+	case components.OpenResourceMsg:
+		return a, a.jumpTo(msg)
+
+	case components.ResourceBackMsg:
+		return a, a.back(msg.Err)
+
+	case components.PickedMsg:
+		return a, a.handlePicked(msg)
+
+	case components.PickerClosedMsg:
+		a.closePicker()
+		return a, nil
+
+	case clearFlashMsg:
+		if msg.seq == a.flashSeq {
+			a.flash = ""
+		}
 		return a, nil
 
 	case tea.KeyMsg:
+		if a.commandBar.Active() {
+			var cmd tea.Cmd
+			a.commandBar, cmd = a.commandBar.Update(msg)
+			return a, cmd
+		}
+		if msg.String() == ":" && !a.capturingInput() {
+			a.flash = ""
+			return a, a.commandBar.Open()
+		}
 		if a.state == StateError && msg.String() == "q" {
 			return a, tea.Quit
 		}
 	}
 
-	// Forward messages to list model when in list state
-	if a.state == StateList {
+	if _, ok := msg.(tea.KeyMsg); !ok {
+		return a, a.broadcast(msg)
+	}
+
+	// Keys go to the active view only
+	switch a.state {
+	case StatePicker:
+		_, cmd := a.picker.Update(msg)
+		return a, cmd
+	case StateList:
 		updatedModel, cmd := a.listModel.Update(msg)
 		a.listModel = updatedModel.(components.ListModel)
 		return a, cmd
+	case StateResource:
+		_, cmd := a.views[a.resource].Update(msg)
+		return a, cmd
 	}
 
 	return a, nil
 }
 
+// broadcast delivers a non-key message to every view, so results of
+// background loads reach a view even while another one is on screen
+func (a *App) broadcast(msg tea.Msg) tea.Cmd {
+	var cmds []tea.Cmd
+	if a.picker != nil {
+		_, cmd := a.picker.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+	if a.listReady {
+		updatedModel, cmd := a.listModel.Update(msg)
+		a.listModel = updatedModel.(components.ListModel)
+		cmds = append(cmds, cmd)
+		// Last uses arriving in the background update the region counts
+		a.updateRegionCounts()
+	}
+	for _, view := range a.views {
+		_, cmd := view.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+	return tea.Batch(cmds...)
+}
+
+// showResource switches to the view of a resource command, creating and
+// loading it on first use. An existing view is shown as it was left.
+func (a *App) showResource(name string) tea.Cmd {
+	a.resource = name
+	if _, ok := a.views[name]; ok {
+		a.setState(StateResource)
+		return nil
+	}
+
+	var source components.ResourceSource
+	switch name {
+	case "users":
+		source = components.NewUserSource(a.userService)
+	case "groups":
+		source = components.NewGroupSource(a.groupService)
+	case "policies":
+		source = components.NewPolicySource(a.policyService)
+	case "instanceprofiles":
+		source = components.NewInstanceProfileSource(a.profileService)
+	case "oidc":
+		source = components.NewOIDCProviderSource(a.providerService, a.roleService)
+	case "saml":
+		source = components.NewSAMLProviderSource(a.providerService, a.roleService)
+	default:
+		return a.flashError(fmt.Errorf("%s view is not available yet", name))
+	}
+
+	view := components.NewResourceModel(source)
+	view.SetContext(a.profile, a.region, a.identity)
+	view.Update(tea.WindowSizeMsg{Width: a.width, Height: a.height})
+	if a.views == nil {
+		a.views = make(map[string]*components.ResourceModel)
+	}
+	a.views[name] = view
+	a.setState(StateResource)
+	return view.Init()
+}
+
+// profileLabel names the profile for display; an empty profile means the
+// SDK's default credential chain
+func profileLabel(profile string) string {
+	if profile == "" {
+		return "default"
+	}
+	return profile
+}
+
+// setState changes the App state, keeping an open picker on top
+func (a *App) setState(s State) {
+	if a.state == StatePicker {
+		a.pickerReturn = s
+		return
+	}
+	a.state = s
+}
+
+// capturingInput reports whether the active view is reading free text, in
+// which case ':' must reach it instead of opening the command bar
+func (a *App) capturingInput() bool {
+	switch a.state {
+	case StateList:
+		return a.listModel.CapturingInput()
+	case StatePicker:
+		return a.picker.CapturingInput()
+	case StateResource:
+		return a.views[a.resource].CapturingInput()
+	}
+	return false
+}
+
+func (a *App) flashInfo(text string) tea.Cmd {
+	return a.setFlash(text, false)
+}
+
+func (a *App) flashError(err error) tea.Cmd {
+	return a.setFlash(err.Error(), true)
+}
+
+func (a *App) setFlash(text string, isErr bool) tea.Cmd {
+	a.flash = text
+	a.flashErr = isErr
+	a.flashSeq++
+	seq := a.flashSeq
+	return tea.Tick(flashDuration, func(time.Time) tea.Msg {
+		return clearFlashMsg{seq: seq}
+	})
+}
+
 func (a *App) View() string {
+	var view string
 	switch a.state {
 	case StateLoading:
-		return "\n  Loading IAM roles... ⚡\n"
+		view = "\n  Loading IAM roles... ⚡\n"
 	case StateError:
-		return fmt.Sprintf("\n  Error: %v\n\n  Press 'q' to quit.\n", a.err)
+		view = fmt.Sprintf("\n  Error: %v\n\n  Press ':' for commands or 'q' to quit.\n", a.err)
 	case StateList:
-		return a.listModel.View()
+		view = a.listModel.View()
+	case StatePicker:
+		view = a.picker.View()
+	case StateResource:
+		view = a.views[a.resource].View()
+	}
+	return a.withFooter(view)
+}
+
+// withFooter shows the command bar or a flash message in place of the help
+// line at the bottom of the screen
+func (a *App) withFooter(view string) string {
+	var footer string
+	switch {
+	case a.commandBar.Active():
+		footer = a.commandBar.View(a.width)
+	case a.flash != "" && a.flashErr:
+		footer = styles.ErrorStyle.Render(a.flash)
+	case a.flash != "":
+		footer = styles.SearchInfo.Render(a.flash)
 	default:
-		return ""
+		return view
+	}
+
+	if a.state != StateList && a.state != StatePicker && a.state != StateResource {
+		return view + "\n" + footer
+	}
+	if i := strings.LastIndex(view, "\n"); i >= 0 {
+		return view[:i+1] + footer
+	}
+	return footer
+}
+
+// historyEntry records a view left by a jump: the resource and the detail
+// that was open in it
+type historyEntry struct {
+	resource string
+	id       string
+	jumped   bool
+}
+
+// jumpTo opens the detail of a resource from another view, remembering the
+// current view for ResourceBackMsg
+func (a *App) jumpTo(msg components.OpenResourceMsg) tea.Cmd {
+	entry := historyEntry{resource: a.resource}
+	switch view, ok := a.views[a.resource]; {
+	case a.state == StateList:
+		entry.id, entry.jumped = a.listModel.CurrentRole()
+	case ok && a.state == StateResource:
+		entry.id, entry.jumped = view.Current()
+	}
+
+	if msg.Resource == "roles" {
+		if !a.listReady {
+			return a.flashError(fmt.Errorf("roles are still loading"))
+		}
+		a.history = append(a.history, entry)
+		a.resource = "roles"
+		a.setState(StateList)
+		return a.listModel.OpenRole(msg.ID, true)
+	}
+
+	cmd := a.showResource(msg.Resource)
+	view, ok := a.views[msg.Resource]
+	if !ok {
+		return cmd
+	}
+	a.history = append(a.history, entry)
+	openCmd := view.Open(msg.ID, true)
+	view.OpenTab(msg.Tab)
+	return tea.Batch(cmd, openCmd)
+}
+
+// back returns to the view the last jump came from, reopening the detail
+// that was shown there
+func (a *App) back(err error) tea.Cmd {
+	var cmds []tea.Cmd
+	if err != nil {
+		cmds = append(cmds, a.flashError(err))
+	}
+	if len(a.history) == 0 {
+		return tea.Batch(cmds...)
+	}
+	entry := a.history[len(a.history)-1]
+	a.history = a.history[:len(a.history)-1]
+
+	if entry.resource == "roles" {
+		a.resource = "roles"
+		if !a.listReady {
+			return tea.Batch(cmds...)
+		}
+		a.setState(StateList)
+		if name, _ := a.listModel.CurrentRole(); entry.id != "" && name != entry.id {
+			cmds = append(cmds, a.listModel.OpenRole(entry.id, entry.jumped))
+		}
+		return tea.Batch(cmds...)
+	}
+
+	cmds = append(cmds, a.showResource(entry.resource))
+	view := a.views[entry.resource]
+	if id, _ := view.Current(); entry.id != "" && id != entry.id {
+		cmds = append(cmds, view.Open(entry.id, entry.jumped))
 	}
+	return tea.Batch(cmds...)
 }
@@ -0,0 +1,110 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/johnoct/a3s/internal/aws/iam"
+	"github.com/johnoct/a3s/internal/aws/identity"
+)
+
+var errTest = errors.New("test")
+
+func TestStaleLoadsAreDropped(t *testing.T) {
+	a, err := NewAppWithOptions(Options{Backend: "fixture:../../examples/fixture.json", Width: 80, Height: 24})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A switch was requested after these loads started
+	a.generation++
+	roles := []iam.Role{{Name: "old-account-role", ARN: "arn:aws:iam::210987654321:role/old-account-role"}}
+
+	tests := []struct {
+		name  string
+		msg   any
+		check func() bool
+	}{
+		{"roles", rolesLoadedMsg{generation: 0, roles: roles}, func() bool { return !a.listReady }},
+		{"identity", identityLoadedMsg{generation: 0, identity: &identity.Identity{Account: "210987654321"}}, func() bool { return a.identity == nil }},
+		{"load error", errorMsg{generation: 0, err: errTest}, func() bool { return a.state != StateError }},
+		{"switch", clientSwitchedMsg{generation: 0, err: errTest}, func() bool { return a.flash == "" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a.identity = nil
+			a.Update(tt.msg)
+			if !tt.check() {
+				t.Errorf("Update(%T) from an earlier generation was applied", tt.msg)
+			}
+		})
+	}
+
+	a.Update(rolesLoadedMsg{generation: a.generation, roles: roles})
+	if !a.listReady {
+		t.Errorf("Update(rolesLoadedMsg) from the current generation was dropped")
+	}
+}
+
+// drain runs a command and every command its results lead to, skipping
+// spinner ticks, which never end
+func drain(a *App, cmd tea.Cmd) {
+	if cmd == nil {
+		return
+	}
+	switch msg := cmd().(type) {
+	case tea.BatchMsg:
+		for _, c := range msg {
+			drain(a, c)
+		}
+	case spinner.TickMsg, nil:
+	default:
+		_, next := a.Update(msg)
+		drain(a, next)
+	}
+}
+
+func TestRegionPickerCounts(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource string
+		want     []string
+	}{
+		{"roles by last use", "roles", []string{"Roles Used", "counting roles by the region they were last used in"}},
+		{"global resource", "users", []string{"users are global, per-region counts do not apply"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := NewAppWithOptions(Options{Backend: "fixture:../../examples/fixture.json", Width: 120, Height: 60})
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, cmd := a.Update(a.loadRoles()())
+			a.resource = tt.resource
+			a.openRegionPicker()
+			if tt.resource == "roles" && !strings.Contains(a.picker.View(), "still loading") {
+				t.Errorf("region picker does not say the last use is still loading:\n%s", a.picker.View())
+			}
+
+			drain(a, cmd)
+			view := a.picker.View()
+			for _, want := range tt.want {
+				if !strings.Contains(view, want) {
+					t.Errorf("region picker lacks %q:\n%s", want, view)
+				}
+			}
+			if strings.Contains(view, "still loading") {
+				t.Errorf("region picker still loading after every last use arrived:\n%s", view)
+			}
+			if tt.resource != "roles" {
+				return
+			}
+			counts, _ := a.listModel.LastUseRegions()
+			if counts["eu-west-1"] == 0 || counts["us-east-1"] == 0 {
+				t.Errorf("LastUseRegions() = %v, want roles counted in eu-west-1 and us-east-1", counts)
+			}
+		})
+	}
+}
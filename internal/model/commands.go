@@ -0,0 +1,499 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/johnoct/a3s/internal/aws/client"
+	"github.com/johnoct/a3s/internal/aws/iam"
+	"github.com/johnoct/a3s/internal/config"
+	"github.com/johnoct/a3s/internal/ui/components"
+)
+
+// command is an entry in the ":" command registry. Resource views register
+// here so they share one navigation entry point.
+type command struct {
+	name    string
+	aliases []string
+	// complete returns candidate values for the command's argument
+	complete func(a *App, arg string) []string
+	run      func(a *App, args []string) tea.Cmd
+	// regionCount counts the resource per region in the region picker. IAM
+	// is global, so it is nil unless the resource has a regional side.
+	regionCount *regionCount
+}
+
+// regionCount is a per-region count shown as a region picker column
+type regionCount struct {
+	// title heads the column and what names the counted resource
+	title string
+	what  string
+	// count returns the count per region and how many resources are still
+	// loading
+	count func(a *App) (counts map[string]int, loading int)
+}
+
+// commands is the registry of ":" commands. It is filled in init because
+// handlers such as the region picker consult the registry themselves.
+var commands []command
+
+func init() {
+	commands = []command{
+		{
+			name:    "roles",
+			aliases: []string{"role", "ro"},
+			run:     (*App).cmdRoles,
+			// Roles are global, but GetRole reports the region each was
+			// last used in; the list fetches it concurrently in the
+			// background
+			regionCount: &regionCount{
+				title: "Roles Used",
+				what:  "roles by the region they were last used in",
+				count: func(a *App) (map[string]int, int) { return a.listModel.LastUseRegions() },
+			},
+		},
+		{
+			name:    "users",
+			aliases: []string{"user", "usr"},
+			run:     resourceCommand("users"),
+		},
+		{
+			name:    "groups",
+			aliases: []string{"group", "grp"},
+			run:     resourceCommand("groups"),
+		},
+		{
+			name:     "policies",
+			aliases:  []string{"policy", "pol"},
+			complete: (*App).completePolicyScope,
+			run:      (*App).cmdPolicies,
+		},
+		{
+			name:    "instanceprofiles",
+			aliases: []string{"instanceprofile", "ip"},
+			run:     resourceCommand("instanceprofiles"),
+		},
+		{
+			name:    "oidc",
+			aliases: []string{"oidcproviders", "oidc-provider"},
+			run:     resourceCommand("oidc"),
+		},
+		{
+			name:    "saml",
+			aliases: []string{"samlproviders", "saml-provider"},
+			run:     resourceCommand("saml"),
+		},
+		{
+			name:     "profile",
+			aliases:  []string{"ctx"},
+			complete: (*App).completeProfile,
+			run:      (*App).cmdProfile,
+		},
+		{
+			name:     "region",
+			complete: (*App).completeRegion,
+			run:      (*App).cmdRegion,
+		},
+		{
+			name:     "columns",
+			aliases:  []string{"cols"},
+			complete: (*App).completeColumns,
+			run:      (*App).cmdColumns,
+		},
+		{
+			name:     "simulate",
+			aliases:  []string{"sim"},
+			complete: (*App).completeSimulate,
+			run:      (*App).cmdSimulate,
+		},
+		{
+			name: "watch",
+			run:  (*App).cmdWatch,
+		},
+		{
+			name:    "quit",
+			aliases: []string{"q", "q!", "exit"},
+			run: func(a *App, args []string) tea.Cmd {
+				return tea.Quit
+			},
+		},
+	}
+}
+
+func lookupCommand(name string) (command, bool) {
+	for _, c := range commands {
+		if c.name == name {
+			return c, true
+		}
+		for _, alias := range c.aliases {
+			if alias == name {
+				return c, true
+			}
+		}
+	}
+	return command{}, false
+}
+
+// runCommand parses and dispatches a line submitted from the command bar
+func (a *App) runCommand(line string) tea.Cmd {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	c, ok := lookupCommand(fields[0])
+	if !ok {
+		return a.flashError(fmt.Errorf("unknown command %q", fields[0]))
+	}
+	return c.run(a, fields[1:])
+}
+
+// completeCommand returns completions for a partially typed command line
+func (a *App) completeCommand(line string) []string {
+	name, arg, hasArg := strings.Cut(strings.TrimLeft(line, " "), " ")
+
+	if !hasArg {
+		var names []string
+		for _, c := range commands {
+			if strings.HasPrefix(c.name, name) {
+				names = append(names, c.name)
+			}
+		}
+		return names
+	}
+
+	c, ok := lookupCommand(name)
+	if !ok || c.complete == nil {
+		return nil
+	}
+
+	var lines []string
+	for _, candidate := range c.complete(a, strings.TrimSpace(arg)) {
+		lines = append(lines, c.name+" "+candidate)
+	}
+	return lines
+}
+
+func (a *App) cmdRoles(args []string) tea.Cmd {
+	a.resource = "roles"
+	a.history = nil
+	if a.listReady {
+		a.listModel.CloseDetail()
+		a.setState(StateList)
+		return nil
+	}
+	a.setState(StateLoading)
+	return a.loadRoles()
+}
+
+// resourceCommand shows the list of a ResourceModel backed resource
+func resourceCommand(resource string) func(a *App, args []string) tea.Cmd {
+	return func(a *App, args []string) tea.Cmd {
+		a.history = nil
+		cmd := a.showResource(resource)
+		if view, ok := a.views[resource]; ok {
+			view.CloseDetail()
+		}
+		return cmd
+	}
+}
+
+// cmdPolicies shows the managed policy list, optionally switching its scope
+// to local (customer managed), aws or all
+func (a *App) cmdPolicies(args []string) tea.Cmd {
+	cmd := resourceCommand("policies")(a, nil)
+	if len(args) == 0 {
+		return cmd
+	}
+	scopeCmd, err := a.views["policies"].SetScope(args[0])
+	if err != nil {
+		return tea.Batch(cmd, a.flashError(err))
+	}
+	return tea.Batch(cmd, scopeCmd)
+}
+
+func (a *App) completePolicyScope(arg string) []string {
+	var scopes []string
+	for _, scope := range iam.PolicyScopes {
+		if s := strings.ToLower(string(scope)); strings.HasPrefix(s, strings.ToLower(arg)) {
+			scopes = append(scopes, s)
+		}
+	}
+	return scopes
+}
+
+// cmdColumns shows or changes the role list columns. Arguments are column
+// IDs, separated by spaces or commas: a plain list replaces the selection,
+// +id and -id add or remove one column, and "reset" restores the defaults.
+// The selection is saved to the config file.
+func (a *App) cmdColumns(args []string) tea.Cmd {
+	current := make([]string, len(a.columns))
+	for i, c := range a.columns {
+		current[i] = c.ID
+	}
+
+	var ids []string
+	for _, arg := range args {
+		for _, id := range strings.Split(arg, ",") {
+			if id != "" {
+				ids = append(ids, id)
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return a.flashInfo("columns: " + strings.Join(current, ", "))
+	}
+
+	var selected []string
+	switch {
+	case len(ids) == 1 && ids[0] == "reset":
+		selected = components.DefaultColumns
+	case strings.HasPrefix(ids[0], "+") || strings.HasPrefix(ids[0], "-"):
+		selected = current
+		for _, id := range ids {
+			switch {
+			case strings.HasPrefix(id, "+"):
+				selected = append(slices.DeleteFunc(selected, func(s string) bool { return s == id[1:] }), id[1:])
+			case strings.HasPrefix(id, "-"):
+				selected = slices.DeleteFunc(selected, func(s string) bool { return s == id[1:] })
+			default:
+				return a.flashError(fmt.Errorf("cannot mix column list and +/- edits"))
+			}
+		}
+	default:
+		selected = ids
+	}
+
+	columns, err := components.ResolveColumns(selected)
+	if err != nil {
+		return a.flashError(err)
+	}
+	a.columns = columns
+
+	// Before the role list has loaded, rolesLoadedMsg applies the columns
+	var cmd tea.Cmd
+	if a.listReady {
+		cmd = a.listModel.SetColumns(columns)
+	}
+	if err := config.SaveColumns(selected); err != nil {
+		return tea.Batch(cmd, a.flashError(err))
+	}
+	return tea.Batch(cmd, a.flashInfo("columns: "+strings.Join(selected, ", ")))
+}
+
+// completeColumns completes the last column ID of the argument
+func (a *App) completeColumns(arg string) []string {
+	head, last := "", arg
+	if i := strings.LastIndexAny(arg, ", "); i >= 0 {
+		head, last = arg[:i+1], arg[i+1:]
+	}
+	prefix := ""
+	if strings.HasPrefix(last, "+") || strings.HasPrefix(last, "-") {
+		prefix, last = last[:1], last[1:]
+	}
+
+	var candidates []string
+	for _, id := range append(components.ColumnIDs(), "reset") {
+		if strings.HasPrefix(id, last) {
+			candidates = append(candidates, head+prefix+id)
+		}
+	}
+	return candidates
+}
+
+// cmdSimulate runs a saved policy simulation scenario against the open
+// role, or with "save <name>" stores the simulation last run against it
+func (a *App) cmdSimulate(args []string) tea.Cmd {
+	if len(args) == 0 {
+		names := slices.Sorted(maps.Keys(a.scenarios))
+		if len(names) == 0 {
+			return a.flashInfo("no saved scenarios; press s in a role to simulate, then :simulate save <name>")
+		}
+		return a.flashInfo("scenarios: " + strings.Join(names, ", "))
+	}
+
+	if args[0] == "save" {
+		if len(args) != 2 {
+			return a.flashError(fmt.Errorf("usage: simulate save <name>"))
+		}
+		sim := a.listModel.LastSimulation()
+		if a.state != StateList || sim == nil {
+			return a.flashError(fmt.Errorf("no simulation to save; press s in a role to run one"))
+		}
+		scenario := scenarioFromSimulation(*sim)
+		if err := config.SaveScenario(args[1], scenario); err != nil {
+			return a.flashError(err)
+		}
+		if a.scenarios == nil {
+			a.scenarios = make(map[string]config.Scenario)
+		}
+		a.scenarios[args[1]] = scenario
+		return a.flashInfo("saved scenario " + args[1])
+	}
+
+	scenario, ok := a.scenarios[args[0]]
+	if !ok {
+		return a.flashError(fmt.Errorf("unknown scenario %q", args[0]))
+	}
+	if len(scenario.Actions) == 0 {
+		return a.flashError(fmt.Errorf("scenario %q has no actions", args[0]))
+	}
+	if a.state != StateList {
+		return a.flashError(fmt.Errorf("open a role to run scenario %q against", args[0]))
+	}
+	cmd, ok := a.listModel.SimulateOpenRole(simulationFromScenario(scenario))
+	if !ok {
+		return a.flashError(fmt.Errorf("open a role to run scenario %q against", args[0]))
+	}
+	return tea.Batch(cmd, a.flashInfo("simulating "+args[0]))
+}
+
+// completeSimulate completes scenario names and "save"
+func (a *App) completeSimulate(arg string) []string {
+	var candidates []string
+	for _, name := range append(slices.Sorted(maps.Keys(a.scenarios)), "save") {
+		if strings.HasPrefix(name, arg) {
+			candidates = append(candidates, name)
+		}
+	}
+	return candidates
+}
+
+func simulationFromScenario(s config.Scenario) iam.Simulation {
+	sim := iam.Simulation{Actions: s.Actions, Resources: s.Resources}
+	for _, e := range s.Context {
+		typ := e.Type
+		if typ == "" {
+			typ = iam.InferContextType(e.Values)
+		}
+		sim.Context = append(sim.Context, iam.ContextEntry{Key: e.Key, Type: typ, Values: e.Values})
+	}
+	return sim
+}
+
+func scenarioFromSimulation(sim iam.Simulation) config.Scenario {
+	s := config.Scenario{Actions: sim.Actions, Resources: sim.Resources}
+	for _, e := range sim.Context {
+		s.Context = append(s.Context, config.ContextEntry{Key: e.Key, Type: e.Type, Values: e.Values})
+	}
+	return s
+}
+
+// defaultWatchInterval is used by :watch when no interval is given
+const defaultWatchInterval = 30 * time.Second
+
+// cmdWatch toggles watch mode. With an argument it (re)starts watching at
+// that interval; "off" stops it.
+func (a *App) cmdWatch(args []string) tea.Cmd {
+	interval := defaultWatchInterval
+	switch {
+	case len(args) > 0 && args[0] == "off":
+		interval = 0
+	case len(args) > 0:
+		d, err := time.ParseDuration(args[0])
+		if err != nil {
+			return a.flashError(fmt.Errorf("invalid watch interval %q", args[0]))
+		}
+		if d < components.MinWatchInterval {
+			return a.flashError(fmt.Errorf("watch interval must be at least %s", components.MinWatchInterval))
+		}
+		interval = d
+	case a.watchInterval > 0:
+		interval = 0
+	}
+
+	a.watchInterval = interval
+	// The role list polls even behind other views; before it has loaded,
+	// rolesLoadedMsg applies the interval
+	var cmd tea.Cmd
+	if a.listReady {
+		cmd = a.listModel.SetWatch(interval)
+	}
+	if interval == 0 {
+		return tea.Batch(cmd, a.flashInfo("watch mode off"))
+	}
+	return tea.Batch(cmd, a.flashInfo(fmt.Sprintf("watching every %s", interval)))
+}
+
+func (a *App) cmdProfile(args []string) tea.Cmd {
+	if a.awsClient == nil {
+		return a.flashError(fmt.Errorf("profile switching requires the aws backend"))
+	}
+	if len(args) == 0 {
+		return a.openProfilePicker()
+	}
+	return a.switchProfile(args[0])
+}
+
+func (a *App) switchProfile(profile string) tea.Cmd {
+	a.generation++
+	current, generation := *a.awsClient, a.generation
+	return func() tea.Msg {
+		c := current
+		if err := c.SwitchProfile(context.Background(), profile); err != nil {
+			return clientSwitchedMsg{generation: generation, err: fmt.Errorf("failed to switch to profile %s: %w", profile, err)}
+		}
+		return clientSwitchedMsg{generation: generation, client: &c}
+	}
+}
+
+func (a *App) completeProfile(arg string) []string {
+	profiles, err := client.LoadProfiles()
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, p := range profiles {
+		if strings.HasPrefix(p.Name, arg) {
+			names = append(names, p.Name)
+		}
+	}
+	return names
+}
+
+func (a *App) cmdRegion(args []string) tea.Cmd {
+	if a.awsClient == nil {
+		return a.flashError(fmt.Errorf("region switching requires the aws backend"))
+	}
+	if len(args) == 0 {
+		return a.openRegionPicker()
+	}
+	return a.switchRegion(args[0])
+}
+
+func (a *App) completeRegion(arg string) []string {
+	var codes []string
+	for _, p := range client.Partitions {
+		for _, r := range p.Regions {
+			if strings.HasPrefix(r.Code, arg) {
+				codes = append(codes, r.Code)
+			}
+		}
+	}
+	return codes
+}
+
+func (a *App) switchRegion(region string) tea.Cmd {
+	a.generation++
+	current, generation := *a.awsClient, a.generation
+	return func() tea.Msg {
+		c := current
+		if err := c.SwitchRegion(context.Background(), region); err != nil {
+			return clientSwitchedMsg{generation: generation, err: fmt.Errorf("failed to switch to region %s: %w", region, err)}
+		}
+		return clientSwitchedMsg{generation: generation, client: &c}
+	}
+}
+
+// clientSwitchedMsg carries a rebuilt AWS client after a profile or region
+// change, requested in generation
+type clientSwitchedMsg struct {
+	generation int
+	client     *client.AWSClient
+	err        error
+}
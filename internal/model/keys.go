@@ -0,0 +1,114 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// defaultKeys maps each rebindable action to the key the views handle
+var defaultKeys = map[string]string{
+	"up":             "k",
+	"down":           "j",
+	"top":            "g",
+	"bottom":         "G",
+	"search":         "/",
+	"sort_name":      "N",
+	"sort_created":   "C",
+	"sort_last_used": "L",
+	"refresh":        "r",
+	"region":         "R",
+	"command":        ":",
+	"quit":           "q",
+	"next_tab":       "l",
+	"prev_tab":       "h",
+}
+
+// fixedKeys are handled by the views but cannot be rebound, mapped to what
+// they do
+var fixedKeys = map[string]string{
+	"enter":     "open",
+	"esc":       "back",
+	"tab":       "next_tab",
+	"shift+tab": "prev_tab",
+	"up":        "up",
+	"down":      "down",
+	"ctrl+c":    "quit",
+	"b":         "open the boundary",
+	"c":         "can it do",
+	"n":         "next match",
+	"s":         "simulate and scope",
+	"u":         "used by",
+}
+
+// keyRemap applies keybinding overrides. The views only know the default
+// keys, so an override is implemented by rewriting the custom key into the
+// default one before the key reaches them.
+type keyRemap struct {
+	custom  map[string]tea.KeyMsg
+	unbound map[string]bool
+}
+
+func newKeyRemap(overrides map[string]string) (keyRemap, error) {
+	r := keyRemap{
+		custom:  make(map[string]tea.KeyMsg),
+		unbound: make(map[string]bool),
+	}
+
+	actions := make([]string, 0, len(overrides))
+	for action := range overrides {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+
+	for _, action := range actions {
+		key := overrides[action]
+		def, ok := defaultKeys[action]
+		if !ok {
+			return keyRemap{}, fmt.Errorf("unknown key binding action %q", action)
+		}
+		if key == "" || key == def {
+			continue
+		}
+		if _, taken := r.custom[key]; taken {
+			return keyRemap{}, fmt.Errorf("key %q is bound to more than one action", key)
+		}
+		r.custom[key] = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(def)}
+		r.unbound[def] = true
+	}
+
+	// A custom key must not take over a key another action still uses,
+	// which would silently make that action unreachable
+	for _, action := range actions {
+		key := overrides[action]
+		if _, ok := r.custom[key]; !ok {
+			continue
+		}
+		if use, ok := fixedKeys[key]; ok {
+			return keyRemap{}, fmt.Errorf("key %q for %s is already used by %s", key, action, use)
+		}
+		if r.unbound[key] {
+			continue
+		}
+		for other, def := range defaultKeys {
+			if def == key {
+				return keyRemap{}, fmt.Errorf("key %q for %s is already bound to %s; rebind %s too", key, action, other, other)
+			}
+		}
+	}
+	return r, nil
+}
+
+// translate rewrites a key press. It returns false for a default key whose
+// action was moved elsewhere, which should then be ignored.
+func (r keyRemap) translate(msg tea.KeyMsg) (tea.KeyMsg, bool) {
+	key := msg.String()
+	if mapped, ok := r.custom[key]; ok {
+		return mapped, true
+	}
+	if r.unbound[key] {
+		return msg, false
+	}
+	return msg, true
+}
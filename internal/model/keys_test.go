@@ -0,0 +1,30 @@
+package model
+
+import (
+	"testing"
+)
+
+func TestNewKeyRemap(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[string]string
+		wantErr   bool
+	}{
+		{"free key", map[string]string{"refresh": "ctrl+r"}, false},
+		{"default key", map[string]string{"refresh": "r"}, false},
+		{"swap", map[string]string{"up": "j", "down": "k"}, false},
+		{"key of a moved action", map[string]string{"up": "ctrl+p", "refresh": "k"}, false},
+		{"key of another action", map[string]string{"refresh": "k"}, true},
+		{"fixed key", map[string]string{"refresh": "s"}, true},
+		{"two actions on one key", map[string]string{"refresh": "x", "quit": "x"}, true},
+		{"unknown action", map[string]string{"reload": "x"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newKeyRemap(tt.overrides)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("newKeyRemap(%v) error = %v, wantErr %v", tt.overrides, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/johnoct/a3s/internal/aws/client"
+	"github.com/johnoct/a3s/internal/ui/components"
+)
+
+const profilePicker = "profile"
+
+// openPicker shows a picker over the current view; closing it returns to
+// whatever state the App was in before
+func (a *App) openPicker(p *components.PickerModel) {
+	p.SetContext(a.profile, a.region, a.identity)
+	p.Update(tea.WindowSizeMsg{Width: a.width, Height: a.height})
+	if a.state != StatePicker {
+		a.pickerReturn = a.state
+	}
+	a.picker = p
+	a.regionCount = nil
+	a.state = StatePicker
+}
+
+func (a *App) closePicker() {
+	a.picker = nil
+	a.regionCount = nil
+	a.state = a.pickerReturn
+}
+
+func (a *App) openProfilePicker() tea.Cmd {
+	profiles, err := client.LoadProfiles()
+	if err != nil {
+		return a.flashError(err)
+	}
+	if len(profiles) == 0 {
+		return a.flashError(fmt.Errorf("no profiles found in %s or %s", client.ConfigFilePath(), client.CredentialsFilePath()))
+	}
+
+	items := make([]components.PickerItem, len(profiles))
+	for i, p := range profiles {
+		items[i] = components.PickerItem{
+			Value:   p.Name,
+			Columns: []string{p.Kind(), p.Region, p.Target()},
+			Current: p.Name == a.profile || (a.profile == "" && p.Name == "default"),
+		}
+	}
+
+	a.openPicker(components.NewPickerModel(profilePicker, "AWS Profiles", []string{"Profile", "Type", "Region", "Target"}, items))
+	return nil
+}
+
+func (a *App) handlePicked(msg components.PickedMsg) tea.Cmd {
+	a.closePicker()
+	switch msg.Picker {
+	case profilePicker:
+		return a.switchProfile(msg.Value)
+	case regionPicker:
+		return a.switchRegion(msg.Value)
+	}
+	return nil
+}
+
+const regionPicker = "region"
+
+func (a *App) openRegionPicker() tea.Cmd {
+	current := client.PartitionOf(a.region)
+	res, _ := lookupCommand(a.resource)
+	counted := res.regionCount
+	status := fmt.Sprintf("%s are global, per-region counts do not apply", a.resource)
+	if counted != nil && !a.listReady {
+		counted = nil
+		status = fmt.Sprintf("%s are still loading, per-region counts are not available", a.resource)
+	}
+
+	headers := []string{"Region", "Name", "Partition"}
+	if counted != nil {
+		headers = append(headers, counted.title)
+	}
+
+	var items []components.PickerItem
+	for _, p := range client.Partitions {
+		for _, r := range p.Regions {
+			columns := []string{r.Name, p.Name}
+			if counted != nil && p.ID != current.ID {
+				// Other partitions are separate accounts
+				columns = append(columns, "-")
+			}
+			items = append(items, components.PickerItem{
+				Value:   r.Code,
+				Columns: columns,
+				Current: r.Code == a.region,
+			})
+		}
+	}
+
+	picker := components.NewPickerModel(regionPicker, "AWS Regions", headers, items)
+	a.openPicker(picker)
+	if counted == nil {
+		picker.SetStatus(status)
+		return nil
+	}
+	a.regionCount = counted
+	a.updateRegionCounts()
+	return nil
+}
+
+// updateRegionCounts fills the open region picker's count column in from
+// what has loaded so far
+func (a *App) updateRegionCounts() {
+	if a.picker == nil || a.regionCount == nil {
+		return
+	}
+	counts, loading := a.regionCount.count(a)
+	current := client.PartitionOf(a.region)
+	for _, r := range current.Regions {
+		a.picker.UpdateItem(r.Code, []string{r.Name, current.Name, strconv.Itoa(counts[r.Code])})
+	}
+	if loading > 0 {
+		a.picker.SetStatus(fmt.Sprintf("counting %s, %d still loading…", a.regionCount.what, loading))
+	} else {
+		a.picker.SetStatus(fmt.Sprintf("counting %s", a.regionCount.what))
+	}
+}
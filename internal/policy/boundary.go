@@ -0,0 +1,125 @@
+package policy
+
+import (
+	"strings"
+)
+
+// GrantedActions returns the action patterns granted by the Allow statements
+// of the document, in document order. NotAction statements are expanded
+// against the bundled catalogue: a service the statement excludes nothing
+// from is granted as "prefix:*", any other action by action. complete is
+// false when a NotAction statement also grants actions of services outside
+// the catalogue, which are left out.
+func (d *Document) GrantedActions() (actions []string, complete bool) {
+	if d == nil {
+		return nil, true
+	}
+	complete = true
+	for _, s := range d.Statements {
+		if s.Effect != Allow {
+			continue
+		}
+		if s.NotAction == nil {
+			for _, a := range s.Action {
+				actions = appendUnique(actions, a)
+			}
+			continue
+		}
+		for _, a := range s.notActionGrants() {
+			actions = appendUnique(actions, a)
+		}
+		if !contains(s.NotAction, "*") {
+			complete = false
+		}
+	}
+	return actions, complete
+}
+
+// notActionGrants lists what a NotAction statement grants of each catalogued
+// service: "prefix:*" when no excluded pattern touches the service, otherwise
+// the catalogued actions it does not exclude
+func (s Statement) notActionGrants() []string {
+	var actions []string
+	for _, svc := range Services() {
+		touched := anyMatch(s.NotAction, svc.Prefix, func(pattern, prefix string) bool {
+			patternPrefix, _, _ := strings.Cut(pattern, ":")
+			return MatchAction(patternPrefix, prefix)
+		})
+		if !touched {
+			actions = append(actions, svc.Prefix+":*")
+			continue
+		}
+		for _, a := range svc.Actions {
+			if s.matchesAction(a.Name) {
+				actions = append(actions, a.Name)
+			}
+		}
+	}
+	return actions
+}
+
+// CutOffActions returns the actions, as granted by an identity policy, that a
+// permissions boundary does not allow in full. An action is cut off when a
+// boundary Deny matches it or no boundary Allow covers it; a wildcard action
+// such as "s3:*" is cut off when the boundary only allows part of it. The
+// analysis is action-level: resources and conditions are not considered.
+func CutOffActions(actions []string, boundary *Document) []string {
+	var statements []Statement
+	if boundary != nil {
+		statements = boundary.Statements
+	}
+
+	var cut []string
+	for _, action := range actions {
+		allowed, denied := false, false
+		for _, s := range statements {
+			switch s.Effect {
+			case Allow:
+				if s.NotAction != nil {
+					// NotAction allows everything except the listed actions
+					if !coversAction(s.NotAction, action) && !overlapsAction(s.NotAction, action) {
+						allowed = true
+					}
+				} else if coversAction(s.Action, action) {
+					allowed = true
+				}
+			case Deny:
+				if s.NotAction != nil {
+					// NotAction denies everything except the listed actions,
+					// so whatever they do not cover in full
+					if !coversAction(s.NotAction, action) {
+						denied = true
+					}
+				} else if coversAction(s.Action, action) || overlapsAction(s.Action, action) {
+					denied = true
+				}
+			}
+		}
+		if denied || !allowed {
+			cut = append(cut, action)
+		}
+	}
+	return cut
+}
+
+// coversAction reports whether any pattern covers the whole of action.
+// Action may itself contain wildcards; it is covered when the pattern matches
+// it read literally, e.g. "s3:*" covers "s3:Get*" but "s3:Get*" does not
+// cover "s3:*".
+func coversAction(patterns []string, action string) bool {
+	return anyMatch(patterns, action, MatchAction)
+}
+
+// overlapsAction reports whether a wildcard action shares any concrete
+// action with one of the patterns, e.g. "s3:*" overlaps "s3:DeleteBucket"
+func overlapsAction(patterns []string, action string) bool {
+	if !strings.ContainsAny(action, "*?") {
+		return false
+	}
+	for _, p := range patterns {
+		if MatchAction(action, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package policy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGrantedActions(t *testing.T) {
+	doc := mustParse(t, `{"Statement": [
+		{"Effect": "Allow", "Action": ["s3:GetObject", "s3:PutObject"], "Resource": "*"},
+		{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "*"},
+		{"Effect": "Deny", "Action": "iam:*", "Resource": "*"},
+		{"Effect": "Allow", "Action": "logs:*", "Resource": "*"}]}`)
+	want := []string{"s3:GetObject", "s3:PutObject", "logs:*"}
+	if got, complete := doc.GrantedActions(); !reflect.DeepEqual(got, want) || !complete {
+		t.Errorf("GrantedActions() = %q, %v, want %q, true", got, complete, want)
+	}
+}
+
+func TestGrantedActionsNotAction(t *testing.T) {
+	doc := mustParse(t, `{"Statement": {"Effect": "Allow", "NotAction": ["iam:*", "s3:Delete*"], "Resource": "*"}}`)
+	got, complete := doc.GrantedActions()
+	if complete {
+		t.Errorf("GrantedActions() complete = true, want false")
+	}
+	for _, want := range []string{"ec2:*", "logs:*", "s3:GetObject", "s3:PutObject"} {
+		if !contains(got, want) {
+			t.Errorf("GrantedActions() = %q, missing %q", got, want)
+		}
+	}
+	for _, unwanted := range []string{"s3:*", "s3:DeleteBucket", "iam:*", "iam:PassRole"} {
+		if contains(got, unwanted) {
+			t.Errorf("GrantedActions() = %q, should not contain %q", got, unwanted)
+		}
+	}
+
+	none := mustParse(t, `{"Statement": {"Effect": "Allow", "NotAction": "*", "Resource": "*"}}`)
+	if got, complete := none.GrantedActions(); len(got) != 0 || !complete {
+		t.Errorf("GrantedActions(NotAction *) = %q, %v, want none, true", got, complete)
+	}
+}
+
+func TestCutOffActions(t *testing.T) {
+	tests := []struct {
+		name     string
+		boundary string
+		actions  []string
+		want     []string
+	}{
+		{
+			name:     "allow list",
+			boundary: `{"Statement": {"Effect": "Allow", "Action": ["s3:*", "logs:PutLogEvents"], "Resource": "*"}}`,
+			actions:  []string{"s3:GetObject", "s3:*", "logs:*", "ec2:RunInstances"},
+			want:     []string{"logs:*", "ec2:RunInstances"},
+		},
+		{
+			name: "deny cuts off part of a wildcard",
+			boundary: `{"Statement": [
+				{"Effect": "Allow", "Action": "*", "Resource": "*"},
+				{"Effect": "Deny", "Action": "s3:DeleteBucket", "Resource": "*"}]}`,
+			actions: []string{"s3:*", "s3:GetObject", "s3:DeleteBucket"},
+			want:    []string{"s3:*", "s3:DeleteBucket"},
+		},
+		{
+			name:     "allow with NotAction",
+			boundary: `{"Statement": {"Effect": "Allow", "NotAction": "iam:*", "Resource": "*"}}`,
+			actions:  []string{"s3:GetObject", "iam:PassRole", "*"},
+			want:     []string{"iam:PassRole", "*"},
+		},
+		{
+			name: "deny with NotAction",
+			boundary: `{"Statement": [
+				{"Effect": "Allow", "Action": "*", "Resource": "*"},
+				{"Effect": "Deny", "NotAction": ["s3:*", "logs:*"], "Resource": "*"}]}`,
+			actions: []string{"s3:GetObject", "logs:*", "ec2:RunInstances", "*"},
+			want:    []string{"ec2:RunInstances", "*"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CutOffActions(tt.actions, mustParse(t, tt.boundary))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CutOffActions() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package policy
+
+import (
+	_ "embed"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// AccessLevel classifies an action the way the AWS service authorization
+// reference does
+type AccessLevel string
+
+const (
+	LevelList        AccessLevel = "List"
+	LevelRead        AccessLevel = "Read"
+	LevelWrite       AccessLevel = "Write"
+	LevelPermissions AccessLevel = "Permissions management"
+	LevelTagging     AccessLevel = "Tagging"
+)
+
+// Service is a service in the action catalogue
+type Service struct {
+	// Prefix is the action namespace, e.g. s3
+	Prefix  string
+	Name    string
+	Actions []CatalogueAction
+}
+
+// CatalogueAction is an action of a catalogued service
+type CatalogueAction struct {
+	// Name is the full action name, e.g. s3:GetObject
+	Name  string
+	Level AccessLevel
+}
+
+// actionsJSON is a bundled subset of the service authorization reference:
+// the commonly used services and their actions
+//
+//go:embed actions.json
+var actionsJSON []byte
+
+type catalogue struct {
+	services []Service
+	byPrefix map[string]int
+	levels   map[string]AccessLevel
+}
+
+var loadCatalogue = sync.OnceValue(func() *catalogue {
+	var raw map[string]struct {
+		Name    string                 `json:"name"`
+		Actions map[string]AccessLevel `json:"actions"`
+	}
+	if err := json.Unmarshal(actionsJSON, &raw); err != nil {
+		panic("policy: invalid bundled action catalogue: " + err.Error())
+	}
+
+	c := &catalogue{byPrefix: make(map[string]int), levels: make(map[string]AccessLevel)}
+	for prefix, svc := range raw {
+		service := Service{Prefix: prefix, Name: svc.Name}
+		for name, level := range svc.Actions {
+			action := prefix + ":" + name
+			service.Actions = append(service.Actions, CatalogueAction{Name: action, Level: level})
+			c.levels[strings.ToLower(action)] = level
+		}
+		sort.Slice(service.Actions, func(i, j int) bool { return service.Actions[i].Name < service.Actions[j].Name })
+		c.services = append(c.services, service)
+	}
+	sort.Slice(c.services, func(i, j int) bool { return c.services[i].Prefix < c.services[j].Prefix })
+	for i, s := range c.services {
+		c.byPrefix[s.Prefix] = i
+	}
+	return c
+})
+
+// Services returns the catalogued services, sorted by prefix
+func Services() []Service {
+	return loadCatalogue().services
+}
+
+// LookupService finds a catalogued service by action prefix
+func LookupService(prefix string) (Service, bool) {
+	c := loadCatalogue()
+	i, ok := c.byPrefix[strings.ToLower(prefix)]
+	if !ok {
+		return Service{}, false
+	}
+	return c.services[i], true
+}
+
+// Level returns the access level of an action, false when the action is
+// not in the catalogue
+func Level(action string) (AccessLevel, bool) {
+	level, ok := loadCatalogue().levels[strings.ToLower(action)]
+	return level, ok
+}
+
+// ExpandAction lists the catalogued actions an action pattern such as
+// s3:Get* or * matches, nil when it matches none
+func ExpandAction(pattern string) []string {
+	var actions []string
+	prefix, _, _ := strings.Cut(pattern, ":")
+	services := Services()
+	if !strings.ContainsAny(prefix, "*?") {
+		s, ok := LookupService(prefix)
+		if !ok {
+			return nil
+		}
+		services = []Service{s}
+	}
+	for _, s := range services {
+		for _, a := range s.Actions {
+			if MatchAction(pattern, a.Name) {
+				actions = append(actions, a.Name)
+			}
+		}
+	}
+	return actions
+}
@@ -0,0 +1,222 @@
+package policy
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Context holds the request's condition keys and their values, e.g.
+// "aws:SourceIp" or "s3:prefix". Keys are case-insensitive.
+type Context map[string][]string
+
+// Set records a key's values, replacing any under the same key in another
+// case
+func (c Context) Set(key string, values ...string) {
+	for k := range c {
+		if strings.EqualFold(k, key) {
+			delete(c, k)
+		}
+	}
+	c[key] = values
+}
+
+// Values returns a key's values, nil when the request does not carry it
+func (c Context) Values(key string) []string {
+	if v, ok := c[key]; ok {
+		return v
+	}
+	for k, v := range c {
+		if strings.EqualFold(k, key) {
+			return v
+		}
+	}
+	return nil
+}
+
+func (c Context) has(key string) bool {
+	if _, ok := c[key]; ok {
+		return true
+	}
+	for k := range c {
+		if strings.EqualFold(k, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// conditionsMet reports whether every condition of the statement holds for
+// the request context. Operators are ANDed, as are the keys under one
+// operator; the values of one key are ORed.
+func (s Statement) conditionsMet(ctx Context) bool {
+	for op, keys := range s.Condition {
+		for key, values := range keys {
+			if !evalCondition(op, key, values, ctx) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// evalCondition evaluates one condition key. Unknown operators never hold.
+func evalCondition(op, key string, values []string, ctx Context) bool {
+	set := ""
+	if i := strings.Index(op, ":"); i >= 0 {
+		set, op = op[:i], op[i+1:]
+	}
+	ifExists := strings.HasSuffix(op, "IfExists")
+	op = strings.TrimSuffix(op, "IfExists")
+
+	if op == "Null" {
+		// "Null": "true" requires the key to be absent
+		want := len(values) > 0 && strings.EqualFold(values[0], "true")
+		return want == !ctx.has(key)
+	}
+
+	compare, negated, ok := operator(op)
+	if !ok {
+		return false
+	}
+
+	actual := ctx.Values(key)
+	if !ctx.has(key) {
+		// A missing key satisfies ...IfExists, ForAllValues and negated
+		// operators, and fails ForAnyValue and positive operators
+		switch {
+		case ifExists || set == "ForAllValues":
+			return true
+		case set == "ForAnyValue":
+			return false
+		}
+		return negated
+	}
+
+	expected := make([]string, len(values))
+	for i, v := range values {
+		expected[i] = substitute(v, ctx)
+	}
+	holds := func(value string) bool {
+		for _, e := range expected {
+			if compare(value, e) {
+				return !negated
+			}
+		}
+		return negated
+	}
+
+	switch set {
+	case "ForAllValues":
+		for _, v := range actual {
+			if !holds(v) {
+				return false
+			}
+		}
+		return true
+	case "ForAnyValue":
+		for _, v := range actual {
+			if holds(v) {
+				return true
+			}
+		}
+		return false
+	}
+	// Single-valued keys carry one value; should several be set, any may
+	// satisfy the condition
+	for _, v := range actual {
+		if holds(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// operator returns the comparison behind a condition operator and whether
+// the operator negates it
+func operator(op string) (compare func(actual, expected string) bool, negated, ok bool) {
+	switch op {
+	case "StringEquals", "StringNotEquals":
+		compare = func(a, e string) bool { return a == e }
+	case "StringEqualsIgnoreCase", "StringNotEqualsIgnoreCase":
+		compare = strings.EqualFold
+	case "StringLike", "StringNotLike":
+		compare = func(a, e string) bool { return matchWildcard(e, a) }
+	case "ArnEquals", "ArnLike", "ArnNotEquals", "ArnNotLike":
+		compare = func(a, e string) bool { return MatchResource(e, a) }
+	case "Bool":
+		compare = strings.EqualFold
+	case "BinaryEquals":
+		compare = func(a, e string) bool { return a == e }
+	case "NumericEquals", "NumericNotEquals":
+		compare = numeric(func(a, e float64) bool { return a == e })
+	case "NumericLessThan":
+		compare = numeric(func(a, e float64) bool { return a < e })
+	case "NumericLessThanEquals":
+		compare = numeric(func(a, e float64) bool { return a <= e })
+	case "NumericGreaterThan":
+		compare = numeric(func(a, e float64) bool { return a > e })
+	case "NumericGreaterThanEquals":
+		compare = numeric(func(a, e float64) bool { return a >= e })
+	case "DateEquals", "DateNotEquals":
+		compare = date(func(a, e time.Time) bool { return a.Equal(e) })
+	case "DateLessThan":
+		compare = date(func(a, e time.Time) bool { return a.Before(e) })
+	case "DateLessThanEquals":
+		compare = date(func(a, e time.Time) bool { return !a.After(e) })
+	case "DateGreaterThan":
+		compare = date(func(a, e time.Time) bool { return a.After(e) })
+	case "DateGreaterThanEquals":
+		compare = date(func(a, e time.Time) bool { return !a.Before(e) })
+	case "IpAddress", "NotIpAddress":
+		compare = ipInRange
+	default:
+		return nil, false, false
+	}
+	negated = strings.Contains(op, "Not")
+	return compare, negated, true
+}
+
+func numeric(cmp func(a, e float64) bool) func(string, string) bool {
+	return func(a, e string) bool {
+		x, err1 := strconv.ParseFloat(a, 64)
+		y, err2 := strconv.ParseFloat(e, 64)
+		return err1 == nil && err2 == nil && cmp(x, y)
+	}
+}
+
+func date(cmp func(a, e time.Time) bool) func(string, string) bool {
+	return func(a, e string) bool {
+		x, ok1 := parseDate(a)
+		y, ok2 := parseDate(e)
+		return ok1 && ok2 && cmp(x, y)
+	}
+}
+
+// parseDate reads an ISO 8601 date or time, or epoch seconds
+func parseDate(s string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05Z0700", "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	if secs, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(secs, 0), true
+	}
+	return time.Time{}, false
+}
+
+// ipInRange reports whether an IP address falls in a CIDR range or equals a
+// single address
+func ipInRange(a, e string) bool {
+	ip := net.ParseIP(a)
+	if ip == nil {
+		return false
+	}
+	if _, network, err := net.ParseCIDR(e); err == nil {
+		return network.Contains(ip)
+	}
+	other := net.ParseIP(e)
+	return other != nil && other.Equal(ip)
+}
@@ -0,0 +1,128 @@
+package policy
+
+import (
+	"testing"
+)
+
+func TestEvalCondition(t *testing.T) {
+	ctx := Context{
+		"aws:username":               {"alice"},
+		"aws:SourceIp":               {"10.0.1.20"},
+		"aws:SourceArn":              {"arn:aws:sns:eu-west-1:123456789012:alerts"},
+		"aws:MultiFactorAuthPresent": {"true"},
+		"aws:CurrentTime":            {"2026-06-01T12:00:00Z"},
+		"aws:EpochTime":              {"1780315200"},
+		"s3:max-keys":                {"10"},
+		"aws:TagKeys":                {"team", "env"},
+		"kms:CiphertextBlob":         {"QUJD"},
+		"aws:PrincipalTag/team":      {"payments"},
+	}
+
+	tests := []struct {
+		op     string
+		key    string
+		values []string
+		want   bool
+	}{
+		{"StringEquals", "aws:username", []string{"alice"}, true},
+		{"StringEquals", "aws:username", []string{"bob", "alice"}, true},
+		{"StringEquals", "aws:username", []string{"Alice"}, false},
+		{"StringEquals", "aws:username", []string{"${aws:username}"}, true},
+		{"StringEquals", "aws:username", []string{"${aws:PrincipalTag/team}"}, false},
+		{"StringNotEquals", "aws:username", []string{"bob"}, true},
+		{"StringNotEquals", "aws:username", []string{"alice"}, false},
+		{"StringEqualsIgnoreCase", "aws:username", []string{"ALICE"}, true},
+		{"StringNotEqualsIgnoreCase", "aws:username", []string{"ALICE"}, false},
+		{"StringLike", "aws:username", []string{"al*"}, true},
+		{"StringLike", "aws:username", []string{"b?b"}, false},
+		{"StringNotLike", "aws:username", []string{"b*"}, true},
+		{"StringNotLike", "aws:username", []string{"a*"}, false},
+		{"ArnEquals", "aws:SourceArn", []string{"arn:aws:sns:eu-west-1:123456789012:alerts"}, true},
+		{"ArnLike", "aws:SourceArn", []string{"arn:aws:sns:*:123456789012:*"}, true},
+		{"ArnLike", "aws:SourceArn", []string{"arn:aws:sqs:*"}, false},
+		{"ArnNotEquals", "aws:SourceArn", []string{"arn:aws:sns:eu-west-1:123456789012:other"}, true},
+		{"ArnNotLike", "aws:SourceArn", []string{"arn:aws:sns:*"}, false},
+		{"Bool", "aws:MultiFactorAuthPresent", []string{"true"}, true},
+		{"Bool", "aws:MultiFactorAuthPresent", []string{"false"}, false},
+		{"BinaryEquals", "kms:CiphertextBlob", []string{"QUJD"}, true},
+		{"BinaryEquals", "kms:CiphertextBlob", []string{"QUJE"}, false},
+		{"NumericEquals", "s3:max-keys", []string{"10"}, true},
+		{"NumericNotEquals", "s3:max-keys", []string{"10"}, false},
+		{"NumericLessThan", "s3:max-keys", []string{"11"}, true},
+		{"NumericLessThan", "s3:max-keys", []string{"10"}, false},
+		{"NumericLessThanEquals", "s3:max-keys", []string{"10"}, true},
+		{"NumericGreaterThan", "s3:max-keys", []string{"9.5"}, true},
+		{"NumericGreaterThan", "s3:max-keys", []string{"10"}, false},
+		{"NumericGreaterThanEquals", "s3:max-keys", []string{"10"}, true},
+		{"NumericEquals", "s3:max-keys", []string{"ten"}, false},
+		{"DateEquals", "aws:CurrentTime", []string{"2026-06-01T12:00:00Z"}, true},
+		{"DateNotEquals", "aws:CurrentTime", []string{"2026-06-01T12:00:00Z"}, false},
+		{"DateLessThan", "aws:CurrentTime", []string{"2027-01-01"}, true},
+		{"DateLessThan", "aws:CurrentTime", []string{"2026-01-01"}, false},
+		{"DateLessThanEquals", "aws:CurrentTime", []string{"2026-06-01T12:00:00Z"}, true},
+		{"DateGreaterThan", "aws:CurrentTime", []string{"2026-01-01T00:00:00Z"}, true},
+		{"DateGreaterThanEquals", "aws:CurrentTime", []string{"2026-06-01T12:00:01Z"}, false},
+		{"DateEquals", "aws:EpochTime", []string{"2026-06-01T12:00:00Z"}, true},
+		{"IpAddress", "aws:SourceIp", []string{"10.0.0.0/16"}, true},
+		{"IpAddress", "aws:SourceIp", []string{"10.0.1.20"}, true},
+		{"IpAddress", "aws:SourceIp", []string{"192.168.0.0/16"}, false},
+		{"NotIpAddress", "aws:SourceIp", []string{"192.168.0.0/16"}, true},
+		{"NotIpAddress", "aws:SourceIp", []string{"10.0.0.0/8"}, false},
+		{"UnknownOperator", "aws:username", []string{"alice"}, false},
+
+		// Missing keys
+		{"StringEquals", "aws:userid", []string{"alice"}, false},
+		{"StringNotEquals", "aws:userid", []string{"alice"}, true},
+		{"StringEqualsIfExists", "aws:userid", []string{"alice"}, true},
+		{"StringEqualsIfExists", "aws:username", []string{"alice"}, true},
+		{"StringEqualsIfExists", "aws:username", []string{"bob"}, false},
+		{"NumericLessThanIfExists", "s3:max-keys", []string{"5"}, false},
+		{"IpAddressIfExists", "aws:VpcSourceIp", []string{"10.0.0.0/8"}, true},
+
+		// Null
+		{"Null", "aws:userid", []string{"true"}, true},
+		{"Null", "aws:username", []string{"true"}, false},
+		{"Null", "aws:username", []string{"false"}, true},
+		{"Null", "aws:userid", []string{"false"}, false},
+
+		// Multivalued keys
+		{"ForAllValues:StringEquals", "aws:TagKeys", []string{"team", "env", "owner"}, true},
+		{"ForAllValues:StringEquals", "aws:TagKeys", []string{"team"}, false},
+		{"ForAllValues:StringEquals", "aws:RequestTag/none", []string{"team"}, true},
+		{"ForAnyValue:StringEquals", "aws:TagKeys", []string{"env"}, true},
+		{"ForAnyValue:StringEquals", "aws:TagKeys", []string{"owner"}, false},
+		{"ForAnyValue:StringEquals", "aws:RequestTag/none", []string{"team"}, false},
+		{"ForAnyValue:StringLike", "aws:TagKeys", []string{"t*"}, true},
+		{"ForAllValues:StringNotEquals", "aws:TagKeys", []string{"owner"}, true},
+	}
+
+	for _, tt := range tests {
+		if got := evalCondition(tt.op, tt.key, tt.values, ctx); got != tt.want {
+			t.Errorf("%s %s %q = %v, want %v", tt.op, tt.key, tt.values, got, tt.want)
+		}
+	}
+}
+
+func TestConditionsMet(t *testing.T) {
+	s := Statement{Condition: Condition{
+		"StringEquals": {"aws:RequestedRegion": {"eu-west-1", "eu-west-2"}, "aws:PrincipalTag/team": {"payments"}},
+		"Bool":         {"aws:SecureTransport": {"true"}},
+	}}
+	tests := []struct {
+		name string
+		ctx  Context
+		want bool
+	}{
+		{"every key holds", Context{"aws:RequestedRegion": {"eu-west-2"}, "aws:PrincipalTag/team": {"payments"}, "aws:SecureTransport": {"true"}}, true},
+		{"one key under an operator fails", Context{"aws:RequestedRegion": {"us-east-1"}, "aws:PrincipalTag/team": {"payments"}, "aws:SecureTransport": {"true"}}, false},
+		{"one operator fails", Context{"aws:RequestedRegion": {"eu-west-1"}, "aws:PrincipalTag/team": {"payments"}, "aws:SecureTransport": {"false"}}, false},
+		{"keys are case-insensitive", Context{"AWS:REQUESTEDREGION": {"eu-west-1"}, "aws:principaltag/team": {"payments"}, "aws:securetransport": {"true"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.conditionsMet(tt.ctx); got != tt.want {
+				t.Errorf("conditionsMet() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
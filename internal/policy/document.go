@@ -0,0 +1,224 @@
+// Package policy parses IAM policy documents and evaluates requests against
+// them offline, following AWS's explicit deny, allow, implicit deny logic.
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Effect is a statement's effect, Allow or Deny
+type Effect string
+
+const (
+	Allow Effect = "Allow"
+	Deny  Effect = "Deny"
+)
+
+// Document is a parsed policy document
+type Document struct {
+	Version    string
+	ID         string
+	Statements []Statement
+}
+
+// Statement is one statement of a policy document. Action and NotAction,
+// Resource and NotResource, Principal and NotPrincipal are mutually exclusive;
+// the unused one is nil.
+type Statement struct {
+	Sid          string
+	Effect       Effect
+	Action       []string
+	NotAction    []string
+	Resource     []string
+	NotResource  []string
+	Principal    Principal
+	NotPrincipal Principal
+	Condition    Condition
+	// Raw is the statement as written in the document
+	Raw json.RawMessage
+}
+
+// Principal maps a principal type (AWS, Service, Federated, CanonicalUser) to
+// its values. "Principal": "*" is read as {"AWS": ["*"]}.
+type Principal map[string][]string
+
+// Condition maps a condition operator, e.g. "StringEquals" or
+// "ForAnyValue:StringLike", to the context keys it tests and their values
+type Condition map[string]map[string][]string
+
+// HasConditions reports whether the statement only applies under conditions
+func (s Statement) HasConditions() bool {
+	return len(s.Condition) > 0
+}
+
+// Conditions returns the statement's conditions as "key operator values"
+// lines, sorted by operator then key
+func (s Statement) Conditions() []string {
+	operators := make([]string, 0, len(s.Condition))
+	for op := range s.Condition {
+		operators = append(operators, op)
+	}
+	sort.Strings(operators)
+
+	var lines []string
+	for _, op := range operators {
+		keys := make([]string, 0, len(s.Condition[op]))
+		for k := range s.Condition[op] {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			lines = append(lines, fmt.Sprintf("%s %s %s", k, op, strings.Join(s.Condition[op][k], ", ")))
+		}
+	}
+	return lines
+}
+
+// rawStatement mirrors a statement as written, before normalisation
+type rawStatement struct {
+	Sid          string                                `json:"Sid"`
+	Effect       string                                `json:"Effect"`
+	Action       json.RawMessage                       `json:"Action"`
+	NotAction    json.RawMessage                       `json:"NotAction"`
+	Resource     json.RawMessage                       `json:"Resource"`
+	NotResource  json.RawMessage                       `json:"NotResource"`
+	Principal    json.RawMessage                       `json:"Principal"`
+	NotPrincipal json.RawMessage                       `json:"NotPrincipal"`
+	Condition    map[string]map[string]json.RawMessage `json:"Condition"`
+}
+
+// Parse parses a policy document as returned by the IAM API, once URL
+// decoded. Statement, Action, Resource and condition values may each be a
+// single value or an array.
+func Parse(document string) (*Document, error) {
+	var raw struct {
+		Version   string          `json:"Version"`
+		ID        string          `json:"Id"`
+		Statement json.RawMessage `json:"Statement"`
+	}
+	if err := json.Unmarshal([]byte(document), &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse policy document: %w", err)
+	}
+
+	var statements []json.RawMessage
+	if len(raw.Statement) > 0 {
+		if err := json.Unmarshal(raw.Statement, &statements); err != nil {
+			statements = []json.RawMessage{raw.Statement}
+		}
+	}
+
+	doc := &Document{Version: raw.Version, ID: raw.ID}
+	for i, rs := range statements {
+		s, err := parseStatement(rs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse statement %d: %w", i+1, err)
+		}
+		doc.Statements = append(doc.Statements, s)
+	}
+	return doc, nil
+}
+
+func parseStatement(raw json.RawMessage) (Statement, error) {
+	var rs rawStatement
+	if err := json.Unmarshal(raw, &rs); err != nil {
+		return Statement{}, err
+	}
+
+	s := Statement{Sid: rs.Sid, Effect: Effect(rs.Effect), Raw: raw}
+	if s.Effect != Allow && s.Effect != Deny {
+		return s, fmt.Errorf("invalid effect %q", rs.Effect)
+	}
+
+	var err error
+	for _, field := range []struct {
+		raw json.RawMessage
+		dst *[]string
+	}{
+		{rs.Action, &s.Action},
+		{rs.NotAction, &s.NotAction},
+		{rs.Resource, &s.Resource},
+		{rs.NotResource, &s.NotResource},
+	} {
+		if *field.dst, err = values(field.raw); err != nil {
+			return s, err
+		}
+	}
+	if s.Principal, err = parsePrincipal(rs.Principal); err != nil {
+		return s, err
+	}
+	if s.NotPrincipal, err = parsePrincipal(rs.NotPrincipal); err != nil {
+		return s, err
+	}
+
+	if len(rs.Condition) > 0 {
+		s.Condition = make(Condition)
+		for op, keys := range rs.Condition {
+			s.Condition[op] = make(map[string][]string)
+			for key, raw := range keys {
+				if s.Condition[op][key], err = values(raw); err != nil {
+					return s, fmt.Errorf("condition %s %s: %w", op, key, err)
+				}
+			}
+		}
+	}
+	return s, nil
+}
+
+func parsePrincipal(raw json.RawMessage) (Principal, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var wildcard string
+	if err := json.Unmarshal(raw, &wildcard); err == nil {
+		return Principal{"AWS": {wildcard}}, nil
+	}
+
+	var byType map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &byType); err != nil {
+		return nil, fmt.Errorf("invalid principal: %w", err)
+	}
+	principal := make(Principal)
+	for typ, v := range byType {
+		vals, err := values(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s principal: %w", typ, err)
+		}
+		principal[typ] = vals
+	}
+	return principal, nil
+}
+
+// values reads a JSON scalar or array of scalars as strings. Booleans and
+// numbers, as found in conditions, keep their JSON spelling.
+func values(raw json.RawMessage) ([]string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var list []json.RawMessage
+	if err := json.Unmarshal(raw, &list); err != nil {
+		list = []json.RawMessage{raw}
+	}
+
+	out := make([]string, 0, len(list))
+	for _, item := range list {
+		var v any
+		if err := json.Unmarshal(item, &v); err != nil {
+			return nil, err
+		}
+		switch v := v.(type) {
+		case string:
+			out = append(out, v)
+		case bool:
+			out = append(out, strconv.FormatBool(v))
+		case float64:
+			out = append(out, strconv.FormatFloat(v, 'f', -1, 64))
+		default:
+			return nil, fmt.Errorf("unsupported value %s", item)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,130 @@
+package policy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		want     []Statement
+		wantErr  bool
+	}{
+		{
+			name: "single statement and values",
+			document: `{"Version": "2012-10-17", "Statement": {
+				"Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::bucket/*"}}`,
+			want: []Statement{{
+				Effect:   Allow,
+				Action:   []string{"s3:GetObject"},
+				Resource: []string{"arn:aws:s3:::bucket/*"},
+			}},
+		},
+		{
+			name: "statement and value arrays",
+			document: `{"Statement": [
+				{"Sid": "Read", "Effect": "Allow", "Action": ["s3:GetObject", "s3:ListBucket"], "Resource": ["a", "b"]},
+				{"Effect": "Deny", "NotAction": ["iam:*"], "NotResource": "c"}]}`,
+			want: []Statement{
+				{Sid: "Read", Effect: Allow, Action: []string{"s3:GetObject", "s3:ListBucket"}, Resource: []string{"a", "b"}},
+				{Effect: Deny, NotAction: []string{"iam:*"}, NotResource: []string{"c"}},
+			},
+		},
+		{
+			name:     "wildcard principal",
+			document: `{"Statement": {"Effect": "Allow", "Principal": "*", "Action": "sts:AssumeRole"}}`,
+			want: []Statement{{
+				Effect:    Allow,
+				Action:    []string{"sts:AssumeRole"},
+				Principal: Principal{"AWS": {"*"}},
+			}},
+		},
+		{
+			name: "principal types",
+			document: `{"Statement": {"Effect": "Allow", "Action": "sts:AssumeRole", "Principal": {
+				"Service": ["ec2.amazonaws.com", "lambda.amazonaws.com"], "AWS": "arn:aws:iam::123456789012:root"}}}`,
+			want: []Statement{{
+				Effect: Allow,
+				Action: []string{"sts:AssumeRole"},
+				Principal: Principal{
+					"Service": {"ec2.amazonaws.com", "lambda.amazonaws.com"},
+					"AWS":     {"arn:aws:iam::123456789012:root"},
+				},
+			}},
+		},
+		{
+			name: "condition values keep their JSON spelling",
+			document: `{"Statement": {"Effect": "Allow", "Action": "*", "Condition": {
+				"Bool": {"aws:MultiFactorAuthPresent": true},
+				"NumericLessThan": {"s3:max-keys": 10},
+				"StringEquals": {"aws:RequestedRegion": ["eu-west-1", "eu-west-2"]}}}}`,
+			want: []Statement{{
+				Effect: Allow,
+				Action: []string{"*"},
+				Condition: Condition{
+					"Bool":            {"aws:MultiFactorAuthPresent": {"true"}},
+					"NumericLessThan": {"s3:max-keys": {"10"}},
+					"StringEquals":    {"aws:RequestedRegion": {"eu-west-1", "eu-west-2"}},
+				},
+			}},
+		},
+		{
+			name:     "no statements",
+			document: `{"Version": "2012-10-17"}`,
+		},
+		{
+			name:     "invalid JSON",
+			document: `{"Statement": [`,
+			wantErr:  true,
+		},
+		{
+			name:     "invalid effect",
+			document: `{"Statement": {"Effect": "Maybe", "Action": "*"}}`,
+			wantErr:  true,
+		},
+		{
+			name:     "object as action",
+			document: `{"Statement": {"Effect": "Allow", "Action": {"s3": "GetObject"}}}`,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := Parse(tt.document)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Parse() succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			for i := range doc.Statements {
+				doc.Statements[i].Raw = nil
+			}
+			if !reflect.DeepEqual(doc.Statements, tt.want) {
+				t.Errorf("Parse() statements = %#v, want %#v", doc.Statements, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatementConditions(t *testing.T) {
+	doc, err := Parse(`{"Statement": {"Effect": "Allow", "Action": "sts:AssumeRoleWithWebIdentity", "Condition": {
+		"StringLike": {"token.actions.githubusercontent.com:sub": ["repo:org/app:*", "repo:org/lib:*"]},
+		"StringEquals": {"token.actions.githubusercontent.com:aud": "sts.amazonaws.com"}}}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"token.actions.githubusercontent.com:aud StringEquals sts.amazonaws.com",
+		"token.actions.githubusercontent.com:sub StringLike repo:org/app:*, repo:org/lib:*",
+	}
+	if got := doc.Statements[0].Conditions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Conditions() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,241 @@
+package policy
+
+import (
+	"sort"
+	"strings"
+)
+
+// Access is what a set of policies does to one action on one resource
+type Access int
+
+const (
+	// AccessAllowed means a statement allows the action unconditionally and
+	// no Deny statement applies
+	AccessAllowed Access = iota
+	// AccessConditional means the outcome depends on request context: the
+	// only statements allowing the action, or a Deny overriding them, have
+	// conditions
+	AccessConditional
+	// AccessDenied means a Deny statement without conditions applies
+	AccessDenied
+)
+
+func (a Access) String() string {
+	switch a {
+	case AccessConditional:
+		return "conditional"
+	case AccessDenied:
+		return "denied"
+	}
+	return "allowed"
+}
+
+// Permission is one row of an effective permissions matrix: an action, the
+// resource pattern it applies to and the policies behind it
+type Permission struct {
+	// Service is the action's prefix, e.g. s3
+	Service string
+	// Action is a catalogued action, or the pattern as written when its
+	// service part is a wildcard or a service the catalogue does not know
+	Action string
+	// Resource is the statement's resource pattern; NotResource patterns
+	// are prefixed with "NOT "
+	Resource string
+	Access   Access
+	// Effect is the effect of the statements behind the row; Deny rows list
+	// actions denied that no statement allows
+	Effect Effect
+	// Policies names the policies with the statements behind the row
+	Policies []string
+	// DeniedBy names the policies whose Deny statements override an Allow
+	DeniedBy []string
+}
+
+// permissionKey identifies a row of the matrix
+type permissionKey struct {
+	action, resource string
+	effect           Effect
+}
+
+// grant is one action and resource pattern of a statement
+type grant struct {
+	permissionKey
+	policy      string
+	conditional bool
+}
+
+// EffectivePermissions merges policies into a service, action and resource
+// matrix. Action wildcards, and NotAction, are expanded against the bundled
+// catalogue, keeping patterns that reach beyond it as rows of their own;
+// Deny statements mark the Allow rows they cover. Rows are sorted by
+// service, action and resource.
+func EffectivePermissions(policies []Policy) []Permission {
+	var allows, denies []grant
+	for _, p := range policies {
+		if p.Document == nil {
+			continue
+		}
+		for _, s := range p.Document.Statements {
+			for _, action := range s.expandedActions() {
+				for _, resource := range s.resourcePatterns() {
+					g := grant{
+						permissionKey: permissionKey{action: action, resource: resource, effect: s.Effect},
+						policy:        p.Name,
+						conditional:   s.HasConditions(),
+					}
+					if s.Effect == Deny {
+						denies = append(denies, g)
+					} else {
+						allows = append(allows, g)
+					}
+				}
+			}
+		}
+	}
+
+	rows := make(map[permissionKey]*Permission)
+	var order []permissionKey
+	row := func(g grant, access Access) *Permission {
+		p, ok := rows[g.permissionKey]
+		if !ok {
+			service, _, _ := strings.Cut(g.action, ":")
+			p = &Permission{
+				Service:  strings.ToLower(service),
+				Action:   g.action,
+				Resource: g.resource,
+				Access:   access,
+				Effect:   g.effect,
+			}
+			rows[g.permissionKey] = p
+			order = append(order, g.permissionKey)
+		}
+		p.Policies = appendUnique(p.Policies, g.policy)
+		return p
+	}
+
+	denied := make(map[permissionKey]bool)
+	for _, a := range allows {
+		access := AccessAllowed
+		if a.conditional {
+			access = AccessConditional
+		}
+		var deniedBy []string
+		for _, d := range denies {
+			if !MatchAction(d.action, a.action) || !coversResource(d.resource, a.resource) {
+				continue
+			}
+			denied[d.permissionKey] = true
+			deniedBy = appendUnique(deniedBy, d.policy)
+			if !d.conditional {
+				access = AccessDenied
+			} else if access == AccessAllowed {
+				access = AccessConditional
+			}
+		}
+
+		_, seen := rows[a.permissionKey]
+		p := row(a, access)
+		// Of several statements allowing the same action and resource, the
+		// most permissive one decides
+		if seen && access < p.Access {
+			p.Access = access
+		}
+		for _, name := range deniedBy {
+			p.DeniedBy = appendUnique(p.DeniedBy, name)
+		}
+	}
+	for _, d := range denies {
+		if denied[d.permissionKey] {
+			continue
+		}
+		access := AccessDenied
+		if d.conditional {
+			access = AccessConditional
+		}
+		row(d, access)
+	}
+
+	permissions := make([]Permission, 0, len(order))
+	for _, key := range order {
+		permissions = append(permissions, *rows[key])
+	}
+	sort.SliceStable(permissions, func(i, j int) bool {
+		a, b := permissions[i], permissions[j]
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		}
+		if a.Action != b.Action {
+			return a.Action < b.Action
+		}
+		if a.Resource != b.Resource {
+			return a.Resource < b.Resource
+		}
+		return a.Effect < b.Effect
+	})
+	return permissions
+}
+
+// expandedActions lists the actions the statement applies to. Action
+// wildcards are expanded against the catalogue; a pattern whose service part
+// is a wildcard, or names a service the catalogue does not know, is also kept
+// as written, since it grants more than the catalogue lists. NotAction covers
+// every catalogued action it does not exclude.
+func (s Statement) expandedActions() []string {
+	var actions []string
+	if s.NotAction != nil {
+		for _, svc := range Services() {
+			for _, a := range svc.Actions {
+				if s.matchesAction(a.Name) {
+					actions = append(actions, a.Name)
+				}
+			}
+		}
+		return actions
+	}
+	for _, pattern := range s.Action {
+		prefix, _, _ := strings.Cut(pattern, ":")
+		if _, ok := LookupService(prefix); !ok {
+			actions = appendUnique(actions, pattern)
+		}
+		for _, action := range ExpandAction(pattern) {
+			actions = appendUnique(actions, action)
+		}
+	}
+	return actions
+}
+
+// resourcePatterns lists the statement's resources as matrix columns
+func (s Statement) resourcePatterns() []string {
+	switch {
+	case s.NotResource != nil:
+		patterns := make([]string, len(s.NotResource))
+		for i, r := range s.NotResource {
+			patterns[i] = "NOT " + r
+		}
+		return patterns
+	case s.Resource != nil:
+		return s.Resource
+	}
+	return []string{"*"}
+}
+
+// coversResource reports whether a Deny resource pattern covers every
+// resource an Allow pattern does
+func coversResource(deny, allow string) bool {
+	if strings.HasPrefix(deny, "NOT ") {
+		return false
+	}
+	if strings.HasPrefix(allow, "NOT ") {
+		return deny == "*"
+	}
+	return MatchResource(deny, allow)
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, v := range list {
+		if v == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
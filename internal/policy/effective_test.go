@@ -0,0 +1,110 @@
+package policy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEffectivePermissions(t *testing.T) {
+	tests := []struct {
+		name     string
+		policies map[string]string
+		// want lists rows that must be present; other rows are ignored
+		want []Permission
+	}{
+		{
+			name: "unconditional deny overrides allow",
+			policies: map[string]string{
+				"app":        `{"Statement": {"Effect": "Allow", "Action": "s3:GetObject", "Resource": "*"}}`,
+				"guardrails": `{"Statement": {"Effect": "Deny", "Action": "s3:Get*", "Resource": "*"}}`,
+			},
+			want: []Permission{
+				{Service: "s3", Action: "s3:GetObject", Resource: "*", Access: AccessDenied, Effect: Allow,
+					Policies: []string{"app"}, DeniedBy: []string{"guardrails"}},
+			},
+		},
+		{
+			name: "conditional deny makes allow conditional",
+			policies: map[string]string{
+				"app": `{"Statement": {"Effect": "Allow", "Action": "s3:PutObject", "Resource": "arn:aws:s3:::uploads/*"}}`,
+				"guardrails": `{"Statement": {"Effect": "Deny", "Action": "s3:PutObject", "Resource": "*",
+					"Condition": {"Bool": {"aws:SecureTransport": "false"}}}}`,
+			},
+			want: []Permission{
+				{Service: "s3", Action: "s3:PutObject", Resource: "arn:aws:s3:::uploads/*", Access: AccessConditional, Effect: Allow,
+					Policies: []string{"app"}, DeniedBy: []string{"guardrails"}},
+			},
+		},
+		{
+			name: "deny on other resources leaves allow",
+			policies: map[string]string{
+				"app":        `{"Statement": {"Effect": "Allow", "Action": "s3:GetObject", "Resource": "*"}}`,
+				"guardrails": `{"Statement": {"Effect": "Deny", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::secret/*"}}`,
+			},
+			want: []Permission{
+				{Service: "s3", Action: "s3:GetObject", Resource: "*", Access: AccessAllowed, Effect: Allow, Policies: []string{"app"}},
+				{Service: "s3", Action: "s3:GetObject", Resource: "arn:aws:s3:::secret/*", Access: AccessDenied, Effect: Deny,
+					Policies: []string{"guardrails"}},
+			},
+		},
+		{
+			name: "NotResource allow",
+			policies: map[string]string{
+				"app": `{"Statement": {"Effect": "Allow", "Action": "s3:DeleteObject", "NotResource": "arn:aws:s3:::backups/*"}}`,
+			},
+			want: []Permission{
+				{Service: "s3", Action: "s3:DeleteObject", Resource: "NOT arn:aws:s3:::backups/*", Access: AccessAllowed, Effect: Allow,
+					Policies: []string{"app"}},
+			},
+		},
+		{
+			name: "every action keeps the wildcard row",
+			policies: map[string]string{
+				"admin": `{"Statement": {"Effect": "Allow", "Action": "*", "Resource": "*"}}`,
+			},
+			want: []Permission{
+				{Service: "*", Action: "*", Resource: "*", Access: AccessAllowed, Effect: Allow, Policies: []string{"admin"}},
+				{Service: "s3", Action: "s3:GetObject", Resource: "*", Access: AccessAllowed, Effect: Allow, Policies: []string{"admin"}},
+			},
+		},
+		{
+			name: "uncatalogued service is shown as written",
+			policies: map[string]string{
+				"app": `{"Statement": {"Effect": "Allow", "Action": "acme:Get*", "Resource": "*"}}`,
+			},
+			want: []Permission{
+				{Service: "acme", Action: "acme:Get*", Resource: "*", Access: AccessAllowed, Effect: Allow, Policies: []string{"app"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var policies []Policy
+			for _, name := range []string{"admin", "app", "guardrails"} {
+				if document, ok := tt.policies[name]; ok {
+					policies = append(policies, Policy{Name: name, Document: mustParse(t, document)})
+				}
+			}
+			got := EffectivePermissions(policies)
+			for _, want := range tt.want {
+				row, ok := findPermission(got, want.Action, want.Resource, want.Effect)
+				if !ok {
+					t.Errorf("no %s row for %s on %s in %+v", want.Effect, want.Action, want.Resource, got)
+					continue
+				}
+				if !reflect.DeepEqual(row, want) {
+					t.Errorf("row = %+v, want %+v", row, want)
+				}
+			}
+		})
+	}
+}
+
+func findPermission(permissions []Permission, action, resource string, effect Effect) (Permission, bool) {
+	for _, p := range permissions {
+		if p.Action == action && p.Resource == resource && p.Effect == effect {
+			return p, true
+		}
+	}
+	return Permission{}, false
+}
@@ -0,0 +1,111 @@
+package policy
+
+// Decision is the outcome of evaluating a request
+type Decision int
+
+const (
+	// ImplicitDeny means no statement allowed the request
+	ImplicitDeny Decision = iota
+	// Allowed means a statement allowed the request and none denied it
+	Allowed
+	// ExplicitDeny means a Deny statement matched the request
+	ExplicitDeny
+)
+
+func (d Decision) String() string {
+	switch d {
+	case Allowed:
+		return "allowed"
+	case ExplicitDeny:
+		return "explicitly denied"
+	}
+	return "implicitly denied"
+}
+
+// Request is the API call being evaluated
+type Request struct {
+	// Principal is the ARN, service principal or federated provider making
+	// the request. It is only compared against statements with a Principal,
+	// as in trust and resource policies.
+	Principal string
+	Action    string
+	Resource  string
+	Context   Context
+}
+
+// Policy is a named document evaluated with others, e.g. the managed and
+// inline policies attached to a role
+type Policy struct {
+	Name     string
+	Document *Document
+}
+
+// Match is a statement that applied to a request
+type Match struct {
+	Policy string
+	// Index is the statement's position in its document, from 0
+	Index     int
+	Statement Statement
+}
+
+// Result is the decision for a request and the statements behind it
+type Result struct {
+	Decision Decision
+	// Allows and Denies are the Allow and Deny statements that matched the
+	// request, conditions included
+	Allows []Match
+	Denies []Match
+	// Unmet are the statements whose action, resource and principal match
+	// but whose conditions do not hold for the request context
+	Unmet []Match
+}
+
+// Evaluate decides a request against a set of policies the way AWS does
+// within one account: any matching Deny wins, otherwise any matching Allow
+// allows, otherwise the request is implicitly denied. A statement matches
+// when its action, resource, principal and every condition apply.
+func Evaluate(req Request, policies []Policy) Result {
+	var result Result
+	for _, p := range policies {
+		if p.Document == nil {
+			continue
+		}
+		for i, s := range p.Document.Statements {
+			if !s.matchesRequest(req) {
+				continue
+			}
+			match := Match{Policy: p.Name, Index: i, Statement: s}
+			if !s.conditionsMet(req.Context) {
+				result.Unmet = append(result.Unmet, match)
+				continue
+			}
+			if s.Effect == Deny {
+				result.Denies = append(result.Denies, match)
+			} else {
+				result.Allows = append(result.Allows, match)
+			}
+		}
+	}
+
+	switch {
+	case len(result.Denies) > 0:
+		result.Decision = ExplicitDeny
+	case len(result.Allows) > 0:
+		result.Decision = Allowed
+	}
+	return result
+}
+
+// Applies reports whether the statement matches the request, regardless of
+// its effect
+func (s Statement) Applies(req Request) bool {
+	return s.matchesRequest(req) && s.conditionsMet(req.Context)
+}
+
+// matchesRequest reports whether the statement's action, resource and
+// principal match the request, leaving conditions aside
+func (s Statement) matchesRequest(req Request) bool {
+	return s.matchesAction(req.Action) &&
+		s.matchesResource(req.Resource, req.Context) &&
+		s.matchesPrincipal(req.Principal)
+}
@@ -0,0 +1,113 @@
+package policy
+
+import (
+	"testing"
+)
+
+func mustParse(t *testing.T, document string) *Document {
+	t.Helper()
+	doc, err := Parse(document)
+	if err != nil {
+		t.Fatalf("Parse(%s) error = %v", document, err)
+	}
+	return doc
+}
+
+func TestEvaluate(t *testing.T) {
+	policies := []Policy{
+		{Name: "s3-read", Document: mustParse(t, `{"Statement": [
+			{"Effect": "Allow", "Action": ["s3:Get*", "s3:List*"], "Resource": "*"},
+			{"Effect": "Allow", "Action": "s3:PutObject", "Resource": "arn:aws:s3:::uploads/*",
+			 "Condition": {"Bool": {"aws:SecureTransport": "true"}}}]}`)},
+		{Name: "guardrails", Document: mustParse(t, `{"Statement": [
+			{"Effect": "Deny", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::secret/*"},
+			{"Effect": "Deny", "NotAction": "s3:*", "Resource": "*",
+			 "Condition": {"StringNotEquals": {"aws:RequestedRegion": "eu-west-1"}}}]}`)},
+	}
+
+	tests := []struct {
+		name   string
+		req    Request
+		want   Decision
+		allows int
+		denies int
+		unmet  int
+	}{
+		{
+			name:   "allowed",
+			req:    Request{Action: "s3:GetObject", Resource: "arn:aws:s3:::reports/q1.csv"},
+			want:   Allowed,
+			allows: 1,
+		},
+		{
+			name:   "explicit deny overrides allow",
+			req:    Request{Action: "s3:GetObject", Resource: "arn:aws:s3:::secret/key"},
+			want:   ExplicitDeny,
+			allows: 1,
+			denies: 1,
+		},
+		{
+			name: "implicit deny",
+			req:  Request{Action: "s3:DeleteObject", Resource: "arn:aws:s3:::reports/q1.csv"},
+			want: ImplicitDeny,
+		},
+		{
+			name:  "unmet condition",
+			req:   Request{Action: "s3:PutObject", Resource: "arn:aws:s3:::uploads/a", Context: Context{"aws:SecureTransport": {"false"}}},
+			want:  ImplicitDeny,
+			unmet: 1,
+		},
+		{
+			name:   "met condition",
+			req:    Request{Action: "s3:PutObject", Resource: "arn:aws:s3:::uploads/a", Context: Context{"aws:SecureTransport": {"true"}}},
+			want:   Allowed,
+			allows: 1,
+		},
+		{
+			name:   "conditional deny with NotAction",
+			req:    Request{Action: "ec2:RunInstances", Resource: "*", Context: Context{"aws:RequestedRegion": {"us-east-1"}}},
+			want:   ExplicitDeny,
+			denies: 1,
+		},
+		{
+			name:  "conditional deny that does not apply",
+			req:   Request{Action: "ec2:RunInstances", Resource: "*", Context: Context{"aws:RequestedRegion": {"eu-west-1"}}},
+			want:  ImplicitDeny,
+			unmet: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Evaluate(tt.req, policies)
+			if got.Decision != tt.want {
+				t.Errorf("Decision = %v, want %v", got.Decision, tt.want)
+			}
+			if len(got.Allows) != tt.allows || len(got.Denies) != tt.denies || len(got.Unmet) != tt.unmet {
+				t.Errorf("matches = %d allows, %d denies, %d unmet; want %d, %d, %d",
+					len(got.Allows), len(got.Denies), len(got.Unmet), tt.allows, tt.denies, tt.unmet)
+			}
+		})
+	}
+}
+
+func TestEvaluatePrincipal(t *testing.T) {
+	trust := []Policy{{Name: "trust", Document: mustParse(t, `{"Statement": [
+		{"Effect": "Allow", "Principal": {"AWS": "arn:aws:iam::111111111111:root"}, "Action": "sts:AssumeRole"},
+		{"Effect": "Deny", "Principal": {"AWS": "arn:aws:iam::111111111111:user/intern"}, "Action": "sts:AssumeRole"}]}`)}}
+
+	tests := []struct {
+		principal string
+		want      Decision
+	}{
+		{"arn:aws:iam::111111111111:role/ci", Allowed},
+		{"arn:aws:iam::111111111111:user/intern", ExplicitDeny},
+		{"arn:aws:iam::222222222222:role/ci", ImplicitDeny},
+	}
+	for _, tt := range tests {
+		req := Request{Principal: tt.principal, Action: "sts:AssumeRole"}
+		if got := Evaluate(req, trust).Decision; got != tt.want {
+			t.Errorf("Evaluate(%s) = %v, want %v", tt.principal, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package policy
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Severity ranks a lint finding
+type Severity int
+
+const (
+	// SeverityMedium marks grants broader than they likely need to be
+	SeverityMedium Severity = iota
+	// SeverityHigh marks grants that amount to, or allow escalation to,
+	// full access
+	SeverityHigh
+)
+
+func (s Severity) String() string {
+	if s == SeverityHigh {
+		return "high"
+	}
+	return "medium"
+}
+
+// Lint rule IDs
+const (
+	RuleWildcardAction    = "wildcard-action"
+	RuleWildcardWrite     = "wildcard-resource-write"
+	RulePassRoleWildcard  = "passrole-wildcard"
+	RuleNotActionAllow    = "notaction-allow"
+	RuleSensitiveAction   = "sensitive-without-condition"
+	RuleTrustAnyPrincipal = "trust-any-principal"
+	RuleTrustExternal     = "trust-external-account"
+)
+
+// Finding is a lint rule a policy statement breaks
+type Finding struct {
+	Rule     string
+	Severity Severity
+	Policy   string
+	// Index is the statement's position in its document, from 0
+	Index   int
+	Sid     string
+	Message string
+}
+
+// sensitiveActions allow privilege escalation, data exfiltration or
+// tampering with access control, so should be scoped with conditions
+var sensitiveActions = []string{
+	"iam:AttachGroupPolicy",
+	"iam:AttachRolePolicy",
+	"iam:AttachUserPolicy",
+	"iam:CreateAccessKey",
+	"iam:CreateLoginProfile",
+	"iam:CreatePolicyVersion",
+	"iam:PassRole",
+	"iam:PutGroupPolicy",
+	"iam:PutRolePolicy",
+	"iam:PutUserPolicy",
+	"iam:SetDefaultPolicyVersion",
+	"iam:UpdateAssumeRolePolicy",
+	"iam:UpdateLoginProfile",
+	"kms:Decrypt",
+	"kms:PutKeyPolicy",
+	"kms:ScheduleKeyDeletion",
+	"lambda:AddPermission",
+	"lambda:UpdateFunctionCode",
+	"s3:DeleteBucketPolicy",
+	"s3:PutBucketPolicy",
+	"secretsmanager:GetSecretValue",
+	"ssm:SendCommand",
+	"ssm:StartSession",
+	"sts:AssumeRole",
+}
+
+// Lint checks the Allow statements of an identity policy for overly broad
+// grants
+func Lint(p Policy) []Finding {
+	if p.Document == nil {
+		return nil
+	}
+	var findings []Finding
+	for i, s := range p.Document.Statements {
+		if s.Effect != Allow {
+			continue
+		}
+		add := func(rule string, severity Severity, format string, args ...any) {
+			findings = append(findings, Finding{
+				Rule: rule, Severity: severity, Policy: p.Name, Index: i, Sid: s.Sid,
+				Message: fmt.Sprintf(format, args...),
+			})
+		}
+
+		if s.NotAction != nil {
+			add(RuleNotActionAllow, SeverityMedium,
+				"Allow with NotAction grants every action except %s, including ones added to AWS later",
+				strings.Join(s.NotAction, ", "))
+			continue
+		}
+		if contains(s.Action, "*") || contains(s.Action, "*:*") {
+			add(RuleWildcardAction, SeverityHigh, `Allows every action ("Action": "*")`)
+			continue
+		}
+
+		anyResource := s.NotResource == nil && (s.Resource == nil || contains(s.Resource, "*"))
+		passRoleAnywhere := anyResource && s.matchesAction("iam:PassRole")
+		if passRoleAnywhere {
+			add(RulePassRoleWildcard, SeverityHigh,
+				"iam:PassRole on every role lets the holder hand any role, including admin roles, to a service")
+		}
+		if anyResource {
+			writes := s.writeActions()
+			if passRoleAnywhere {
+				// Already reported on its own
+				writes = slices.DeleteFunc(writes, func(a string) bool { return a == "iam:PassRole" })
+			}
+			if len(writes) > 0 {
+				add(RuleWildcardWrite, SeverityMedium, "Write access on every resource: %s", summarize(writes, 3))
+			}
+		}
+		if !s.HasConditions() {
+			var sensitive []string
+			for _, action := range sensitiveActions {
+				if s.matchesAction(action) && !(passRoleAnywhere && action == "iam:PassRole") {
+					sensitive = append(sensitive, action)
+				}
+			}
+			if len(sensitive) > 0 {
+				add(RuleSensitiveAction, SeverityMedium, "Sensitive actions without conditions: %s", summarize(sensitive, 3))
+			}
+		}
+	}
+	return findings
+}
+
+// LintTrust checks a role trust policy for principals that open the role
+// to anyone, or to other accounts without an external ID. account is the
+// ID of the account owning the role.
+func LintTrust(p Policy, account string) []Finding {
+	if p.Document == nil {
+		return nil
+	}
+	var findings []Finding
+	for i, s := range p.Document.Statements {
+		if s.Effect != Allow || !s.allowsAssumeRole() {
+			continue
+		}
+		add := func(rule string, severity Severity, format string, args ...any) {
+			findings = append(findings, Finding{
+				Rule: rule, Severity: severity, Policy: p.Name, Index: i, Sid: s.Sid,
+				Message: fmt.Sprintf(format, args...),
+			})
+		}
+
+		var external []string
+		for _, v := range s.Principal["AWS"] {
+			switch {
+			case v == "*":
+				if s.HasConditions() {
+					add(RuleTrustAnyPrincipal, SeverityMedium,
+						`Any AWS principal ("AWS": "*") can assume the role when the conditions hold`)
+				} else {
+					add(RuleTrustAnyPrincipal, SeverityHigh,
+						`Any AWS principal ("AWS": "*") in any account can assume the role`)
+				}
+			case isAccount(v) && accountOf(v) != account:
+				external = append(external, accountOf(v))
+			}
+		}
+		if len(external) > 0 && !s.hasConditionKey("sts:ExternalId") {
+			accounts := "external account "
+			if len(external) > 1 {
+				accounts = "external accounts "
+			}
+			add(RuleTrustExternal, SeverityMedium,
+				"Every principal in %s%s can assume the role without sts:ExternalId",
+				accounts, strings.Join(external, ", "))
+		}
+	}
+	return findings
+}
+
+// SortFindings orders findings by severity, highest first, then by policy
+// and statement
+func SortFindings(findings []Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.Severity != b.Severity {
+			return a.Severity > b.Severity
+		}
+		if a.Policy != b.Policy {
+			return a.Policy < b.Policy
+		}
+		return a.Index < b.Index
+	})
+}
+
+// writeActions lists the catalogued Write and Permissions management
+// actions the statement allows
+func (s Statement) writeActions() []string {
+	var writes []string
+	for _, action := range s.expandedActions() {
+		level, ok := Level(action)
+		if ok && (level == LevelWrite || level == LevelPermissions) {
+			writes = append(writes, action)
+		}
+	}
+	return writes
+}
+
+// allowsAssumeRole reports whether a trust statement grants any of the
+// sts:AssumeRole actions
+func (s Statement) allowsAssumeRole() bool {
+	for _, action := range []string{"sts:AssumeRole", "sts:AssumeRoleWithSAML", "sts:AssumeRoleWithWebIdentity"} {
+		if s.matchesAction(action) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasConditionKey reports whether any condition of the statement tests key
+func (s Statement) hasConditionKey(key string) bool {
+	for _, keys := range s.Condition {
+		for k := range keys {
+			if strings.EqualFold(k, key) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// summarize lists the first n items and how many more there are
+func summarize(items []string, n int) string {
+	if len(items) <= n {
+		return strings.Join(items, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(items[:n], ", "), len(items)-n)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
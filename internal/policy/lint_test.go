@@ -0,0 +1,116 @@
+package policy
+
+import (
+	"reflect"
+	"testing"
+)
+
+// ruleSeverities lists findings as "rule/severity" for comparison
+func ruleSeverities(findings []Finding) []string {
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.Rule+"/"+f.Severity.String())
+	}
+	return got
+}
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		want     []string
+	}{
+		{
+			name:     "every action",
+			document: `{"Statement": {"Effect": "Allow", "Action": "*", "Resource": "*"}}`,
+			want:     []string{"wildcard-action/high"},
+		},
+		{
+			name:     "write on every resource",
+			document: `{"Statement": {"Effect": "Allow", "Action": ["s3:GetObject", "s3:PutObject"], "Resource": "*"}}`,
+			want:     []string{"wildcard-resource-write/medium"},
+		},
+		{
+			name:     "write on one resource",
+			document: `{"Statement": {"Effect": "Allow", "Action": "s3:PutObject", "Resource": "arn:aws:s3:::uploads/*"}}`,
+		},
+		{
+			name:     "pass any role",
+			document: `{"Statement": {"Effect": "Allow", "Action": "iam:PassRole", "Resource": "*"}}`,
+			want:     []string{"passrole-wildcard/high"},
+		},
+		{
+			name:     "allow with NotAction",
+			document: `{"Statement": {"Effect": "Allow", "NotAction": "iam:*", "Resource": "*"}}`,
+			want:     []string{"notaction-allow/medium"},
+		},
+		{
+			name:     "sensitive action without conditions",
+			document: `{"Statement": {"Effect": "Allow", "Action": "kms:Decrypt", "Resource": "arn:aws:kms:eu-west-1:123456789012:key/1"}}`,
+			want:     []string{"sensitive-without-condition/medium"},
+		},
+		{
+			name: "sensitive action with conditions",
+			document: `{"Statement": {"Effect": "Allow", "Action": "kms:Decrypt", "Resource": "arn:aws:kms:eu-west-1:123456789012:key/1",
+				"Condition": {"StringEquals": {"kms:ViaService": "s3.eu-west-1.amazonaws.com"}}}}`,
+		},
+		{
+			name:     "deny statements are not linted",
+			document: `{"Statement": {"Effect": "Deny", "Action": "*", "Resource": "*"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ruleSeverities(Lint(Policy{Name: "test", Document: mustParse(t, tt.document)}))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lint() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLintTrust(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		want     []string
+	}{
+		{
+			name:     "any principal",
+			document: `{"Statement": {"Effect": "Allow", "Principal": {"AWS": "*"}, "Action": "sts:AssumeRole"}}`,
+			want:     []string{"trust-any-principal/high"},
+		},
+		{
+			name: "any principal with conditions",
+			document: `{"Statement": {"Effect": "Allow", "Principal": {"AWS": "*"}, "Action": "sts:AssumeRole",
+				"Condition": {"StringEquals": {"aws:PrincipalOrgID": "o-example"}}}}`,
+			want: []string{"trust-any-principal/medium"},
+		},
+		{
+			name:     "external account",
+			document: `{"Statement": {"Effect": "Allow", "Principal": {"AWS": "arn:aws:iam::210987654321:root"}, "Action": "sts:AssumeRole"}}`,
+			want:     []string{"trust-external-account/medium"},
+		},
+		{
+			name: "external account with external ID",
+			document: `{"Statement": {"Effect": "Allow", "Principal": {"AWS": "210987654321"}, "Action": "sts:AssumeRole",
+				"Condition": {"StringEquals": {"sts:ExternalId": "audit"}}}}`,
+		},
+		{
+			name:     "same account",
+			document: `{"Statement": {"Effect": "Allow", "Principal": {"AWS": "arn:aws:iam::123456789012:root"}, "Action": "sts:AssumeRole"}}`,
+		},
+		{
+			name:     "service principal",
+			document: `{"Statement": {"Effect": "Allow", "Principal": {"Service": "lambda.amazonaws.com"}, "Action": "sts:AssumeRole"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ruleSeverities(LintTrust(Policy{Name: "Trust policy", Document: mustParse(t, tt.document)}, "123456789012"))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LintTrust() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
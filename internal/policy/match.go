@@ -0,0 +1,180 @@
+package policy
+
+import (
+	"strings"
+)
+
+// MatchAction reports whether an IAM action pattern matches an action. Both
+// are compared case-insensitively; "*" matches any run of characters and "?"
+// any single character.
+func MatchAction(pattern, action string) bool {
+	return matchWildcard(strings.ToLower(pattern), strings.ToLower(action))
+}
+
+// MatchResource reports whether a resource pattern matches an ARN. Unlike
+// actions, ARNs are compared case-sensitively.
+func MatchResource(pattern, arn string) bool {
+	return matchWildcard(pattern, arn)
+}
+
+// matchWildcard matches s against a glob where "*" matches any run of
+// characters and "?" any single character
+func matchWildcard(pattern, s string) bool {
+	// Iterative glob match with backtracking to the last "*"
+	p, i := 0, 0
+	star, mark := -1, 0
+	for i < len(s) {
+		switch {
+		case p < len(pattern) && (pattern[p] == '?' || pattern[p] == s[i]):
+			p++
+			i++
+		case p < len(pattern) && pattern[p] == '*':
+			star, mark = p, i
+			p++
+		case star >= 0:
+			p = star + 1
+			mark++
+			i = mark
+		default:
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
+
+// matchesAction reports whether the statement applies to the action
+func (s Statement) matchesAction(action string) bool {
+	if s.NotAction != nil {
+		return !anyMatch(s.NotAction, action, MatchAction)
+	}
+	return anyMatch(s.Action, action, MatchAction)
+}
+
+// matchesResource reports whether the statement applies to the resource.
+// Statements without Resource or NotResource, as in trust policies, apply to
+// any resource. Policy variables such as ${aws:username} are substituted
+// from the request context first.
+func (s Statement) matchesResource(resource string, ctx Context) bool {
+	match := func(pattern, arn string) bool {
+		return MatchResource(substitute(pattern, ctx), arn)
+	}
+	switch {
+	case s.NotResource != nil:
+		return !anyMatch(s.NotResource, resource, match)
+	case s.Resource != nil:
+		return anyMatch(s.Resource, resource, match)
+	}
+	return true
+}
+
+// matchesPrincipal reports whether the statement applies to the principal.
+// Identity policies have no Principal and apply to the principal they are
+// attached to.
+func (s Statement) matchesPrincipal(principal string) bool {
+	switch {
+	case s.NotPrincipal != nil:
+		return !s.NotPrincipal.Matches(principal)
+	case s.Principal != nil:
+		return s.Principal.Matches(principal)
+	}
+	return true
+}
+
+// Matches reports whether the principal, an ARN, service principal or
+// federated provider, is one of p's values. An account ID or account root ARN
+// matches every principal in that account.
+func (p Principal) Matches(principal string) bool {
+	for _, vals := range p {
+		for _, v := range vals {
+			switch {
+			case v == "*" || v == principal:
+				return true
+			case strings.ContainsAny(v, "*?") && matchWildcard(v, principal):
+				return true
+			case accountOf(v) != "" && accountOf(v) == accountOf(principal) && isAccount(v):
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isAccount reports whether a principal value names a whole account
+func isAccount(v string) bool {
+	return isAccountID(v) || (strings.HasPrefix(v, "arn:") && strings.HasSuffix(v, ":root"))
+}
+
+func isAccountID(v string) bool {
+	if len(v) != 12 {
+		return false
+	}
+	for _, c := range v {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// accountOf returns the account ID of an account ID or ARN, "" for anything
+// else
+func accountOf(v string) string {
+	if isAccountID(v) {
+		return v
+	}
+	parts := strings.SplitN(v, ":", 6)
+	if len(parts) == 6 && parts[0] == "arn" {
+		return parts[4]
+	}
+	return ""
+}
+
+func anyMatch(patterns []string, s string, match func(pattern, s string) bool) bool {
+	for _, p := range patterns {
+		if match(p, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// substitute replaces policy variables, e.g. ${aws:username}, with their
+// single value in the request context. ${*}, ${?} and ${$} stand for the
+// literal characters. Variables without a value are left as written, so they
+// match nothing.
+func substitute(s string, ctx Context) string {
+	if !strings.Contains(s, "${") {
+		return s
+	}
+	var b strings.Builder
+	for {
+		start := strings.Index(s, "${")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(s[start:], "}")
+		if end < 0 {
+			break
+		}
+		b.WriteString(s[:start])
+		name := s[start+2 : start+end]
+		switch name {
+		case "*", "?", "$":
+			// matchWildcard has no escape syntax, so a literal "*" or "?"
+			// still matches as a wildcard
+			b.WriteString(name)
+		default:
+			if vals := ctx.Values(name); len(vals) == 1 {
+				b.WriteString(vals[0])
+			} else {
+				b.WriteString(s[start : start+end+1])
+			}
+		}
+		s = s[start+end+1:]
+	}
+	b.WriteString(s)
+	return b.String()
+}
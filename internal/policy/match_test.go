@@ -0,0 +1,138 @@
+package policy
+
+import (
+	"testing"
+)
+
+func TestMatchAction(t *testing.T) {
+	tests := []struct {
+		pattern, action string
+		want            bool
+	}{
+		{"s3:GetObject", "s3:GetObject", true},
+		{"s3:getobject", "S3:GetObject", true},
+		{"s3:Get*", "s3:GetObject", true},
+		{"s3:Get*", "s3:PutObject", false},
+		{"s3:*Object", "s3:DeleteObject", true},
+		{"s3:?etObject", "s3:GetObject", true},
+		{"s3:?etObject", "s3:GettObject", false},
+		{"*", "iam:PassRole", true},
+		{"iam:*", "s3:GetObject", false},
+		{"s3:GetObject", "s3:GetObjectAcl", false},
+	}
+	for _, tt := range tests {
+		if got := MatchAction(tt.pattern, tt.action); got != tt.want {
+			t.Errorf("MatchAction(%q, %q) = %v, want %v", tt.pattern, tt.action, got, tt.want)
+		}
+	}
+}
+
+func TestMatchResource(t *testing.T) {
+	tests := []struct {
+		pattern, arn string
+		want         bool
+	}{
+		{"arn:aws:s3:::bucket/*", "arn:aws:s3:::bucket/key", true},
+		{"arn:aws:s3:::bucket/*", "arn:aws:s3:::bucket", false},
+		{"arn:aws:s3:::Bucket/*", "arn:aws:s3:::bucket/key", false},
+		{"arn:aws:iam::*:role/app-?", "arn:aws:iam::123456789012:role/app-1", true},
+		{"*", "arn:aws:sqs:eu-west-1:123456789012:queue", true},
+	}
+	for _, tt := range tests {
+		if got := MatchResource(tt.pattern, tt.arn); got != tt.want {
+			t.Errorf("MatchResource(%q, %q) = %v, want %v", tt.pattern, tt.arn, got, tt.want)
+		}
+	}
+}
+
+func TestStatementMatchesAction(t *testing.T) {
+	tests := []struct {
+		name   string
+		stmt   Statement
+		action string
+		want   bool
+	}{
+		{"action", Statement{Action: []string{"s3:Get*", "s3:List*"}}, "s3:ListBucket", true},
+		{"action miss", Statement{Action: []string{"s3:Get*"}}, "s3:PutObject", false},
+		{"notaction excludes", Statement{NotAction: []string{"iam:*"}}, "iam:PassRole", false},
+		{"notaction includes the rest", Statement{NotAction: []string{"iam:*"}}, "s3:GetObject", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.stmt.matchesAction(tt.action); got != tt.want {
+				t.Errorf("matchesAction(%q) = %v, want %v", tt.action, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatementMatchesResource(t *testing.T) {
+	ctx := Context{"aws:username": {"alice"}}
+	tests := []struct {
+		name     string
+		stmt     Statement
+		resource string
+		want     bool
+	}{
+		{"resource", Statement{Resource: []string{"arn:aws:s3:::bucket/*"}}, "arn:aws:s3:::bucket/key", true},
+		{"resource miss", Statement{Resource: []string{"arn:aws:s3:::bucket/*"}}, "arn:aws:s3:::other/key", false},
+		{"notresource excludes", Statement{NotResource: []string{"arn:aws:s3:::secret/*"}}, "arn:aws:s3:::secret/key", false},
+		{"notresource includes the rest", Statement{NotResource: []string{"arn:aws:s3:::secret/*"}}, "arn:aws:s3:::bucket/key", true},
+		{"no resource element", Statement{}, "arn:aws:s3:::bucket/key", true},
+		{"policy variable", Statement{Resource: []string{"arn:aws:s3:::home/${aws:username}/*"}}, "arn:aws:s3:::home/alice/notes", true},
+		{"policy variable of another user", Statement{Resource: []string{"arn:aws:s3:::home/${aws:username}/*"}}, "arn:aws:s3:::home/bob/notes", false},
+		{"policy variable without a value", Statement{Resource: []string{"arn:aws:s3:::home/${aws:userid}/*"}}, "arn:aws:s3:::home/alice/notes", false},
+		{"literal wildcard variable", Statement{Resource: []string{"arn:aws:s3:::bucket/${*}"}}, "arn:aws:s3:::bucket/key", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.stmt.matchesResource(tt.resource, ctx); got != tt.want {
+				t.Errorf("matchesResource(%q) = %v, want %v", tt.resource, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubstitute(t *testing.T) {
+	ctx := Context{"aws:username": {"alice"}, "aws:PrincipalTag/team": {"payments"}, "s3:prefix": {"a", "b"}}
+	tests := []struct {
+		in, want string
+	}{
+		{"arn:aws:s3:::bucket/${aws:username}/*", "arn:aws:s3:::bucket/alice/*"},
+		{"${aws:principaltag/team}-${aws:username}", "payments-alice"},
+		{"${s3:prefix}", "${s3:prefix}"},
+		{"${aws:userid}", "${aws:userid}"},
+		{"${$}{literal}", "${literal}"},
+		{"no variables", "no variables"},
+		{"unterminated ${aws:username", "unterminated ${aws:username"},
+	}
+	for _, tt := range tests {
+		if got := substitute(tt.in, ctx); got != tt.want {
+			t.Errorf("substitute(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPrincipalMatches(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal Principal
+		caller    string
+		want      bool
+	}{
+		{"any", Principal{"AWS": {"*"}}, "arn:aws:iam::111111111111:role/x", true},
+		{"exact role", Principal{"AWS": {"arn:aws:iam::111111111111:role/x"}}, "arn:aws:iam::111111111111:role/x", true},
+		{"account root", Principal{"AWS": {"arn:aws:iam::111111111111:root"}}, "arn:aws:iam::111111111111:role/x", true},
+		{"account ID", Principal{"AWS": {"111111111111"}}, "arn:aws:iam::111111111111:user/y", true},
+		{"other account", Principal{"AWS": {"222222222222"}}, "arn:aws:iam::111111111111:user/y", false},
+		{"specific role does not match its account", Principal{"AWS": {"arn:aws:iam::111111111111:role/x"}}, "arn:aws:iam::111111111111:role/z", false},
+		{"service", Principal{"Service": {"lambda.amazonaws.com"}}, "lambda.amazonaws.com", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.principal.Matches(tt.caller); got != tt.want {
+				t.Errorf("Matches(%q) = %v, want %v", tt.caller, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,330 @@
+package components
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/johnoct/a3s/internal/aws/iam"
+)
+
+// Column is a role list column. Columns are identified by ID in the config
+// file and in the :columns command.
+type Column struct {
+	ID    string
+	Title string
+	// MinWidth and MaxWidth bound the width computed from the cell values
+	MinWidth int
+	MaxWidth int
+	// Flex columns take whatever width is left over
+	Flex bool
+	// NeedsDetails marks columns that ListRoles does not populate; their
+	// values are fetched per role in the background
+	NeedsDetails bool
+	// LastUse marks columns showing the last use, which the list fills in
+	// per role in the background
+	LastUse bool
+	// Findings marks the column showing lint finding badges, which the list
+	// fills in per role in the background; Value is unused
+	Findings bool
+	Value    func(role iam.Role) string
+
+	sort sortColumn
+}
+
+// DefaultColumns is the column selection used when none is configured
+var DefaultColumns = []string{"name", "created", "lastused", "description", "findings"}
+
+// tagColumnPrefix introduces a column showing the value of one tag
+const tagColumnPrefix = "tag:"
+
+var columnRegistry = []Column{
+	{
+		ID: "name", Title: "Role Name", MinWidth: 20, MaxWidth: 48, sort: sortName,
+		Value: func(r iam.Role) string { return r.Name },
+	},
+	{
+		ID: "created", Title: "Created", MinWidth: 12, MaxWidth: 12, sort: sortCreated,
+		Value: func(r iam.Role) string { return r.CreateDate.Format("2006-01-02") },
+	},
+	{
+		ID: "lastused", Title: "Last Used", MinWidth: 12, MaxWidth: 28, sort: sortLastUsed, LastUse: true,
+		Value: func(r iam.Role) string {
+			if r.LastUsed == nil {
+				return "Never"
+			}
+			if r.LastUsedRegion == "" {
+				return r.LastUsed.Format("2006-01-02")
+			}
+			return r.LastUsed.Format("2006-01-02") + " " + r.LastUsedRegion
+		},
+	},
+	{
+		ID: "description", Title: "Description", MinWidth: 20, Flex: true,
+		Value: func(r iam.Role) string { return r.Description },
+	},
+	{
+		ID: "path", Title: "Path", MinWidth: 6, MaxWidth: 32,
+		Value: func(r iam.Role) string { return r.Path },
+	},
+	{
+		ID: "roleid", Title: "Role ID", MinWidth: 8, MaxWidth: 24,
+		Value: func(r iam.Role) string { return r.RoleID },
+	},
+	{
+		ID: "arn", Title: "ARN", MinWidth: 20, MaxWidth: 80,
+		Value: func(r iam.Role) string { return r.ARN },
+	},
+	{
+		ID: "maxsession", Title: "Max Session", MinWidth: 12, MaxWidth: 12,
+		Value: func(r iam.Role) string {
+			if r.MaxSessionDuration == 0 {
+				return ""
+			}
+			return formatSessionDuration(r.MaxSessionDuration)
+		},
+	},
+	{
+		ID: "trust", Title: "Trusted By", MinWidth: 12, MaxWidth: 40,
+		Value: func(r iam.Role) string { return iam.TrustSummary(r.TrustPolicy) },
+	},
+	{
+		ID: "managed", Title: "Managed", MinWidth: 8, MaxWidth: 8, NeedsDetails: true,
+		Value: func(r iam.Role) string { return strconv.Itoa(len(r.ManagedPolicies)) },
+	},
+	{
+		ID: "inline", Title: "Inline", MinWidth: 7, MaxWidth: 7, NeedsDetails: true,
+		Value: func(r iam.Role) string { return strconv.Itoa(len(r.InlinePolicies)) },
+	},
+	{
+		ID: "boundary", Title: "Boundary", MinWidth: 9, MaxWidth: 32, NeedsDetails: true,
+		Value: func(r iam.Role) string {
+			if r.PermissionsBoundary == nil {
+				return "none"
+			}
+			return r.PermissionsBoundary.Name
+		},
+	},
+	{
+		ID: "findings", Title: "Findings", MinWidth: 9, MaxWidth: 12, Findings: true,
+	},
+}
+
+// LookupColumn returns the column with the given ID. Any "tag:<Key>" ID is
+// valid and shows that tag's value.
+func LookupColumn(id string) (Column, bool) {
+	if key, ok := strings.CutPrefix(id, tagColumnPrefix); ok && key != "" {
+		return Column{
+			ID: id, Title: key, MinWidth: 6, MaxWidth: 30, NeedsDetails: true,
+			Value: func(r iam.Role) string {
+				for _, t := range r.Tags {
+					if t.Key == key {
+						return t.Value
+					}
+				}
+				return ""
+			},
+		}, true
+	}
+
+	for _, c := range columnRegistry {
+		if c.ID == id {
+			return c, true
+		}
+	}
+	return Column{}, false
+}
+
+// ColumnIDs lists the IDs of the built-in columns, for completion
+func ColumnIDs() []string {
+	ids := make([]string, 0, len(columnRegistry)+1)
+	for _, c := range columnRegistry {
+		ids = append(ids, c.ID)
+	}
+	return append(ids, tagColumnPrefix)
+}
+
+// ResolveColumns looks up a column selection, failing on unknown IDs
+func ResolveColumns(ids []string) ([]Column, error) {
+	columns := make([]Column, 0, len(ids))
+	for _, id := range ids {
+		c, ok := LookupColumn(id)
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q", id)
+		}
+		columns = append(columns, c)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("at least one column is required")
+	}
+	return columns, nil
+}
+
+// formatSessionDuration renders a maximum session duration in seconds as
+// hours and minutes, e.g. 1h or 1h30m
+func formatSessionDuration(seconds int32) string {
+	h, m := seconds/3600, seconds%3600/60
+	switch {
+	case h == 0:
+		return fmt.Sprintf("%dm", m)
+	case m == 0:
+		return fmt.Sprintf("%dh", h)
+	}
+	return fmt.Sprintf("%dh%02dm", h, m)
+}
+
+// listLayout caches what the list view derives from its rows: the cells of
+// each role and the widest cell of each column. Bubble Tea passes copies of
+// the model around, so the cache sits behind a pointer that View fills in
+// and updates replace, or trim, whenever rows or columns change.
+type listLayout struct {
+	cells map[string][]string
+	// widths is the widest cell, header included, of each column over the
+	// filtered roles; nil until computed
+	widths []int
+}
+
+// rowsChanged drops every cached cell, e.g. after the columns or the roles
+// were replaced
+func (m *ListModel) rowsChanged() {
+	m.layout = &listLayout{}
+}
+
+// rowChanged drops the cached cells of one role
+func (m *ListModel) rowChanged(arn string) {
+	if m.layout != nil {
+		delete(m.layout.cells, arn)
+		m.layout.widths = nil
+	}
+}
+
+// filterChanged drops the cached column widths after the filtered roles,
+// their order or the sort indicator changed
+func (m *ListModel) filterChanged() {
+	if m.layout != nil {
+		m.layout.widths = nil
+	}
+}
+
+// cells returns the cells of a role for the selected columns
+func (m *ListModel) cells(role iam.Role) []string {
+	if m.layout != nil {
+		if cells, ok := m.layout.cells[role.ARN]; ok {
+			return cells
+		}
+	}
+	cells := make([]string, len(m.columns))
+	for i, c := range m.columns {
+		cells[i] = m.cell(c, role)
+	}
+	if m.layout != nil {
+		if m.layout.cells == nil {
+			m.layout.cells = make(map[string][]string)
+		}
+		m.layout.cells[role.ARN] = cells
+	}
+	return cells
+}
+
+// contentWidths returns the widest cell, header included, of each column
+func (m *ListModel) contentWidths() []int {
+	if m.layout != nil && m.layout.widths != nil {
+		return m.layout.widths
+	}
+	widths := make([]int, len(m.columns))
+	for i, c := range m.columns {
+		widths[i] = lipgloss.Width(m.sortIndicator(c.Title, c.sort))
+	}
+	for _, role := range m.filteredRoles {
+		for i, cell := range m.cells(role) {
+			widths[i] = max(widths[i], lipgloss.Width(cell))
+		}
+	}
+	if m.layout != nil {
+		m.layout.widths = widths
+	}
+	return widths
+}
+
+// columnWidths sizes each column to its widest cell within its bounds. Flex
+// columns, or the last column when none flexes, share the remaining width.
+func (m *ListModel) columnWidths(availableWidth int) []int {
+	content := m.contentWidths()
+	widths := make([]int, len(m.columns))
+	used := 0
+	flex := 0
+	for i, c := range m.columns {
+		if c.Flex {
+			flex++
+			continue
+		}
+		w := content[i] + 1
+		if c.MaxWidth > 0 {
+			w = min(w, c.MaxWidth)
+		}
+		widths[i] = max(w, c.MinWidth)
+		used += widths[i] + 1
+	}
+
+	remaining := availableWidth - used
+	if flex == 0 {
+		widths[len(widths)-1] += max(0, remaining)
+		return widths
+	}
+	for i, c := range m.columns {
+		if c.Flex {
+			widths[i] = max(c.MinWidth, remaining/flex-1)
+		}
+	}
+	return widths
+}
+
+// cell renders a column value for a role, marking detail columns that are
+// still loading ("…") or failed to load ("-")
+func (m *ListModel) cell(c Column, role iam.Role) string {
+	if c.Findings {
+		result, ok := m.findings[role.ARN]
+		switch {
+		case !ok:
+			return "…"
+		case result.err != nil:
+			return "-"
+		}
+		return findingsBadge(result.findings)
+	}
+	if c.NeedsDetails {
+		details, ok := m.details[role.ARN]
+		if !ok {
+			return "…"
+		}
+		if details == nil {
+			return "-"
+		}
+		return c.Value(*details)
+	}
+	if c.LastUse && role.LastUsed == nil && m.lastUseLoading(role.ARN) {
+		return "…"
+	}
+	return c.Value(role)
+}
+
+// needsDetails reports whether any selected column is filled by the enricher
+func (m *ListModel) needsDetails() bool {
+	for _, c := range m.columns {
+		if c.NeedsDetails {
+			return true
+		}
+	}
+	return false
+}
+
+// needsFindings reports whether the findings column is selected
+func (m *ListModel) needsFindings() bool {
+	for _, c := range m.columns {
+		if c.Findings {
+			return true
+		}
+	}
+	return false
+}
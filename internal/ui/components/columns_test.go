@@ -0,0 +1,24 @@
+package components
+
+import (
+	"testing"
+)
+
+func TestFormatSessionDuration(t *testing.T) {
+	tests := []struct {
+		seconds int32
+		want    string
+	}{
+		{3600, "1h"},
+		{4200, "1h10m"},
+		{5400, "1h30m"},
+		{7500, "2h05m"},
+		{43200, "12h"},
+		{900, "15m"},
+	}
+	for _, tt := range tests {
+		if got := formatSessionDuration(tt.seconds); got != tt.want {
+			t.Errorf("formatSessionDuration(%d) = %q, want %q", tt.seconds, got, tt.want)
+		}
+	}
+}
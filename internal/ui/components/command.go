@@ -0,0 +1,195 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/johnoct/a3s/internal/ui/styles"
+)
+
+// CommandMsg is emitted when the user submits a line in the command bar
+type CommandMsg struct {
+	Line string
+}
+
+// CommandBar is the k9s-style ":" prompt with tab completion and history
+type CommandBar struct {
+	input  textinput.Model
+	active bool
+
+	// complete returns the candidate lines for the current input
+	complete    func(line string) []string
+	completions []string
+	completeIdx int
+
+	history    []string
+	historyIdx int
+	draft      string
+}
+
+const maxCommandHistory = 50
+
+// NewCommandBar creates a command bar using complete for tab completion
+func NewCommandBar(complete func(line string) []string) CommandBar {
+	ti := textinput.New()
+	ti.Prompt = ""
+	ti.CharLimit = 200
+
+	return CommandBar{
+		input:    ti,
+		complete: complete,
+	}
+}
+
+// Active reports whether the command bar is open and capturing keys
+func (b *CommandBar) Active() bool {
+	return b.active
+}
+
+// Open focuses the command bar with an empty line
+func (b *CommandBar) Open() tea.Cmd {
+	b.active = true
+	b.input.SetValue("")
+	b.resetCompletion()
+	b.historyIdx = len(b.history)
+	b.draft = ""
+	return b.input.Focus()
+}
+
+// Close hides the command bar without submitting
+func (b *CommandBar) Close() {
+	b.active = false
+	b.input.Blur()
+	b.resetCompletion()
+}
+
+func (b CommandBar) Update(msg tea.Msg) (CommandBar, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		var cmd tea.Cmd
+		b.input, cmd = b.input.Update(msg)
+		return b, cmd
+	}
+
+	switch keyMsg.String() {
+	case "esc", "ctrl+c":
+		b.Close()
+		return b, nil
+
+	case "enter":
+		line := strings.TrimSpace(b.input.Value())
+		b.Close()
+		if line == "" {
+			return b, nil
+		}
+		b.pushHistory(line)
+		return b, func() tea.Msg { return CommandMsg{Line: line} }
+
+	case "tab":
+		b.nextCompletion(1)
+		return b, nil
+
+	case "shift+tab":
+		b.nextCompletion(-1)
+		return b, nil
+
+	case "up":
+		if b.historyIdx > 0 {
+			if b.historyIdx == len(b.history) {
+				b.draft = b.input.Value()
+			}
+			b.historyIdx--
+			b.setValue(b.history[b.historyIdx])
+		}
+		return b, nil
+
+	case "down":
+		if b.historyIdx < len(b.history) {
+			b.historyIdx++
+			if b.historyIdx == len(b.history) {
+				b.setValue(b.draft)
+			} else {
+				b.setValue(b.history[b.historyIdx])
+			}
+		}
+		return b, nil
+	}
+
+	var cmd tea.Cmd
+	b.input, cmd = b.input.Update(msg)
+	b.resetCompletion()
+	return b, cmd
+}
+
+func (b *CommandBar) setValue(v string) {
+	b.input.SetValue(v)
+	b.input.CursorEnd()
+	b.resetCompletion()
+}
+
+func (b *CommandBar) pushHistory(line string) {
+	if n := len(b.history); n > 0 && b.history[n-1] == line {
+		return
+	}
+	b.history = append(b.history, line)
+	if len(b.history) > maxCommandHistory {
+		b.history = b.history[len(b.history)-maxCommandHistory:]
+	}
+}
+
+// nextCompletion cycles through the completions of the line as it was when
+// Tab was first pressed
+func (b *CommandBar) nextCompletion(step int) {
+	if b.completions == nil {
+		if b.complete == nil {
+			return
+		}
+		b.completions = b.complete(b.input.Value())
+		if len(b.completions) == 0 {
+			return
+		}
+		b.completeIdx = -1
+		if step < 0 {
+			b.completeIdx = 0
+		}
+	}
+	if len(b.completions) == 0 {
+		return
+	}
+
+	b.completeIdx = (b.completeIdx + step + len(b.completions)) % len(b.completions)
+	b.input.SetValue(b.completions[b.completeIdx])
+	b.input.CursorEnd()
+}
+
+func (b *CommandBar) resetCompletion() {
+	b.completions = nil
+	b.completeIdx = 0
+}
+
+// View renders the command bar on a single line, followed by the available
+// completions when the line is ambiguous
+func (b CommandBar) View(width int) string {
+	line := styles.SearchPrompt.Render(":") + b.input.View()
+
+	hints := b.completions
+	if hints == nil && b.complete != nil {
+		hints = b.complete(b.input.Value())
+	}
+	if len(hints) > 1 || (len(hints) == 1 && hints[0] != b.input.Value()) {
+		const maxHints = 6
+		shown := hints
+		if len(shown) > maxHints {
+			shown = shown[:maxHints]
+		}
+		hint := "  " + strings.Join(shown, "  ")
+		if len(hints) > maxHints {
+			hint += "  …"
+		}
+		line += styles.HelpDesc.Render(hint)
+	}
+
+	return lipgloss.NewStyle().MaxWidth(width).Render(line)
+}
@@ -3,14 +3,15 @@ package components
 import (
 	"context"
 	"fmt"
-	"regexp"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/johnoct/a3s/internal/aws/iam"
 	"github.com/johnoct/a3s/internal/aws/identity"
+	"github.com/johnoct/a3s/internal/policy"
 	"github.com/johnoct/a3s/internal/ui/styles"
 )
 
@@ -18,7 +19,7 @@ import (
 type DetailModel struct {
 	// Core role data
 	role        *iam.Role
-	roleService *iam.RoleService
+	roleService iam.RoleAPI
 
 	// AWS context
 	profile  string
@@ -30,60 +31,124 @@ type DetailModel struct {
 	height int
 
 	// Navigation state
-	activeTab      int
-	tabs           []string
-	viewState      viewState
-	scrollY        int
-	selectedPolicy int
+	activeTab       int
+	tabs            []string
+	scrollY         int
+	selectedPolicy  int
+	selectedProfile int
 
 	// Policy document viewing
-	policyDocument string
-	policyName     string
-	loadingPolicy  bool
+	document      *DocumentViewer
+	loadingPolicy bool
+
+	// Policy documents shared by the analyses of the role
+	documents *documentCache
+
+	// Permissions boundary analysis, nil until loaded
+	boundary        *boundaryAnalysis
+	boundaryLoading bool
+
+	// Service last accessed report, nil until the Access Advisor tab is shown
+	access        *accessReport
+	accessLoading bool
+
+	// Effective permissions matrix, nil until the tab is shown
+	effective        *effectiveReport
+	effectiveLoading bool
+
+	// Lint findings, nil until the Findings tab is shown
+	findings        *roleFindings
+	findingsLoading bool
+
+	// "Can this role do X?" prompt
+	queryMode  bool
+	queryInput textinput.Model
+	queryErr   string
+	querying   bool
+
+	// Policy simulator prompt and the last simulation run
+	simulateMode   bool
+	simulateInput  textinput.Model
+	simulateErr    string
+	simulating     bool
+	lastSimulation *iam.Simulation
+}
 
-	// Search functionality
-	searchMode    bool
-	searchInput   textinput.Model
-	searchQuery   string
-	searchMatches []searchMatch
-	currentMatch  int
+// accessReport is the service last accessed report of the role
+type accessReport struct {
+	services []iam.ServiceAccess
+	err      error
 }
 
-type viewState int
+// effectiveReport is the role's policies merged into a permissions matrix
+type effectiveReport struct {
+	permissions []policy.Permission
+	// partial names the policies whose NotAction statements grant actions
+	// outside the bundled catalogue, which have no rows
+	partial []string
+	err     error
+}
 
-const (
-	viewNormal viewState = iota
-	viewPolicyDocument
-)
+// boundaryAnalysis lists the actions granted by the role's identity
+// policies that its permissions boundary cuts off
+type boundaryAnalysis struct {
+	cutOff []boundaryCutOff
+	// granted counts the action patterns found in the identity policies
+	granted int
+	// partial names the policies whose NotAction statements grant actions
+	// outside the bundled catalogue, which are not analysed
+	partial []string
+	err     error
+}
 
-type searchMatch struct {
-	line  int
-	start int
-	end   int
-	text  string
+// boundaryCutOff is one cut off action and the policy granting it
+type boundaryCutOff struct {
+	action string
+	policy string
 }
 
 // IsViewingPolicyDocument returns true if currently viewing a policy document
 func (m *DetailModel) IsViewingPolicyDocument() bool {
-	return m.viewState == viewPolicyDocument
+	return m.document != nil
 }
 
-// NewDetailModel creates a new DetailModel with the given role and configuration
-func NewDetailModel(role *iam.Role, profile, region string, roleService *iam.RoleService) *DetailModel {
-	searchInput := textinput.New()
-	searchInput.Placeholder = "Search..."
-	searchInput.CharLimit = 100
-	searchInput.Width = 50
+// CapturingInput returns true while the search, query or simulation prompt
+// is reading text
+func (m *DetailModel) CapturingInput() bool {
+	return m.queryMode || m.simulateMode || (m.document != nil && m.document.CapturingInput())
+}
 
+// NewDetailModel creates a new DetailModel with the given role and configuration
+func NewDetailModel(role *iam.Role, profile, region string, roleService iam.RoleAPI) *DetailModel {
 	return &DetailModel{
-		role:        role,
-		roleService: roleService,
-		profile:     profile,
-		region:      region,
-		tabs:        []string{"Overview", "Trust Policy", "Policies", "Tags"},
-		viewState:   viewNormal,
-		searchInput: searchInput,
+		role:          role,
+		roleService:   roleService,
+		profile:       profile,
+		region:        region,
+		queryInput:    newQueryInput(),
+		simulateInput: newSimulationInput(),
+		documents:     &documentCache{},
+		tabs:          []string{"Overview", "Trust Policy", "Policies", "Instance Profiles", "Boundary", "Access Advisor", "Effective Permissions", "Findings", "Tags"},
+	}
+}
+
+// SetRole replaces the displayed role with fresh data, keeping the active
+// tab, scroll position and policy selection where possible. The returned
+// command re-runs the boundary analysis.
+func (m *DetailModel) SetRole(role *iam.Role) tea.Cmd {
+	m.role = role
+	totalPolicies := len(role.ManagedPolicies) + len(role.InlinePolicies)
+	if m.selectedPolicy >= totalPolicies {
+		m.selectedPolicy = max(0, totalPolicies-1)
+	}
+	if m.selectedProfile >= len(role.InstanceProfiles) {
+		m.selectedProfile = max(0, len(role.InstanceProfiles)-1)
 	}
+	m.documents = &documentCache{}
+	m.boundary = nil
+	m.effective = nil
+	m.findings = nil
+	return tea.Batch(m.analyzeBoundary(), m.loadEffectivePermissions(), m.loadFindings())
 }
 
 // policyDocumentLoadedMsg represents the result of loading a policy document
@@ -93,8 +158,31 @@ type policyDocumentLoadedMsg struct {
 	err        error
 }
 
+// accessAdvisorLoadedMsg carries the service last accessed report of the
+// role with roleARN
+type accessAdvisorLoadedMsg struct {
+	roleARN string
+	report  *accessReport
+}
+
+// accessAdvisorTimeout bounds how long the report job is polled
+const accessAdvisorTimeout = 2 * time.Minute
+
+// effectivePermissionsMsg carries the effective permissions of the role
+// with roleARN
+type effectivePermissionsMsg struct {
+	roleARN string
+	report  *effectiveReport
+}
+
+// boundaryAnalyzedMsg carries the boundary analysis of the role with roleARN
+type boundaryAnalyzedMsg struct {
+	roleARN  string
+	analysis *boundaryAnalysis
+}
+
 func (m *DetailModel) Init() tea.Cmd {
-	return nil
+	return m.analyzeBoundary()
 }
 
 // ============================================================================
@@ -107,152 +195,197 @@ func (m *DetailModel) SetIdentity(id *identity.Identity) {
 }
 
 func (m *DetailModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	var cmd tea.Cmd
-
-	// Update search input if in search mode
-	if m.searchMode {
-		m.searchInput, cmd = m.searchInput.Update(msg)
-
-		// If search input changed, update search results
-		if m.searchInput.Value() != m.searchQuery {
-			newQuery := strings.TrimSpace(m.searchInput.Value())
-			if len(newQuery) > 100 { // Prevent extremely long searches
-				newQuery = newQuery[:100]
-				m.searchInput.SetValue(newQuery)
-			}
-			m.searchQuery = newQuery
-			m.updateSearchResults()
-		}
-	}
-
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		// Update search input width based on screen width
-		m.searchInput.Width = max(20, m.width-20)
-		return m, cmd
+		if m.document != nil {
+			m.document.SetSize(m.width, m.height)
+		}
+		return m, nil
 
 	case policyDocumentLoadedMsg:
 		m.loadingPolicy = false
-		if msg.err != nil {
-			m.policyDocument = fmt.Sprintf("Error loading policy: %v", msg.err)
-			m.policyName = "Error"
-		} else {
-			m.policyDocument = msg.document
-			m.policyName = msg.policyName
-		}
-		m.viewState = viewPolicyDocument
-		m.scrollY = 0
-		// Clear any existing search state when loading new document
-		m.clearSearch()
-		return m, cmd
+		m.openDocument(msg.policyName, msg.document, msg.err)
+		return m, nil
 
-	case tea.KeyMsg:
-		switch m.viewState {
-		case viewPolicyDocument:
-			return m.updatePolicyDocumentView(msg)
-		case viewNormal:
-			return m.updateNormalView(msg)
+	case accessAdvisorLoadedMsg:
+		if msg.roleARN == m.role.ARN {
+			m.accessLoading = false
+			m.access = msg.report
 		}
-	}
-
-	return m, cmd
-}
+		return m, nil
 
-func (m *DetailModel) updatePolicyDocumentView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	if m.searchMode {
-		switch msg.String() {
-		case "esc":
-			m.exitSearchMode()
-			return m, nil
-		case "enter":
-			m.exitSearchMode()
-			return m, nil
+	case findingsLoadedMsg:
+		if msg.roleARN == m.role.ARN {
+			m.findingsLoading = false
+			m.findings = msg.result
 		}
-		// Let the search input handle other keys
 		return m, nil
-	}
 
-	switch msg.String() {
-	case "esc":
-		m.viewState = viewNormal
-		m.scrollY = 0
-		m.clearSearch()
+	case effectivePermissionsMsg:
+		if msg.roleARN == m.role.ARN {
+			m.effectiveLoading = false
+			m.effective = msg.report
+		}
 		return m, nil
-	case "/":
-		m.enterSearchMode()
+
+	case boundaryAnalyzedMsg:
+		if msg.roleARN == m.role.ARN {
+			m.boundaryLoading = false
+			m.boundary = msg.analysis
+		}
 		return m, nil
-	case "n":
-		if len(m.searchMatches) > 0 {
-			m.nextMatch()
+
+	case roleQueryAnsweredMsg:
+		m.querying = false
+		if msg.roleARN == m.role.ARN {
+			m.document = NewDocumentViewer(msg.title, msg.answer, "back to role")
+			m.document.SetContext(m.profile, m.region, m.identity)
+			m.document.SetSize(m.width, m.height)
 		}
 		return m, nil
-	case "N":
-		if len(m.searchMatches) > 0 {
-			m.prevMatch()
+
+	case simulationDoneMsg:
+		m.simulating = false
+		if msg.roleARN == m.role.ARN {
+			m.lastSimulation = &msg.simulation
+			m.document = NewDocumentViewer(msg.title, msg.report, "back to role")
+			m.document.SetContext(m.profile, m.region, m.identity)
+			m.document.SetSize(m.width, m.height)
 		}
 		return m, nil
-	case "j", "down":
-		m.scrollY++
-	case "k", "up":
-		if m.scrollY > 0 {
-			m.scrollY--
+	}
+
+	if m.document != nil {
+		closed, cmd := m.document.Update(msg)
+		if closed {
+			m.document = nil
 		}
-	case "g":
-		m.scrollY = 0
-	case "G":
-		// Scroll to bottom
-		lines := strings.Split(m.policyDocument, "\n")
-		visibleHeight := m.calculateVisibleHeight()
-		m.scrollY = max(0, len(lines)-visibleHeight)
+		return m, cmd
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		if m.queryMode {
+			return m, m.updateQuery(msg)
+		}
+		if m.simulateMode {
+			return m, m.updateSimulation(msg)
+		}
+		return m.updateNormalView(msg)
+	}
+	if m.queryMode {
+		var cmd tea.Cmd
+		m.queryInput, cmd = m.queryInput.Update(msg)
+		return m, cmd
+	}
+	if m.simulateMode {
+		var cmd tea.Cmd
+		m.simulateInput, cmd = m.simulateInput.Update(msg)
+		return m, cmd
 	}
 	return m, nil
 }
 
+// openDocument shows a loaded policy document, or the error that prevented
+// loading it, in the document viewer
+func (m *DetailModel) openDocument(name, document string, err error) {
+	if err != nil {
+		document = fmt.Sprintf("Error loading policy: %v", err)
+		name = "Error"
+	}
+	m.document = NewDocumentViewer(fmt.Sprintf("📄 Policy Document: %s", name), document, "back to policies")
+	m.document.SetContext(m.profile, m.region, m.identity)
+	m.document.SetSize(m.width, m.height)
+}
+
 func (m *DetailModel) updateNormalView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "tab", "l":
 		m.activeTab = (m.activeTab + 1) % len(m.tabs)
 		m.scrollY = 0
 		m.selectedPolicy = 0
+		m.selectedProfile = 0
+		return m, tea.Batch(m.loadAccessAdvisor(), m.loadEffectivePermissions(), m.loadFindings())
 	case "shift+tab", "h":
 		m.activeTab = (m.activeTab - 1 + len(m.tabs)) % len(m.tabs)
 		m.scrollY = 0
 		m.selectedPolicy = 0
+		m.selectedProfile = 0
+		return m, tea.Batch(m.loadAccessAdvisor(), m.loadEffectivePermissions(), m.loadFindings())
 	case "j", "down":
 		if m.activeTab == 2 { // Policies tab
 			totalPolicies := len(m.role.ManagedPolicies) + len(m.role.InlinePolicies)
 			if totalPolicies > 0 {
 				m.selectedPolicy = min(m.selectedPolicy+1, totalPolicies-1)
 			}
+		} else if m.activeTab == 3 { // Instance Profiles tab
+			if len(m.role.InstanceProfiles) > 0 {
+				m.selectedProfile = min(m.selectedProfile+1, len(m.role.InstanceProfiles)-1)
+			}
 		} else {
 			m.scrollY++
 		}
 	case "k", "up":
 		if m.activeTab == 2 { // Policies tab
 			m.selectedPolicy = max(0, m.selectedPolicy-1)
+		} else if m.activeTab == 3 { // Instance Profiles tab
+			m.selectedProfile = max(0, m.selectedProfile-1)
 		} else if m.scrollY > 0 {
 			m.scrollY--
 		}
 	case "g":
 		m.scrollY = 0
 		m.selectedPolicy = 0
+		m.selectedProfile = 0
 	case "G":
 		if m.activeTab == 2 { // Policies tab
 			totalPolicies := len(m.role.ManagedPolicies) + len(m.role.InlinePolicies)
 			if totalPolicies > 0 {
 				m.selectedPolicy = totalPolicies - 1
 			}
+		} else if m.activeTab == 3 && len(m.role.InstanceProfiles) > 0 { // Instance Profiles tab
+			m.selectedProfile = len(m.role.InstanceProfiles) - 1
 		}
 	case "enter":
 		if m.activeTab == 2 { // Policies tab
 			return m, m.loadSelectedPolicy()
 		}
+		if m.activeTab == 4 { // Boundary tab
+			return m, m.loadBoundaryPolicy()
+		}
+		if m.activeTab == 3 && len(m.role.InstanceProfiles) > 0 { // Instance Profiles tab
+			profile := m.role.InstanceProfiles[m.selectedProfile]
+			return m, func() tea.Msg {
+				return OpenResourceMsg{Resource: "instanceprofiles", ID: profile.Name}
+			}
+		}
+	case "u":
+		if m.activeTab == 2 { // Policies tab
+			return m, m.showPolicyUsage()
+		}
+	case "b":
+		return m, m.loadBoundaryPolicy()
+	case "c":
+		return m, m.startQuery()
+	case "s":
+		return m, m.startSimulation()
 	}
 	return m, nil
 }
 
+// showPolicyUsage jumps to the attachments of the selected managed policy,
+// listing every role, user and group that uses it
+func (m *DetailModel) showPolicyUsage() tea.Cmd {
+	if m.selectedPolicy >= len(m.role.ManagedPolicies) {
+		return nil
+	}
+	policy := m.role.ManagedPolicies[m.selectedPolicy]
+	return func() tea.Msg {
+		return OpenResourceMsg{Resource: "policies", ID: policy.ARN, Tab: "Attachments"}
+	}
+}
+
 // ============================================================================
 // Async Operations
 // ============================================================================
@@ -288,143 +421,106 @@ func (m *DetailModel) loadSelectedPolicy() tea.Cmd {
 	return nil
 }
 
-// ============================================================================
-// Search Functionality
-// ============================================================================
-
-func (m *DetailModel) enterSearchMode() {
-	m.searchMode = true
-	m.searchInput.Focus()
-	m.searchInput.SetValue("")
-	m.searchQuery = ""
-	m.searchMatches = nil
-	m.currentMatch = -1
-}
-
-func (m *DetailModel) exitSearchMode() {
-	m.searchMode = false
-	m.searchInput.Blur()
-}
-
-func (m *DetailModel) clearSearch() {
-	m.searchMode = false
-	m.searchInput.Blur()
-	m.searchInput.SetValue("")
-	m.searchQuery = ""
-	m.searchMatches = nil
-	m.currentMatch = -1
-}
-
-func (m *DetailModel) updateSearchResults() {
-	if m.searchQuery == "" {
-		m.searchMatches = nil
-		m.currentMatch = -1
-		return
-	}
-
-	// Limit search query length to prevent performance issues
-	if len(m.searchQuery) > 100 {
-		m.searchMatches = nil
-		m.currentMatch = -1
-		return
-	}
-
-	m.searchMatches = nil
-	m.currentMatch = -1
-
-	// Create case-insensitive regex with error handling
-	pattern, err := regexp.Compile("(?i)" + regexp.QuoteMeta(m.searchQuery))
-	if err != nil {
-		// On regex error, clear matches but don't crash
-		m.searchMatches = nil
-		m.currentMatch = -1
-		return
-	}
-
-	lines := strings.Split(m.policyDocument, "\n")
-	for lineNum, line := range lines {
-		matches := pattern.FindAllStringIndex(line, -1)
-		for _, match := range matches {
-			m.searchMatches = append(m.searchMatches, searchMatch{
-				line:  lineNum,
-				start: match[0],
-				end:   match[1],
-				text:  line[match[0]:match[1]],
-			})
-		}
+// loadBoundaryPolicy opens the permissions boundary document in the viewer
+func (m *DetailModel) loadBoundaryPolicy() tea.Cmd {
+	boundary := m.role.PermissionsBoundary
+	if m.loadingPolicy || boundary == nil {
+		return nil
 	}
-
-	// If we have matches, set current to first match and scroll to it
-	if len(m.searchMatches) > 0 {
-		m.currentMatch = 0
-		m.scrollToMatch()
+	m.loadingPolicy = true
+	return func() tea.Msg {
+		doc, err := m.roleService.GetManagedPolicyDocument(context.Background(), boundary.ARN)
+		return policyDocumentLoadedMsg{document: doc, policyName: boundary.Name + " (permissions boundary)", err: err}
 	}
 }
 
-func (m *DetailModel) nextMatch() {
-	if len(m.searchMatches) == 0 {
-		return
-	}
-	if m.currentMatch < 0 {
-		m.currentMatch = 0
-	} else {
-		m.currentMatch = (m.currentMatch + 1) % len(m.searchMatches)
+// loadAccessAdvisor generates the service last accessed report when the
+// Access Advisor tab is shown for the first time, or again after a failure
+func (m *DetailModel) loadAccessAdvisor() tea.Cmd {
+	if m.activeTab != 5 || m.accessLoading || m.roleService == nil {
+		return nil
 	}
-	m.scrollToMatch()
-}
-
-func (m *DetailModel) prevMatch() {
-	if len(m.searchMatches) == 0 {
-		return
+	if m.access != nil && m.access.err == nil {
+		return nil
 	}
-	if m.currentMatch < 0 {
-		m.currentMatch = len(m.searchMatches) - 1
-	} else {
-		m.currentMatch = (m.currentMatch - 1 + len(m.searchMatches)) % len(m.searchMatches)
+	m.accessLoading = true
+	roleARN := m.role.ARN
+	roleService := m.roleService
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), accessAdvisorTimeout)
+		defer cancel()
+		services, err := roleService.GetServiceLastAccessed(ctx, roleARN)
+		return accessAdvisorLoadedMsg{roleARN: roleARN, report: &accessReport{services: services, err: err}}
 	}
-	m.scrollToMatch()
 }
 
-func (m *DetailModel) scrollToMatch() {
-	if m.currentMatch < 0 || m.currentMatch >= len(m.searchMatches) {
-		return
-	}
-
-	matchLine := m.searchMatches[m.currentMatch].line
-	visibleHeight := m.calculateVisibleHeight()
-
-	// Center the match in the view
-	targetScroll := matchLine - visibleHeight/2
-	if targetScroll < 0 {
-		targetScroll = 0
+// loadEffectivePermissions fetches and merges the role's policies when the
+// Effective Permissions tab is shown for the first time, or again after a
+// failure
+func (m *DetailModel) loadEffectivePermissions() tea.Cmd {
+	if m.activeTab != 6 || m.effectiveLoading || m.roleService == nil {
+		return nil
 	}
-
-	totalLines := len(strings.Split(m.policyDocument, "\n"))
-	maxScroll := totalLines - visibleHeight
-	if maxScroll < 0 {
-		maxScroll = 0
+	if m.effective != nil && m.effective.err == nil {
+		return nil
 	}
-	if targetScroll > maxScroll {
-		targetScroll = maxScroll
+	m.effectiveLoading = true
+	role := m.role
+	roleService := m.roleService
+	documents := m.documents
+	return func() tea.Msg {
+		report := &effectiveReport{}
+		policies, err := documents.rolePolicies(context.Background(), roleService, role)
+		if err != nil {
+			report.err = err
+		} else {
+			report.permissions = policy.EffectivePermissions(policies)
+			for _, p := range policies {
+				if _, complete := p.Document.GrantedActions(); !complete {
+					report.partial = append(report.partial, p.Name)
+				}
+			}
+		}
+		return effectivePermissionsMsg{roleARN: role.ARN, report: report}
 	}
-
-	m.scrollY = targetScroll
 }
 
-// calculateVisibleHeight calculates the available height for content display
-func (m *DetailModel) calculateVisibleHeight() int {
-	const (
-		minHeight     = 5
-		borderPadding = 2 // Border top and bottom
-		headerHeight  = 6 // ASCII art lines
-		baseUIHeight  = 8 // title(2) + spacing(2) + tab(2) + status(1) + help(1)
-	)
+// analyzeBoundary fetches the boundary and every identity policy of the role
+// and works out which granted actions the boundary cuts off
+func (m *DetailModel) analyzeBoundary() tea.Cmd {
+	if m.role.PermissionsBoundary == nil || m.roleService == nil {
+		return nil
+	}
+	m.boundaryLoading = true
+	role := m.role
+	roleService := m.roleService
+	documents := m.documents
+	return func() tea.Msg {
+		ctx := context.Background()
+		analysis := &boundaryAnalysis{}
+		boundary, err := documents.boundaryPolicy(ctx, roleService, role)
+		if err != nil {
+			analysis.err = err
+			return boundaryAnalyzedMsg{roleARN: role.ARN, analysis: analysis}
+		}
+		policies, err := documents.rolePolicies(ctx, roleService, role)
+		if err != nil {
+			analysis.err = err
+			return boundaryAnalyzedMsg{roleARN: role.ARN, analysis: analysis}
+		}
 
-	calculatedHeight := m.height - headerHeight - baseUIHeight - borderPadding
-	if calculatedHeight < minHeight {
-		return minHeight
+		for _, p := range policies {
+			actions, complete := p.Document.GrantedActions()
+			if !complete {
+				analysis.partial = append(analysis.partial, p.Name)
+			}
+			analysis.granted += len(actions)
+			for _, action := range policy.CutOffActions(actions, boundary.Document) {
+				analysis.cutOff = append(analysis.cutOff, boundaryCutOff{action: action, policy: p.Name})
+			}
+		}
+		return boundaryAnalyzedMsg{roleARN: role.ARN, analysis: analysis}
 	}
-	return calculatedHeight
 }
 
 // ============================================================================
@@ -450,206 +546,10 @@ func min(a, b int) int {
 // ============================================================================
 
 func (m *DetailModel) View() string {
-	switch m.viewState {
-	case viewPolicyDocument:
-		return m.renderPolicyDocumentView()
-	default:
-		return m.renderNormalView()
-	}
-}
-
-func (m *DetailModel) renderPolicyDocumentView() string {
-	var content strings.Builder
-	var fullView strings.Builder
-
-	// Header with logo and AWS identity (like list view) - no top margin needed
-	fullView.WriteString(styles.RenderHeader(m.profile, m.region, m.identity, m.width))
-	fullView.WriteString("\n")
-
-	// Title for policy document view - with left padding
-	title := fmt.Sprintf("📄 Policy Document: %s", m.policyName)
-	// Ensure title doesn't exceed terminal width
-	titleWidth := lipgloss.Width(title)
-	if titleWidth > m.width-4 {
-		// Truncate if too long
-		maxLen := m.width - 10 // Leave space for emoji and ellipsis
-		if len(m.policyName) > maxLen {
-			title = fmt.Sprintf("📄 Policy Document: %s...", m.policyName[:maxLen])
-		}
-	}
-	fullView.WriteString("") // 3 spaces to align with header
-	fullView.WriteString(styles.TitleStyle.Render(title))
-	fullView.WriteString("\n\n") // Extra line to match the spacing of tabs in normal view
-
-	// Policy document content with scrolling and search highlighting
-	lines := strings.Split(m.policyDocument, "\n")
-	visibleHeight := m.calculateVisibleHeight()
-
-	// Always reserve space for search bar to prevent layout shifts
-	visibleHeight -= 2
-
-	endIdx := m.scrollY + visibleHeight
-	if endIdx > len(lines) {
-		endIdx = len(lines)
-	}
-
-	// Calculate available width for the policy document content
-	// GetMainContainer sets width to ((m.width-2) - 2), and CodeBlock adds padding(1) on each side
-	// So the available content width is: (m.width - 4) - 2 = m.width - 6
-	availableWidth := m.width - 6
-	if availableWidth < 80 {
-		availableWidth = 80
-	}
-
-	for i := m.scrollY; i < endIdx; i++ {
-		if i < len(lines) {
-			// Apply search highlighting to this line
-			highlightedLine := m.applySearchHighlighting(lines[i], i)
-			// Pad line to full width to ensure consistent background
-			lineWidth := lipgloss.Width(highlightedLine)
-			if lineWidth < availableWidth {
-				highlightedLine += strings.Repeat(" ", availableWidth-lineWidth)
-			}
-			content.WriteString(highlightedLine)
-			content.WriteString("\n")
-		}
-	}
-
-	// Fill empty space
-	for i := endIdx - m.scrollY; i < visibleHeight; i++ {
-		content.WriteString(strings.Repeat(" ", availableWidth))
-		content.WriteString("\n")
-	}
-
-	// Apply code block styling and border
-	styledContent := styles.CodeBlock.Render(strings.TrimRight(content.String(), "\n"))
-	// Height calculation: visibleHeight + CodeBlock padding(top:1, bottom:1) = visibleHeight + 2
-	borderedContent := styles.GetMainContainer(m.width, visibleHeight+2).Render(styledContent)
-	fullView.WriteString(borderedContent)
-	fullView.WriteString("\n")
-
-	// Search bar - always reserve space to prevent layout shifts
-	if m.searchMode {
-		searchBar := m.renderSearchBar()
-		fullView.WriteString(searchBar)
-	} else {
-		// Render invisible search bar to maintain layout consistency
-		searchBarHeight := strings.Repeat(" ", m.width-2) // Match container width
-		fullView.WriteString(searchBarHeight)
-	}
-	fullView.WriteString("\n")
-
-	// Help for policy document view
-	help := m.getPolicyDocumentHelp()
-	fullView.WriteString(styles.HelpStyle.Render(strings.Join(help, " | ")))
-
-	return fullView.String()
-}
-
-func (m *DetailModel) applySearchHighlighting(line string, lineNum int) string {
-	if m.searchQuery == "" || len(m.searchMatches) == 0 {
-		return line
-	}
-
-	// Find matches on this line
-	var lineMatches []searchMatch
-	for _, match := range m.searchMatches {
-		if match.line == lineNum {
-			lineMatches = append(lineMatches, match)
-		}
-	}
-
-	if len(lineMatches) == 0 {
-		return line
-	}
-
-	// Apply highlighting by building the line with styled segments
-	result := ""
-	lastEnd := 0
-
-	for _, match := range lineMatches {
-		// Add the text before this match
-		if match.start > lastEnd {
-			result += line[lastEnd:match.start]
-		}
-
-		// Add the highlighted match
-		matchText := line[match.start:match.end]
-		isCurrentMatch := false
-
-		// Check if this is the current match
-		if m.currentMatch >= 0 && m.currentMatch < len(m.searchMatches) {
-			currentMatch := m.searchMatches[m.currentMatch]
-			if currentMatch.line == lineNum && currentMatch.start == match.start {
-				isCurrentMatch = true
-			}
-		}
-
-		if isCurrentMatch {
-			result += styles.SearchCurrentMatch.Render(matchText)
-		} else {
-			result += styles.SearchMatch.Render(matchText)
-		}
-
-		lastEnd = match.end
-	}
-
-	// Add any remaining text after the last match
-	if lastEnd < len(line) {
-		result += line[lastEnd:]
+	if m.document != nil {
+		return m.document.View()
 	}
-
-	return result
-}
-
-func (m *DetailModel) renderSearchBar() string {
-	prompt := styles.SearchPrompt.Render("/")
-	input := styles.SearchInput.Render(m.searchInput.View())
-
-	searchInfo := ""
-	if len(m.searchMatches) > 0 {
-		searchInfo = styles.SearchInfo.Render(
-			fmt.Sprintf(" (%d/%d)", m.currentMatch+1, len(m.searchMatches)),
-		)
-	} else if m.searchQuery != "" {
-		searchInfo = styles.SearchInfo.Render(" (no matches)")
-	}
-
-	searchLine := prompt + input + searchInfo
-
-	// Center the search bar
-	padding := (m.width - lipgloss.Width(searchLine)) / 2
-	if padding < 0 {
-		padding = 0
-	}
-
-	return strings.Repeat(" ", padding) + searchLine
-}
-
-func (m *DetailModel) getPolicyDocumentHelp() []string {
-	if m.searchMode {
-		return []string{
-			styles.HelpKey.Render("Enter/Esc") + " " + styles.HelpDesc.Render("exit search"),
-		}
-	}
-
-	baseHelp := []string{
-		styles.HelpKey.Render("j/k") + " " + styles.HelpDesc.Render("scroll"),
-		styles.HelpKey.Render("g/G") + " " + styles.HelpDesc.Render("top/bottom"),
-		styles.HelpKey.Render("/") + " " + styles.HelpDesc.Render("search"),
-	}
-
-	if len(m.searchMatches) > 0 {
-		baseHelp = append(baseHelp,
-			styles.HelpKey.Render("n/N")+" "+styles.HelpDesc.Render("next/prev match"),
-		)
-	}
-
-	baseHelp = append(baseHelp,
-		styles.HelpKey.Render("Esc")+" "+styles.HelpDesc.Render("back to policies"),
-	)
-
-	return baseHelp
+	return m.renderNormalView()
 }
 
 func (m *DetailModel) renderNormalView() string {
@@ -688,13 +588,23 @@ func (m *DetailModel) renderNormalView() string {
 		tabContent = m.renderTrustPolicy()
 	case 2: // Policies
 		tabContent = m.renderPolicies()
-	case 3: // Tags
+	case 3: // Instance Profiles
+		tabContent = m.renderInstanceProfiles()
+	case 4: // Boundary
+		tabContent = m.renderBoundary()
+	case 5: // Access Advisor
+		tabContent = m.renderAccessAdvisor()
+	case 6: // Effective Permissions
+		tabContent = m.renderEffectivePermissions()
+	case 7: // Findings
+		tabContent = m.renderFindings()
+	case 8: // Tags
 		tabContent = m.renderTags()
 	}
 
 	// Apply scrolling
 	lines := strings.Split(tabContent, "\n")
-	visibleHeight := m.calculateVisibleHeight()
+	visibleHeight := calculateVisibleHeight(m.height)
 
 	endIdx := m.scrollY + visibleHeight
 	if endIdx > len(lines) {
@@ -729,7 +639,16 @@ func (m *DetailModel) renderNormalView() string {
 	fullView.WriteString(borderedContent)
 	fullView.WriteString("\n")
 
-	// Help (outside the border)
+	// Help (outside the border), replaced by the query or simulation prompt
+	// while it is open
+	if m.queryMode {
+		fullView.WriteString(m.renderQueryBar())
+		return fullView.String()
+	}
+	if m.simulateMode {
+		fullView.WriteString(m.renderSimulationBar())
+		return fullView.String()
+	}
 	help := m.getHelpText()
 	fullView.WriteString(styles.HelpStyle.Render(strings.Join(help, " | ")))
 
@@ -743,15 +662,34 @@ func (m *DetailModel) getHelpText() []string {
 				styles.HelpKey.Render("Tab/l") + " " + styles.HelpDesc.Render("next tab"),
 				styles.HelpKey.Render("j/k") + " " + styles.HelpDesc.Render("navigate"),
 				styles.HelpKey.Render("Enter") + " " + styles.HelpDesc.Render("view policy"),
+				styles.HelpKey.Render("u") + " " + styles.HelpDesc.Render("used by"),
 				styles.HelpKey.Render("Esc") + " " + styles.HelpDesc.Render("back"),
 			}
 		}
 	}
+	if m.activeTab == 4 && m.role.PermissionsBoundary != nil { // Boundary tab
+		return []string{
+			styles.HelpKey.Render("Tab/l") + " " + styles.HelpDesc.Render("next tab"),
+			styles.HelpKey.Render("j/k") + " " + styles.HelpDesc.Render("scroll"),
+			styles.HelpKey.Render("Enter") + " " + styles.HelpDesc.Render("view boundary"),
+			styles.HelpKey.Render("Esc") + " " + styles.HelpDesc.Render("back"),
+		}
+	}
+	if m.activeTab == 3 && len(m.role.InstanceProfiles) > 0 { // Instance Profiles tab
+		return []string{
+			styles.HelpKey.Render("Tab/l") + " " + styles.HelpDesc.Render("next tab"),
+			styles.HelpKey.Render("j/k") + " " + styles.HelpDesc.Render("navigate"),
+			styles.HelpKey.Render("Enter") + " " + styles.HelpDesc.Render("open profile"),
+			styles.HelpKey.Render("Esc") + " " + styles.HelpDesc.Render("back"),
+		}
+	}
 
 	return []string{
 		styles.HelpKey.Render("Tab/l") + " " + styles.HelpDesc.Render("next tab"),
 		styles.HelpKey.Render("Shift+Tab/h") + " " + styles.HelpDesc.Render("prev tab"),
 		styles.HelpKey.Render("j/k") + " " + styles.HelpDesc.Render("scroll"),
+		styles.HelpKey.Render("c") + " " + styles.HelpDesc.Render("can it do…"),
+		styles.HelpKey.Render("s") + " " + styles.HelpDesc.Render("simulate"),
 		styles.HelpKey.Render("Esc") + " " + styles.HelpDesc.Render("back"),
 	}
 }
@@ -775,12 +713,25 @@ func (m *DetailModel) renderOverview() string {
 	}
 
 	if m.role.LastUsed != nil {
+		lastUsed := m.role.LastUsed.Format("2006-01-02 15:04:05")
+		if m.role.LastUsedRegion != "" {
+			lastUsed += " in " + m.role.LastUsedRegion
+		}
 		fields = append(fields, struct {
 			label string
 			value string
-		}{"Last Used", m.role.LastUsed.Format("2006-01-02 15:04:05")})
+		}{"Last Used", lastUsed})
 	}
 
+	boundary := "None"
+	if m.role.PermissionsBoundary != nil {
+		boundary = m.role.PermissionsBoundary.Name + " (press b to view)"
+	}
+	fields = append(fields, struct {
+		label string
+		value string
+	}{"Boundary", boundary})
+
 	for _, field := range fields {
 		s.WriteString(styles.DetailLabel.Render(field.label + ":"))
 		s.WriteString(" ")
@@ -878,8 +829,255 @@ func (m *DetailModel) renderPolicies() string {
 	} else {
 		s.WriteString("\n")
 		s.WriteString(styles.HelpDesc.Render("Press Enter to view the selected policy document"))
+		if len(m.role.ManagedPolicies) > 0 {
+			s.WriteString("\n")
+			s.WriteString(styles.HelpDesc.Render("Press u to list every role, user and group using the selected managed policy"))
+		}
+	}
+
+	return s.String()
+}
+
+func (m *DetailModel) renderInstanceProfiles() string {
+	var s strings.Builder
+
+	s.WriteString(styles.DetailTitle.Render("Instance Profiles"))
+	s.WriteString("\n\n")
+
+	if len(m.role.InstanceProfiles) == 0 {
+		s.WriteString(styles.HelpDesc.Render("Not in any instance profile; no EC2 instance can use this role"))
+		return s.String()
+	}
+
+	for i, profile := range m.role.InstanceProfiles {
+		text := fmt.Sprintf("  • %s  (created %s)", profile.Name, profile.CreateDate.Format("2006-01-02"))
+		if i == m.selectedProfile {
+			s.WriteString(styles.SelectedItem.Render(text))
+		} else {
+			s.WriteString(styles.ListItem.Render(text))
+		}
+		s.WriteString("\n")
+		s.WriteString(styles.HelpDesc.Render("      " + profile.ARN))
+		s.WriteString("\n")
+	}
+
+	s.WriteString("\n")
+	s.WriteString(styles.HelpDesc.Render("Press Enter to open the selected instance profile"))
+
+	return s.String()
+}
+
+func (m *DetailModel) renderBoundary() string {
+	var s strings.Builder
+
+	s.WriteString(styles.DetailTitle.Render("Permissions Boundary"))
+	s.WriteString("\n\n")
+
+	boundary := m.role.PermissionsBoundary
+	if boundary == nil {
+		s.WriteString(styles.HelpDesc.Render("No permissions boundary; the role's policies alone decide what it can do"))
+		return s.String()
 	}
 
+	s.WriteString(styles.DetailLabel.Render("Policy:"))
+	s.WriteString(" ")
+	s.WriteString(styles.DetailValue.Render(boundary.Name))
+	s.WriteString("\n")
+	s.WriteString(styles.DetailLabel.Render("ARN:"))
+	s.WriteString(" ")
+	s.WriteString(styles.DetailValue.Render(boundary.ARN))
+	s.WriteString("\n\n")
+
+	switch {
+	case m.boundaryLoading || m.boundary == nil:
+		s.WriteString(styles.LoadingStyle.Render("Comparing policies with the boundary..."))
+		s.WriteString("\n")
+	case m.boundary.err != nil:
+		s.WriteString(styles.ErrorStyle.Render(fmt.Sprintf("Analysis failed: %v", m.boundary.err)))
+		s.WriteString("\n")
+	case m.boundary.granted == 0:
+		s.WriteString(styles.HelpDesc.Render("The role's policies grant no actions"))
+		s.WriteString("\n")
+	case len(m.boundary.cutOff) == 0:
+		s.WriteString(styles.HelpDesc.Render(fmt.Sprintf("The boundary allows all %d actions granted by the role's policies", m.boundary.granted)))
+		s.WriteString("\n")
+	default:
+		s.WriteString(styles.DetailLabel.Render("Cut Off:"))
+		s.WriteString(" ")
+		s.WriteString(styles.DetailValue.Render(fmt.Sprintf("%d of %d granted actions", len(m.boundary.cutOff), m.boundary.granted)))
+		s.WriteString("\n\n")
+		for _, c := range m.boundary.cutOff {
+			s.WriteString(styles.ListItem.Render(fmt.Sprintf("  • %-40s from %s", c.action, c.policy)))
+			s.WriteString("\n")
+		}
+		s.WriteString("\n")
+		s.WriteString(styles.HelpDesc.Render("Wildcard actions are listed when the boundary allows only part of them."))
+		s.WriteString("\n")
+		s.WriteString(styles.HelpDesc.Render("Resources and conditions are not compared."))
+		s.WriteString("\n")
+	}
+	if m.boundary != nil && len(m.boundary.partial) > 0 {
+		s.WriteString("\n")
+		s.WriteString(styles.DeltaModified.Render(fmt.Sprintf("Not analysed: NotAction in %s also grants services outside the bundled action catalogue", strings.Join(m.boundary.partial, ", "))))
+		s.WriteString("\n")
+	}
+
+	s.WriteString("\n")
+	s.WriteString(styles.HelpDesc.Render("Press Enter to view the boundary policy document"))
+
+	return s.String()
+}
+
+func (m *DetailModel) renderAccessAdvisor() string {
+	var s strings.Builder
+
+	s.WriteString(styles.DetailTitle.Render("Service Last Accessed"))
+	s.WriteString("\n\n")
+
+	switch {
+	case m.accessLoading || m.access == nil:
+		s.WriteString(styles.LoadingStyle.Render("Generating the service last accessed report, this can take a few seconds..."))
+		return s.String()
+	case m.access.err != nil:
+		s.WriteString(styles.ErrorStyle.Render(fmt.Sprintf("Report failed: %v", m.access.err)))
+		s.WriteString("\n\n")
+		s.WriteString(styles.HelpDesc.Render("Switch tabs and come back to retry"))
+		return s.String()
+	case len(m.access.services) == 0:
+		s.WriteString(styles.HelpDesc.Render("The role's policies grant access to no services"))
+		return s.String()
+	}
+
+	var used, unused []iam.ServiceAccess
+	for _, svc := range m.access.services {
+		if svc.Used() {
+			used = append(used, svc)
+		} else {
+			unused = append(unused, svc)
+		}
+	}
+
+	s.WriteString(styles.DetailLabel.Render("Granted:"))
+	s.WriteString(" ")
+	s.WriteString(styles.DetailValue.Render(fmt.Sprintf("%d services, %d used, %d never used", len(m.access.services), len(used), len(unused))))
+	s.WriteString("\n\n")
+
+	if len(used) > 0 {
+		s.WriteString(styles.DetailLabel.Render("Used:"))
+		s.WriteString("\n")
+		for _, svc := range used {
+			s.WriteString(styles.ListItem.Render(fmt.Sprintf("  • %-50s %s  %s",
+				truncate(serviceLabel(svc), 50), svc.LastAccessed.Format("2006-01-02 15:04"), svc.LastAccessedRegion)))
+			s.WriteString("\n")
+		}
+		s.WriteString("\n")
+	}
+
+	if len(unused) > 0 {
+		s.WriteString(styles.DetailLabel.Render("Never Used:"))
+		s.WriteString("\n")
+		for _, svc := range unused {
+			s.WriteString(styles.ListItem.Render("  • " + serviceLabel(svc)))
+			s.WriteString("\n")
+		}
+		s.WriteString("\n")
+	}
+
+	s.WriteString(styles.HelpDesc.Render("IAM tracks access for up to 400 days; never used services are candidates for removal"))
+
+	return s.String()
+}
+
+// serviceLabel names a service together with its namespace
+func serviceLabel(svc iam.ServiceAccess) string {
+	if svc.ServiceName == "" || svc.ServiceName == svc.Namespace {
+		return svc.Namespace
+	}
+	return fmt.Sprintf("%s (%s)", svc.ServiceName, svc.Namespace)
+}
+
+func (m *DetailModel) renderEffectivePermissions() string {
+	var s strings.Builder
+
+	s.WriteString(styles.DetailTitle.Render("Effective Permissions"))
+	s.WriteString("\n\n")
+
+	switch {
+	case m.effectiveLoading || m.effective == nil:
+		s.WriteString(styles.LoadingStyle.Render("Merging the role's policies..."))
+		return s.String()
+	case m.effective.err != nil:
+		s.WriteString(styles.ErrorStyle.Render(fmt.Sprintf("Failed to load policies: %v", m.effective.err)))
+		s.WriteString("\n\n")
+		s.WriteString(styles.HelpDesc.Render("Switch tabs and come back to retry"))
+		return s.String()
+	case len(m.effective.permissions) == 0:
+		s.WriteString(styles.HelpDesc.Render("The role's policies grant no permissions"))
+		return s.String()
+	}
+
+	counts := make(map[policy.Access]int)
+	services := 0
+	actionWidth, resourceWidth := 0, 0
+	for i, p := range m.effective.permissions {
+		counts[p.Access]++
+		if i == 0 || p.Service != m.effective.permissions[i-1].Service {
+			services++
+		}
+		actionWidth = max(actionWidth, len(p.Action)-len(p.Service)-1)
+		resourceWidth = max(resourceWidth, len(p.Resource))
+	}
+	actionWidth = min(actionWidth, 40)
+	resourceWidth = min(resourceWidth, 64)
+	s.WriteString(styles.DetailLabel.Render("Summary:"))
+	s.WriteString(" ")
+	s.WriteString(styles.DetailValue.Render(fmt.Sprintf("%d services, %d allowed, %d denied, %d conditional",
+		services, counts[policy.AccessAllowed], counts[policy.AccessDenied], counts[policy.AccessConditional])))
+	s.WriteString("\n")
+
+	for i, p := range m.effective.permissions {
+		if i == 0 || p.Service != m.effective.permissions[i-1].Service {
+			name := p.Service
+			if svc, ok := policy.LookupService(p.Service); ok {
+				name = fmt.Sprintf("%s (%s)", svc.Name, svc.Prefix)
+			}
+			s.WriteString("\n")
+			s.WriteString(styles.HelpKey.Render(name))
+			s.WriteString("\n")
+		}
+
+		action := p.Action
+		if _, after, found := strings.Cut(action, ":"); found {
+			action = after
+		}
+		source := strings.Join(p.Policies, ", ")
+		if len(p.DeniedBy) > 0 {
+			source += "; denied by " + strings.Join(p.DeniedBy, ", ")
+		}
+		line := fmt.Sprintf("  %-11s %-*s  %-*s  %s", p.Access,
+			actionWidth, truncate(action, actionWidth), resourceWidth, truncate(p.Resource, resourceWidth), source)
+		switch p.Access {
+		case policy.AccessDenied:
+			s.WriteString(styles.DeltaDeleted.Render(line))
+		case policy.AccessConditional:
+			s.WriteString(styles.DeltaModified.Render(line))
+		default:
+			s.WriteString(styles.ListItem.Render(line))
+		}
+		s.WriteString("\n")
+	}
+
+	if len(m.effective.partial) > 0 {
+		s.WriteString("\n")
+		s.WriteString(styles.DeltaModified.Render(fmt.Sprintf("Not analysed: NotAction in %s also grants services outside the bundled action catalogue", strings.Join(m.effective.partial, ", "))))
+		s.WriteString("\n")
+	}
+
+	s.WriteString("\n")
+	s.WriteString(styles.HelpDesc.Render("Wildcards are expanded against a bundled action catalogue; patterns spanning every service, or services it does not know, are also shown as written."))
+	s.WriteString("\n")
+	s.WriteString(styles.HelpDesc.Render("Conditional rows depend on request context; the permissions boundary is not applied here (see the Boundary tab)."))
+
 	return s.String()
 }
 
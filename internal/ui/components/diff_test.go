@@ -0,0 +1,26 @@
+package components
+
+import (
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		want     string
+	}{
+		{"identical", "a\nb", "a\nb", "  a\n  b"},
+		{"added", "a\nc", "a\nb\nc", "  a\n+ b\n  c"},
+		{"removed", "a\nb\nc", "a\nc", "  a\n- b\n  c"},
+		{"changed", "a\nb\nc", "a\nx\nc", "  a\n- b\n+ x\n  c"},
+		{"from empty", "", "a", "- \n+ a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffLines(tt.from, tt.to); got != tt.want {
+				t.Errorf("diffLines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,402 @@
+package components
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/johnoct/a3s/internal/aws/identity"
+	"github.com/johnoct/a3s/internal/ui/styles"
+)
+
+// DocumentViewer shows a JSON document (usually a policy) full screen with
+// scrolling and incremental search. It is embedded by detail views.
+type DocumentViewer struct {
+	title    string
+	document string
+	// backLabel describes where Esc returns to, e.g. "back to policies"
+	backLabel string
+
+	// AWS context
+	profile  string
+	region   string
+	identity *identity.Identity
+
+	width   int
+	height  int
+	scrollY int
+
+	// Search functionality
+	searchMode    bool
+	searchInput   textinput.Model
+	searchQuery   string
+	searchMatches []searchMatch
+	currentMatch  int
+}
+
+type searchMatch struct {
+	line  int
+	start int
+	end   int
+	text  string
+}
+
+// NewDocumentViewer creates a viewer for document under the given title
+func NewDocumentViewer(title, document, backLabel string) *DocumentViewer {
+	searchInput := textinput.New()
+	searchInput.Placeholder = "Search..."
+	searchInput.CharLimit = 100
+	searchInput.Width = 50
+
+	return &DocumentViewer{
+		title:        title,
+		document:     document,
+		backLabel:    backLabel,
+		searchInput:  searchInput,
+		currentMatch: -1,
+		width:        80,
+		height:       24,
+	}
+}
+
+// SetContext sets the header information shown above the document
+func (v *DocumentViewer) SetContext(profile, region string, id *identity.Identity) {
+	v.profile = profile
+	v.region = region
+	v.identity = id
+}
+
+// SetSize sets the terminal dimensions
+func (v *DocumentViewer) SetSize(width, height int) {
+	v.width = width
+	v.height = height
+	// Update search input width based on screen width
+	v.searchInput.Width = max(20, width-20)
+}
+
+// CapturingInput returns true while the search prompt is reading text
+func (v *DocumentViewer) CapturingInput() bool {
+	return v.searchMode
+}
+
+// Update handles a message. It reports closed when the user leaves the
+// document with Esc.
+func (v *DocumentViewer) Update(msg tea.Msg) (closed bool, cmd tea.Cmd) {
+	key, isKey := msg.(tea.KeyMsg)
+	if v.searchMode {
+		if isKey && (key.String() == "esc" || key.String() == "enter") {
+			v.exitSearchMode()
+			return false, nil
+		}
+
+		v.searchInput, cmd = v.searchInput.Update(msg)
+		// If search input changed, update search results
+		if v.searchInput.Value() != v.searchQuery {
+			newQuery := strings.TrimSpace(v.searchInput.Value())
+			if len(newQuery) > 100 { // Prevent extremely long searches
+				newQuery = newQuery[:100]
+				v.searchInput.SetValue(newQuery)
+			}
+			v.searchQuery = newQuery
+			v.updateSearchResults()
+		}
+		return false, cmd
+	}
+
+	if !isKey {
+		return false, nil
+	}
+	switch key.String() {
+	case "esc":
+		return true, nil
+	case "/":
+		v.enterSearchMode()
+		return false, textinput.Blink
+	case "n":
+		if len(v.searchMatches) > 0 {
+			v.nextMatch()
+		}
+	case "N":
+		if len(v.searchMatches) > 0 {
+			v.prevMatch()
+		}
+	case "j", "down":
+		v.scrollY++
+	case "k", "up":
+		if v.scrollY > 0 {
+			v.scrollY--
+		}
+	case "g":
+		v.scrollY = 0
+	case "G":
+		// Scroll to bottom
+		lines := strings.Split(v.document, "\n")
+		v.scrollY = max(0, len(lines)-v.visibleHeight())
+	}
+	return false, nil
+}
+
+// ============================================================================
+// Search Functionality
+// ============================================================================
+
+func (v *DocumentViewer) enterSearchMode() {
+	v.searchMode = true
+	v.searchInput.Focus()
+	v.searchInput.SetValue("")
+	v.searchQuery = ""
+	v.searchMatches = nil
+	v.currentMatch = -1
+}
+
+func (v *DocumentViewer) exitSearchMode() {
+	v.searchMode = false
+	v.searchInput.Blur()
+}
+
+func (v *DocumentViewer) updateSearchResults() {
+	v.searchMatches = nil
+	v.currentMatch = -1
+
+	// Limit search query length to prevent performance issues
+	if v.searchQuery == "" || len(v.searchQuery) > 100 {
+		return
+	}
+
+	// Create case-insensitive regex with error handling
+	pattern, err := regexp.Compile("(?i)" + regexp.QuoteMeta(v.searchQuery))
+	if err != nil {
+		// On regex error, clear matches but don't crash
+		return
+	}
+
+	lines := strings.Split(v.document, "\n")
+	for lineNum, line := range lines {
+		matches := pattern.FindAllStringIndex(line, -1)
+		for _, match := range matches {
+			v.searchMatches = append(v.searchMatches, searchMatch{
+				line:  lineNum,
+				start: match[0],
+				end:   match[1],
+				text:  line[match[0]:match[1]],
+			})
+		}
+	}
+
+	// If we have matches, set current to first match and scroll to it
+	if len(v.searchMatches) > 0 {
+		v.currentMatch = 0
+		v.scrollToMatch()
+	}
+}
+
+func (v *DocumentViewer) nextMatch() {
+	if v.currentMatch < 0 {
+		v.currentMatch = 0
+	} else {
+		v.currentMatch = (v.currentMatch + 1) % len(v.searchMatches)
+	}
+	v.scrollToMatch()
+}
+
+func (v *DocumentViewer) prevMatch() {
+	if v.currentMatch < 0 {
+		v.currentMatch = len(v.searchMatches) - 1
+	} else {
+		v.currentMatch = (v.currentMatch - 1 + len(v.searchMatches)) % len(v.searchMatches)
+	}
+	v.scrollToMatch()
+}
+
+func (v *DocumentViewer) scrollToMatch() {
+	if v.currentMatch < 0 || v.currentMatch >= len(v.searchMatches) {
+		return
+	}
+
+	matchLine := v.searchMatches[v.currentMatch].line
+	visibleHeight := v.visibleHeight()
+
+	// Center the match in the view
+	targetScroll := max(0, matchLine-visibleHeight/2)
+	totalLines := len(strings.Split(v.document, "\n"))
+	maxScroll := max(0, totalLines-visibleHeight)
+	v.scrollY = min(targetScroll, maxScroll)
+}
+
+// visibleHeight is the number of document lines that fit on screen
+func (v *DocumentViewer) visibleHeight() int {
+	// Always reserve space for search bar to prevent layout shifts
+	return calculateVisibleHeight(v.height) - 2
+}
+
+// calculateVisibleHeight calculates the available height for content display
+// in a tabbed detail layout of the given terminal height
+func calculateVisibleHeight(height int) int {
+	const (
+		minHeight     = 5
+		borderPadding = 2 // Border top and bottom
+		headerHeight  = 6 // ASCII art lines
+		baseUIHeight  = 8 // title(2) + spacing(2) + tab(2) + status(1) + help(1)
+	)
+
+	calculatedHeight := height - headerHeight - baseUIHeight - borderPadding
+	if calculatedHeight < minHeight {
+		return minHeight
+	}
+	return calculatedHeight
+}
+
+// ============================================================================
+// View Rendering
+// ============================================================================
+
+func (v *DocumentViewer) View() string {
+	var content strings.Builder
+	var fullView strings.Builder
+
+	// Header with logo and AWS identity (like list view) - no top margin needed
+	fullView.WriteString(styles.RenderHeader(v.profile, v.region, v.identity, v.width))
+	fullView.WriteString("\n")
+
+	// Ensure title doesn't exceed terminal width
+	title := v.title
+	if lipgloss.Width(title) > v.width-4 {
+		title = truncate(title, max(10, v.width-6))
+	}
+	fullView.WriteString(styles.TitleStyle.Render(title))
+	fullView.WriteString("\n\n") // Extra line to match the spacing of tabs in normal view
+
+	// Policy document content with scrolling and search highlighting
+	lines := strings.Split(v.document, "\n")
+	visibleHeight := v.visibleHeight()
+	endIdx := min(v.scrollY+visibleHeight, len(lines))
+
+	// Calculate available width for the policy document content
+	// GetMainContainer sets width to ((m.width-2) - 2), and CodeBlock adds padding(1) on each side
+	// So the available content width is: (m.width - 4) - 2 = m.width - 6
+	availableWidth := v.width - 6
+	if availableWidth < 80 {
+		availableWidth = 80
+	}
+
+	for i := v.scrollY; i < endIdx; i++ {
+		// Apply search highlighting to this line
+		highlightedLine := v.applySearchHighlighting(lines[i], i)
+		// Pad line to full width to ensure consistent background
+		if lineWidth := lipgloss.Width(highlightedLine); lineWidth < availableWidth {
+			highlightedLine += strings.Repeat(" ", availableWidth-lineWidth)
+		}
+		content.WriteString(highlightedLine)
+		content.WriteString("\n")
+	}
+
+	// Fill empty space
+	for i := max(0, endIdx-v.scrollY); i < visibleHeight; i++ {
+		content.WriteString(strings.Repeat(" ", availableWidth))
+		content.WriteString("\n")
+	}
+
+	// Apply code block styling and border
+	styledContent := styles.CodeBlock.Render(strings.TrimRight(content.String(), "\n"))
+	// Height calculation: visibleHeight + CodeBlock padding(top:1, bottom:1) = visibleHeight + 2
+	borderedContent := styles.GetMainContainer(v.width, visibleHeight+2).Render(styledContent)
+	fullView.WriteString(borderedContent)
+	fullView.WriteString("\n")
+
+	// Search bar - always reserve space to prevent layout shifts
+	if v.searchMode {
+		fullView.WriteString(v.renderSearchBar())
+	} else {
+		fullView.WriteString(strings.Repeat(" ", v.width-2)) // Match container width
+	}
+	fullView.WriteString("\n")
+
+	fullView.WriteString(styles.HelpStyle.Render(strings.Join(v.help(), " | ")))
+
+	return fullView.String()
+}
+
+func (v *DocumentViewer) applySearchHighlighting(line string, lineNum int) string {
+	if v.searchQuery == "" || len(v.searchMatches) == 0 {
+		return line
+	}
+
+	// Apply highlighting by building the line with styled segments
+	var result strings.Builder
+	lastEnd := 0
+
+	for i, match := range v.searchMatches {
+		if match.line != lineNum {
+			continue
+		}
+
+		// Add the text before this match
+		if match.start > lastEnd {
+			result.WriteString(line[lastEnd:match.start])
+		}
+
+		// Add the highlighted match
+		matchText := line[match.start:match.end]
+		if i == v.currentMatch {
+			result.WriteString(styles.SearchCurrentMatch.Render(matchText))
+		} else {
+			result.WriteString(styles.SearchMatch.Render(matchText))
+		}
+
+		lastEnd = match.end
+	}
+
+	// Add any remaining text after the last match
+	if lastEnd < len(line) {
+		result.WriteString(line[lastEnd:])
+	}
+
+	return result.String()
+}
+
+func (v *DocumentViewer) renderSearchBar() string {
+	prompt := styles.SearchPrompt.Render("/")
+	input := styles.SearchInput.Render(v.searchInput.View())
+
+	searchInfo := ""
+	if len(v.searchMatches) > 0 {
+		searchInfo = styles.SearchInfo.Render(
+			fmt.Sprintf(" (%d/%d)", v.currentMatch+1, len(v.searchMatches)),
+		)
+	} else if v.searchQuery != "" {
+		searchInfo = styles.SearchInfo.Render(" (no matches)")
+	}
+
+	searchLine := prompt + input + searchInfo
+
+	// Center the search bar
+	padding := max(0, (v.width-lipgloss.Width(searchLine))/2)
+	return strings.Repeat(" ", padding) + searchLine
+}
+
+func (v *DocumentViewer) help() []string {
+	if v.searchMode {
+		return []string{
+			styles.HelpKey.Render("Enter/Esc") + " " + styles.HelpDesc.Render("exit search"),
+		}
+	}
+
+	baseHelp := []string{
+		styles.HelpKey.Render("j/k") + " " + styles.HelpDesc.Render("scroll"),
+		styles.HelpKey.Render("g/G") + " " + styles.HelpDesc.Render("top/bottom"),
+		styles.HelpKey.Render("/") + " " + styles.HelpDesc.Render("search"),
+	}
+
+	if len(v.searchMatches) > 0 {
+		baseHelp = append(baseHelp,
+			styles.HelpKey.Render("n/N")+" "+styles.HelpDesc.Render("next/prev match"),
+		)
+	}
+
+	return append(baseHelp,
+		styles.HelpKey.Render("Esc")+" "+styles.HelpDesc.Render(v.backLabel),
+	)
+}
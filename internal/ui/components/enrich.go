@@ -0,0 +1,331 @@
+package components
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/johnoct/a3s/internal/aws/iam"
+)
+
+// enrichConcurrency bounds the number of GetRole and GetRoleDetails calls
+// in flight so large accounts do not trip IAM throttling
+const enrichConcurrency = 4
+
+// roleEnrichedMsg carries the details fetched for one role
+type roleEnrichedMsg struct {
+	arn  string
+	role *iam.Role
+}
+
+// roleLastUsedMsg carries the GetRole result for one role, nil on failure
+type roleLastUsedMsg struct {
+	arn  string
+	role *iam.Role
+}
+
+// lastUse is when and where a role was last used, as reported by GetRole.
+// ListRoles omits it. seq is the list's last use generation when it was
+// fetched; older entries are shown until they are fetched again.
+type lastUse struct {
+	date   *time.Time
+	region string
+	seq    int
+}
+
+// startEnrichment queues every role whose last use or, when a detail column
+// is selected, details are neither cached nor already pending, and with the
+// findings column the roles on screen that are not linted yet, and starts
+// fetching them. Linting fetches every policy of a role, so only the rows
+// shown are linted.
+func (m *ListModel) startEnrichment() tea.Cmd {
+	if m.roleService == nil {
+		return nil
+	}
+	if m.lastUsed == nil {
+		m.lastUsed = make(map[string]lastUse)
+		m.lastUsedPending = make(map[string]bool)
+	}
+	for _, role := range m.roles {
+		if m.lastUseCurrent(role.ARN) || m.lastUsedPending[role.ARN] || m.deltas[role.ARN] == deltaDeleted {
+			continue
+		}
+		m.lastUsedPending[role.ARN] = true
+		m.lastUsedQueue = append(m.lastUsedQueue, role)
+		m.rowChanged(role.ARN)
+	}
+
+	if m.needsDetails() {
+		if m.details == nil {
+			m.details = make(map[string]*iam.Role)
+			m.enrichPending = make(map[string]bool)
+		}
+		for _, role := range m.roles {
+			if _, ok := m.details[role.ARN]; ok || m.enrichPending[role.ARN] || m.deltas[role.ARN] == deltaDeleted {
+				continue
+			}
+			m.enrichPending[role.ARN] = true
+			m.enrichQueue = append(m.enrichQueue, role)
+		}
+	}
+
+	if m.needsFindings() {
+		if m.findings == nil {
+			m.findings = make(map[string]*roleFindings)
+			m.lintPending = make(map[string]bool)
+			m.lintDocuments = &documentCache{}
+		}
+		start, end := m.visibleRange()
+		for _, role := range m.filteredRoles[start:end] {
+			if _, ok := m.findings[role.ARN]; ok || m.lintPending[role.ARN] || m.deltas[role.ARN] == deltaDeleted {
+				continue
+			}
+			m.lintPending[role.ARN] = true
+			m.lintQueue = append(m.lintQueue, role)
+		}
+	}
+	return m.enrichNext()
+}
+
+// enrichNext starts fetches until the concurrency limit is reached. Details
+// go first since they include the last use, then the cheap last use
+// fetches, then lint jobs.
+func (m *ListModel) enrichNext() tea.Cmd {
+	var cmds []tea.Cmd
+	roleService := m.roleService
+	for m.enrichInFlight < enrichConcurrency {
+		if len(m.enrichQueue) > 0 {
+			role := m.enrichQueue[0]
+			m.enrichQueue = m.enrichQueue[1:]
+			m.enrichInFlight++
+			cmds = append(cmds, func() tea.Msg {
+				// A failed fetch is recorded as nil so the cell shows the
+				// failure instead of loading forever
+				details, _ := roleService.GetRoleDetails(context.Background(), role.Name)
+				return roleEnrichedMsg{arn: role.ARN, role: details}
+			})
+			continue
+		}
+		if len(m.lastUsedQueue) > 0 {
+			role := m.lastUsedQueue[0]
+			m.lastUsedQueue = m.lastUsedQueue[1:]
+			if m.lastUseCurrent(role.ARN) {
+				// Already known from a details fetch
+				delete(m.lastUsedPending, role.ARN)
+				m.rowChanged(role.ARN)
+				continue
+			}
+			m.enrichInFlight++
+			cmds = append(cmds, func() tea.Msg {
+				fetched, _ := roleService.GetRole(context.Background(), role.Name)
+				return roleLastUsedMsg{arn: role.ARN, role: fetched}
+			})
+			continue
+		}
+		if len(m.lintQueue) > 0 {
+			role := m.lintQueue[0]
+			m.lintQueue = m.lintQueue[1:]
+			if !m.onScreen(role.ARN) {
+				// Scrolled away; queued again when shown
+				delete(m.lintPending, role.ARN)
+				continue
+			}
+			m.enrichInFlight++
+			cmds = append(cmds, lintListedRole(roleService, role, m.lintSeq, m.details[role.ARN], m.lintDocuments))
+			continue
+		}
+		break
+	}
+	return tea.Batch(cmds...)
+}
+
+// lintVisible starts linting the roles on screen when the findings column
+// is selected
+func (m *ListModel) lintVisible() tea.Cmd {
+	if !m.needsFindings() {
+		return nil
+	}
+	return m.startEnrichment()
+}
+
+// onScreen reports whether a role is among the rows shown
+func (m *ListModel) onScreen(arn string) bool {
+	start, end := m.visibleRange()
+	for _, role := range m.filteredRoles[start:end] {
+		if role.ARN == arn {
+			return true
+		}
+	}
+	return false
+}
+
+// handleEnriched stores a fetched role and starts the next fetch
+func (m *ListModel) handleEnriched(msg roleEnrichedMsg) tea.Cmd {
+	m.enrichInFlight--
+	delete(m.enrichPending, msg.arn)
+	m.details[msg.arn] = msg.role
+	m.rowChanged(msg.arn)
+	if msg.role != nil {
+		m.recordLastUse(msg.role)
+	}
+	return m.enrichNext()
+}
+
+// handleLinted stores a role's findings, and the details fetched for them,
+// and starts the next fetch. A failed lint is stored too so the cell shows
+// the failure; a lint started before findings were invalidated is redone.
+func (m *ListModel) handleLinted(msg roleLintedMsg) tea.Cmd {
+	m.enrichInFlight--
+	if msg.seq != m.lintSeq {
+		m.lintQueue = append(m.lintQueue, msg.role)
+		return m.enrichNext()
+	}
+	delete(m.lintPending, msg.role.ARN)
+	m.findings[msg.role.ARN] = msg.result
+	m.rowChanged(msg.role.ARN)
+	if msg.details != nil {
+		if m.details == nil {
+			m.details = make(map[string]*iam.Role)
+			m.enrichPending = make(map[string]bool)
+		}
+		m.details[msg.role.ARN] = msg.details
+		m.recordLastUse(msg.details)
+	}
+	return m.enrichNext()
+}
+
+// invalidateFindings drops findings a refresh may have made stale so they
+// are linted again: after an explicit refresh every role's, together with
+// the cached details and policy documents, and after a watch cycle those
+// of added and modified roles, together with their details and inline
+// policies
+func (m *ListModel) invalidateFindings(explicit bool) {
+	if m.findings == nil {
+		return
+	}
+	if explicit {
+		m.lintSeq++
+		m.findings = make(map[string]*roleFindings)
+		m.lintDocuments = &documentCache{}
+		if m.details != nil {
+			m.details = make(map[string]*iam.Role)
+		}
+		m.rowsChanged()
+		return
+	}
+	for arn, d := range m.deltas {
+		if d == deltaAdded || d == deltaModified {
+			m.lintSeq++
+			delete(m.findings, arn)
+			delete(m.details, arn)
+			m.rowChanged(arn)
+			m.lintDocuments.forgetRole(arn[strings.LastIndex(arn, "/")+1:])
+		}
+	}
+}
+
+// handleLastUsed fills a role's last use into its list row and starts the
+// next fetch. A failed fetch leaves the row as ListRoles reported it.
+func (m *ListModel) handleLastUsed(msg roleLastUsedMsg) tea.Cmd {
+	m.enrichInFlight--
+	delete(m.lastUsedPending, msg.arn)
+	m.rowChanged(msg.arn)
+	if msg.role != nil {
+		m.recordLastUse(msg.role)
+	} else {
+		use := m.lastUsedOf(msg.arn)
+		use.seq = m.lastUsedSeq
+		m.lastUsed[msg.arn] = use
+	}
+	return m.enrichNext()
+}
+
+// recordLastUse caches a role's last use and updates its row, re-sorting
+// when the list is ordered by last use. In watch mode a last use that
+// differs from the one fetched before marks the role modified.
+func (m *ListModel) recordLastUse(role *iam.Role) {
+	if m.lastUsed == nil {
+		return
+	}
+	previous, known := m.lastUsed[role.ARN]
+	m.lastUsed[role.ARN] = lastUse{date: role.LastUsed, region: role.LastUsedRegion, seq: m.lastUsedSeq}
+	m.rowChanged(role.ARN)
+	m.reorder(func() {
+		for i := range m.roles {
+			if m.roles[i].ARN == role.ARN {
+				m.roles[i].LastUsed = role.LastUsed
+				m.roles[i].LastUsedRegion = role.LastUsedRegion
+			}
+		}
+	})
+
+	if m.snapshot == nil {
+		return
+	}
+	if prev, ok := m.snapshot[role.ARN]; ok {
+		prev.LastUsed, prev.LastUsedRegion = role.LastUsed, role.LastUsedRegion
+		m.snapshot[role.ARN] = prev
+	}
+	if known && !sameTime(previous.date, role.LastUsed) && m.deltas[role.ARN] == deltaNone {
+		if m.deltas == nil {
+			m.deltas = make(map[string]delta)
+		}
+		m.deltas[role.ARN] = deltaModified
+	}
+}
+
+// refreshLastUse makes every cached last use stale, so the next enrichment
+// fetches them again while the list keeps showing the old values
+func (m *ListModel) refreshLastUse() {
+	m.lastUsedSeq++
+}
+
+// lastUseCurrent reports whether a role's last use was fetched since the
+// list was last refreshed
+func (m *ListModel) lastUseCurrent(arn string) bool {
+	use, ok := m.lastUsed[arn]
+	return ok && use.seq == m.lastUsedSeq
+}
+
+// lastUsedOf returns the last use currently shown for a role
+func (m *ListModel) lastUsedOf(arn string) lastUse {
+	for _, role := range m.roles {
+		if role.ARN == arn {
+			return lastUse{date: role.LastUsed, region: role.LastUsedRegion}
+		}
+	}
+	return lastUse{}
+}
+
+// withLastUse fills the cached last use into freshly listed roles
+func (m *ListModel) withLastUse(roles []iam.Role) []iam.Role {
+	for i := range roles {
+		if use, ok := m.lastUsed[roles[i].ARN]; ok && roles[i].LastUsed == nil {
+			roles[i].LastUsed = use.date
+			roles[i].LastUsedRegion = use.region
+		}
+	}
+	return roles
+}
+
+// LastUseRegions counts the listed roles by the region they were last used
+// in, and returns how many roles' last use is still being fetched
+func (m *ListModel) LastUseRegions() (counts map[string]int, loading int) {
+	counts = make(map[string]int)
+	for _, role := range m.roles {
+		switch {
+		case m.deltas[role.ARN] == deltaDeleted:
+		case role.LastUsedRegion != "":
+			counts[role.LastUsedRegion]++
+		case role.LastUsed == nil && m.lastUseLoading(role.ARN):
+			loading++
+		}
+	}
+	return counts, loading
+}
+
+// lastUseLoading reports whether a role's last use is still being fetched
+func (m *ListModel) lastUseLoading(arn string) bool {
+	return m.lastUsedPending[arn]
+}
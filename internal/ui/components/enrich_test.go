@@ -0,0 +1,53 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/johnoct/a3s/internal/aws/iam"
+)
+
+func TestFindingsLintOnlyVisibleRoles(t *testing.T) {
+	fixture := &iam.Fixture{}
+	for i := range 30 {
+		name := fmt.Sprintf("role-%02d", i)
+		fixture.Roles = append(fixture.Roles, iam.FixtureRole{Name: name, ARN: "arn:aws:iam::123456789012:role/" + name})
+	}
+	api, err := iam.NewFixtureBackend(fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roles, _ := api.ListRoles(context.Background())
+	m := NewListModelWithSize(roles, "test", "us-east-1", 120, 24)
+	m.SetRoleService(api)
+	columns, _ := ResolveColumns(DefaultColumns)
+	m = runCmd(t, m, m.SetColumns(columns))
+
+	rows := m.visibleHeight()
+	tests := []struct {
+		name string
+		key  string
+		want int
+	}{
+		{"first screen", "", rows},
+		{"scrolled to the end", "G", 2 * rows},
+		{"back to the top", "g", 2 * rows},
+	}
+	for _, tt := range tests {
+		if tt.key != "" {
+			model, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(tt.key)})
+			m = runCmd(t, model.(ListModel), cmd)
+		}
+		if got := len(m.findings); got != tt.want {
+			t.Errorf("%s: %d roles linted, want %d", tt.name, got, tt.want)
+		}
+		start, end := m.visibleRange()
+		for _, role := range m.filteredRoles[start:end] {
+			if _, ok := m.findings[role.ARN]; !ok {
+				t.Errorf("%s: %s is shown but not linted", tt.name, role.Name)
+			}
+		}
+	}
+}
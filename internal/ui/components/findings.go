@@ -0,0 +1,167 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/johnoct/a3s/internal/aws/iam"
+	"github.com/johnoct/a3s/internal/policy"
+	"github.com/johnoct/a3s/internal/ui/styles"
+)
+
+// roleFindings is the lint result of one role
+type roleFindings struct {
+	findings []policy.Finding
+	err      error
+}
+
+// roleLintedMsg carries the findings for one role of the list, and the
+// details fetched to lint it. seq is the list's lint generation when the job
+// started.
+type roleLintedMsg struct {
+	role    iam.Role
+	seq     int
+	details *iam.Role
+	result  *roleFindings
+}
+
+// findingsLoadedMsg carries the findings for the role shown in the detail
+// view
+type findingsLoadedMsg struct {
+	roleARN string
+	result  *roleFindings
+}
+
+// lintRole lints the trust policy and every managed and inline policy of a
+// role. role must carry its details, as returned by GetRoleDetails.
+func lintRole(ctx context.Context, api iam.RoleAPI, role *iam.Role, cache *documentCache) ([]policy.Finding, error) {
+	var findings []policy.Finding
+	if role.TrustPolicy != "" {
+		doc, err := policy.Parse(role.TrustPolicy)
+		if err != nil {
+			return nil, fmt.Errorf("trust policy: %w", err)
+		}
+		findings = append(findings, policy.LintTrust(policy.Policy{Name: "Trust policy", Document: doc}, accountOf(role.ARN))...)
+	}
+
+	policies, err := cache.rolePolicies(ctx, api, role)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range policies {
+		findings = append(findings, policy.Lint(p)...)
+	}
+	policy.SortFindings(findings)
+	return findings, nil
+}
+
+// findingsBadge summarizes findings for a list cell, e.g. "[H1] [M2]"
+func findingsBadge(findings []policy.Finding) string {
+	high, medium := 0, 0
+	for _, f := range findings {
+		if f.Severity == policy.SeverityHigh {
+			high++
+		} else {
+			medium++
+		}
+	}
+	var badges []string
+	if high > 0 {
+		badges = append(badges, fmt.Sprintf("[H%d]", high))
+	}
+	if medium > 0 {
+		badges = append(badges, fmt.Sprintf("[M%d]", medium))
+	}
+	if len(badges) == 0 {
+		return "ok"
+	}
+	return strings.Join(badges, " ")
+}
+
+// lintListedRole fetches a listed role's details, unless already known,
+// and lints its policies
+func lintListedRole(api iam.RoleAPI, role iam.Role, seq int, details *iam.Role, cache *documentCache) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		if details == nil {
+			var err error
+			details, err = api.GetRoleDetails(ctx, role.Name)
+			if err != nil {
+				return roleLintedMsg{role: role, seq: seq, result: &roleFindings{err: err}}
+			}
+		}
+		findings, err := lintRole(ctx, api, details, cache)
+		return roleLintedMsg{role: role, seq: seq, details: details, result: &roleFindings{findings: findings, err: err}}
+	}
+}
+
+// loadFindings lints the role when the Findings tab is shown for the first
+// time, or again after a failure
+func (m *DetailModel) loadFindings() tea.Cmd {
+	if m.activeTab != 7 || m.findingsLoading || m.roleService == nil {
+		return nil
+	}
+	if m.findings != nil && m.findings.err == nil {
+		return nil
+	}
+	m.findingsLoading = true
+	role := m.role
+	roleService := m.roleService
+	documents := m.documents
+	return func() tea.Msg {
+		findings, err := lintRole(context.Background(), roleService, role, documents)
+		return findingsLoadedMsg{roleARN: role.ARN, result: &roleFindings{findings: findings, err: err}}
+	}
+}
+
+func (m *DetailModel) renderFindings() string {
+	var s strings.Builder
+
+	s.WriteString(styles.DetailTitle.Render("Findings"))
+	s.WriteString("\n\n")
+
+	switch {
+	case m.findingsLoading || m.findings == nil:
+		s.WriteString(styles.LoadingStyle.Render("Linting the role's policies..."))
+		return s.String()
+	case m.findings.err != nil:
+		s.WriteString(styles.ErrorStyle.Render(fmt.Sprintf("Lint failed: %v", m.findings.err)))
+		s.WriteString("\n\n")
+		s.WriteString(styles.HelpDesc.Render("Switch tabs and come back to retry"))
+		return s.String()
+	case len(m.findings.findings) == 0:
+		s.WriteString(styles.HelpDesc.Render("No findings in the trust policy or the role's policies"))
+		return s.String()
+	}
+
+	s.WriteString(styles.DetailLabel.Render("Summary:"))
+	s.WriteString(" ")
+	s.WriteString(styles.DetailValue.Render(findingsBadge(m.findings.findings)))
+	s.WriteString("\n")
+
+	for _, f := range m.findings.findings {
+		statement := fmt.Sprintf("statement %d", f.Index+1)
+		if f.Sid != "" {
+			statement += fmt.Sprintf(" (%s)", f.Sid)
+		}
+		s.WriteString("\n")
+		heading := fmt.Sprintf("  %-6s %s", strings.ToUpper(f.Severity.String()), f.Rule)
+		if f.Severity == policy.SeverityHigh {
+			s.WriteString(styles.ErrorStyle.PaddingLeft(1).Render(heading))
+		} else {
+			s.WriteString(styles.DeltaModified.Render(heading))
+		}
+		s.WriteString("\n")
+		s.WriteString(styles.ListItem.Render(fmt.Sprintf("         %s, %s", f.Policy, statement)))
+		s.WriteString("\n")
+		s.WriteString(styles.HelpDesc.Render("          " + f.Message))
+		s.WriteString("\n")
+	}
+
+	s.WriteString("\n")
+	s.WriteString(styles.HelpDesc.Render("The permissions boundary and service control policies are not linted."))
+
+	return s.String()
+}
@@ -0,0 +1,90 @@
+package components
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/johnoct/a3s/internal/aws/client"
+	"github.com/johnoct/a3s/internal/aws/iam"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// replayRoleService serves the IAM recording in testdata/replay, as -replay
+// does
+func replayRoleService(t *testing.T) *iam.RoleService {
+	t.Helper()
+	// Keep the developer's AWS configuration out of the test
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(t.TempDir(), "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+	t.Setenv("AWS_PROFILE", "")
+
+	c, err := client.New(context.Background(), "", "", client.WithReplay(filepath.Join("testdata", "replay")))
+	if err != nil {
+		t.Fatalf("client.New() error = %v", err)
+	}
+	return iam.NewRoleService(c, iam.NewSnapshotStore(c))
+}
+
+// assertGolden compares a rendered view with testdata/<name>.golden, or
+// rewrites the file when -update is set
+func assertGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create it)", err)
+	}
+	if got != string(want) {
+		t.Errorf("view differs from %s (run go test -update to accept it)\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+func TestListViewGolden(t *testing.T) {
+	roles, err := replayRoleService(t).ListRoles(context.Background())
+	if err != nil {
+		t.Fatalf("ListRoles() error = %v", err)
+	}
+	m := NewListModelWithSize(roles, "replay", "us-east-1", 120, 24)
+	assertGolden(t, "list", m.View())
+}
+
+func TestDetailViewGolden(t *testing.T) {
+	api := replayRoleService(t)
+	// Listing loads the account snapshot the details are read from, as in
+	// the app
+	if _, err := api.ListRoles(context.Background()); err != nil {
+		t.Fatalf("ListRoles() error = %v", err)
+	}
+	role, err := api.GetRoleDetails(context.Background(), "github-actions-deploy")
+	if err != nil {
+		t.Fatalf("GetRoleDetails() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		tab  int
+	}{
+		{"detail-overview", 0},
+		{"detail-trust", 1},
+		{"detail-policies", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewDetailModel(role, "replay", "us-east-1", api)
+			m.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+			m.activeTab = tt.tab
+			assertGolden(t, tt.name, m.View())
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/johnoct/a3s/internal/aws/iam"
+)
+
+// GroupSource lists IAM groups for a ResourceModel
+type GroupSource struct {
+	api iam.GroupAPI
+}
+
+func NewGroupSource(api iam.GroupAPI) *GroupSource {
+	return &GroupSource{api: api}
+}
+
+func (s *GroupSource) Name() string  { return "groups" }
+func (s *GroupSource) Title() string { return "IAM Groups" }
+
+func (s *GroupSource) Headers() []string {
+	return []string{"Group Name", "Members", "Managed", "Inline", "Created", "Path"}
+}
+
+// List fetches every group's details, enrichConcurrency at a time, since
+// ListGroups reports neither members nor policies
+func (s *GroupSource) List(ctx context.Context) ([]ResourceRow, error) {
+	groups, err := s.api.ListGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	details := make([]*iam.Group, len(groups))
+	sem := make(chan struct{}, enrichConcurrency)
+	var wg sync.WaitGroup
+	for i, g := range groups {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			// A failed fetch leaves nil, shown as "-"
+			details[i], _ = s.api.GetGroupDetails(ctx, g.Name)
+		}()
+	}
+	wg.Wait()
+
+	rows := make([]ResourceRow, len(groups))
+	for i, g := range groups {
+		members, managed, inline := "-", "-", "-"
+		if d := details[i]; d != nil {
+			members = strconv.Itoa(len(d.Members))
+			managed = strconv.Itoa(len(d.ManagedPolicies))
+			inline = strconv.Itoa(len(d.InlinePolicies))
+		}
+		rows[i] = ResourceRow{
+			ID:    g.Name,
+			Cells: []string{g.Name, members, managed, inline, g.CreateDate.Format("2006-01-02"), g.Path},
+		}
+	}
+	return rows, nil
+}
+
+func (s *GroupSource) Describe(ctx context.Context, name string) (*ResourceDetail, error) {
+	group, err := s.api.GetGroupDetails(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ResourceDetail{
+		Title: fmt.Sprintf("👥 Group: %s", group.Name),
+		Tabs: []DetailTab{
+			{Name: "Overview", Lines: groupOverview(group)},
+			{Name: "Members", Lines: groupMembers(group)},
+			{Name: "Policies", Lines: s.groupPolicies(group)},
+		},
+	}, nil
+}
+
+func groupOverview(group *iam.Group) []DetailLine {
+	return []DetailLine{
+		HeadingLine("Group Information"),
+		FieldLine("ARN", group.ARN),
+		FieldLine("Group ID", group.GroupID),
+		FieldLine("Path", group.Path),
+		FieldLine("Created", group.CreateDate.Format("2006-01-02 15:04:05")),
+		FieldLine("Members", strconv.Itoa(len(group.Members))),
+		FieldLine("Policies", fmt.Sprintf("%d managed, %d inline", len(group.ManagedPolicies), len(group.InlinePolicies))),
+	}
+}
+
+func groupMembers(group *iam.Group) []DetailLine {
+	lines := []DetailLine{HeadingLine("Members")}
+	if len(group.Members) == 0 {
+		return append(lines, NoteLine("No members"))
+	}
+	for _, u := range group.Members {
+		lines = append(lines, ItemLine(u, &DetailAction{Open: &OpenResourceMsg{Resource: "users", ID: u}}))
+	}
+	return append(lines, TextLine(""), NoteLine("Press Enter to open the selected user"))
+}
+
+func (s *GroupSource) groupPolicies(group *iam.Group) []DetailLine {
+	return policyLines(group.ManagedPolicies, group.InlinePolicies, s.api.GetManagedPolicyDocument,
+		func(ctx context.Context, name string) (string, error) {
+			return s.api.GetGroupInlinePolicy(ctx, group.Name, name)
+		})
+}
@@ -0,0 +1,82 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/johnoct/a3s/internal/aws/iam"
+)
+
+// InstanceProfileSource lists EC2 instance profiles for a ResourceModel
+type InstanceProfileSource struct {
+	api iam.InstanceProfileAPI
+}
+
+func NewInstanceProfileSource(api iam.InstanceProfileAPI) *InstanceProfileSource {
+	return &InstanceProfileSource{api: api}
+}
+
+func (s *InstanceProfileSource) Name() string  { return "instanceprofiles" }
+func (s *InstanceProfileSource) Title() string { return "IAM Instance Profiles" }
+
+func (s *InstanceProfileSource) Headers() []string {
+	return []string{"Profile Name", "Roles", "Created", "Path"}
+}
+
+func (s *InstanceProfileSource) List(ctx context.Context) ([]ResourceRow, error) {
+	profiles, err := s.api.ListInstanceProfiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]ResourceRow, len(profiles))
+	for i, p := range profiles {
+		roles := "-"
+		if len(p.Roles) > 0 {
+			roles = strings.Join(p.Roles, ", ")
+		}
+		rows[i] = ResourceRow{
+			ID:    p.Name,
+			Cells: []string{p.Name, roles, p.CreateDate.Format("2006-01-02"), p.Path},
+		}
+	}
+	return rows, nil
+}
+
+func (s *InstanceProfileSource) Describe(ctx context.Context, name string) (*ResourceDetail, error) {
+	profile, err := s.api.GetInstanceProfile(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ResourceDetail{
+		Title: fmt.Sprintf("💻 Instance Profile: %s", profile.Name),
+		Tabs: []DetailTab{
+			{Name: "Overview", Lines: instanceProfileOverview(profile)},
+			{Name: "Roles", Lines: instanceProfileRoles(profile)},
+			{Name: "Tags", Lines: tagLines(profile.Tags)},
+		},
+	}, nil
+}
+
+func instanceProfileOverview(profile *iam.InstanceProfile) []DetailLine {
+	return []DetailLine{
+		HeadingLine("Instance Profile Information"),
+		FieldLine("ARN", profile.ARN),
+		FieldLine("Profile ID", profile.InstanceProfileID),
+		FieldLine("Path", profile.Path),
+		FieldLine("Created", profile.CreateDate.Format("2006-01-02 15:04:05")),
+	}
+}
+
+func instanceProfileRoles(profile *iam.InstanceProfile) []DetailLine {
+	lines := []DetailLine{HeadingLine("Roles")}
+	if len(profile.Roles) == 0 {
+		return append(lines, NoteLine("No role; instances launched with this profile get no credentials"))
+	}
+	for _, r := range profile.Roles {
+		lines = append(lines, ItemLine(r, &DetailAction{Open: &OpenResourceMsg{Resource: "roles", ID: r}}))
+	}
+	return append(lines, TextLine(""), NoteLine("Press Enter to open the selected role"))
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -27,8 +29,52 @@ type ListModel struct {
 	selectedRole  *iam.Role
 	showDetail    bool
 	detailView    *DetailModel
-	roleService   *iam.RoleService
+	roleService   iam.RoleAPI
 	loadingDetail bool
+	// jumped is set when the detail was opened from another view; Esc then
+	// returns there instead of to the list
+	jumped bool
+
+	// Refresh state
+	refreshing bool
+	spinner    spinner.Model
+	statusErr  error
+
+	// Watch mode: periodic refresh with change highlighting
+	watchInterval time.Duration
+	watchSeq      int
+	snapshot      map[string]iam.Role
+	deltas        map[string]delta
+
+	// Sort order, re-applied after refresh
+	sortBy   sortColumn
+	sortDesc bool
+
+	// Selected columns and the per-role details some of them need
+	columns        []Column
+	details        map[string]*iam.Role
+	enrichPending  map[string]bool
+	enrichQueue    []iam.Role
+	enrichInFlight int
+
+	// Last use per role ARN, fetched with GetRole since ListRoles omits it.
+	// lastUsedSeq is bumped on refresh so every last use is fetched again.
+	lastUsed        map[string]lastUse
+	lastUsedPending map[string]bool
+	lastUsedQueue   []iam.Role
+	lastUsedSeq     int
+
+	// Lint findings per role ARN for the findings column. lintSeq is bumped
+	// whenever findings are invalidated so lint jobs already running are
+	// redone.
+	findings      map[string]*roleFindings
+	lintPending   map[string]bool
+	lintQueue     []iam.Role
+	lintDocuments *documentCache
+	lintSeq       int
+
+	// Cells and column widths derived from the rows, see listLayout
+	layout *listLayout
 }
 
 func NewListModel(roles []iam.Role, profile, region string) ListModel {
@@ -40,14 +86,22 @@ func NewListModelWithSize(roles []iam.Role, profile, region string, width, heigh
 	ti.Placeholder = "Search roles..."
 	ti.CharLimit = 100
 
+	sp := spinner.New(spinner.WithSpinner(spinner.Dot))
+	sp.Style = styles.LoadingStyle
+
+	columns, _ := ResolveColumns(DefaultColumns)
+
 	m := ListModel{
+		columns:       columns,
 		roles:         roles,
 		filteredRoles: roles,
 		searchInput:   ti,
+		spinner:       sp,
 		profile:       profile,
 		region:        region,
 		width:         width,
 		height:        height,
+		layout:        &listLayout{},
 	}
 
 	return m
@@ -61,12 +115,73 @@ func (m *ListModel) SetIdentity(id *identity.Identity) {
 	m.identity = id
 }
 
-func (m *ListModel) SetRoleService(rs *iam.RoleService) {
+func (m *ListModel) SetRoleService(rs iam.RoleAPI) {
 	m.roleService = rs
 }
 
+// SetColumns changes the displayed columns and starts fetching role details
+// when a selected column needs them
+func (m *ListModel) SetColumns(columns []Column) tea.Cmd {
+	m.columns = columns
+	m.rowsChanged()
+	return m.startEnrichment()
+}
+
+// CapturingInput reports whether the list or its detail view is reading text input
+func (m *ListModel) CapturingInput() bool {
+	if m.showDetail && m.detailView != nil {
+		return m.detailView.CapturingInput()
+	}
+	return m.searchMode
+}
+
+// CloseDetail returns from the detail view to the role list
+func (m *ListModel) CloseDetail() {
+	m.showDetail = false
+	m.detailView = nil
+	m.loadingDetail = false
+	m.jumped = false
+}
+
+// OpenRole loads and shows the detail of a role by name. When jumped is set,
+// Esc from that detail emits ResourceBackMsg instead of returning to the list.
+func (m *ListModel) OpenRole(roleName string, jumped bool) tea.Cmd {
+	m.CloseDetail()
+	m.jumped = jumped
+	m.loadingDetail = true
+	return m.loadRoleDetails(roleName)
+}
+
+// SimulateOpenRole runs a policy simulation against the role whose detail
+// is open. It returns false when no role detail is open.
+func (m *ListModel) SimulateOpenRole(sim iam.Simulation) (tea.Cmd, bool) {
+	if !m.showDetail || m.detailView == nil {
+		return nil, false
+	}
+	return m.detailView.Simulate(sim), true
+}
+
+// LastSimulation returns the simulation last run against the open role,
+// nil when no role detail is open or none has been run
+func (m *ListModel) LastSimulation() *iam.Simulation {
+	if !m.showDetail || m.detailView == nil {
+		return nil
+	}
+	return m.detailView.LastSimulation()
+}
+
+// CurrentRole returns the name of the role whose detail is open, empty when
+// the list is shown, and whether it was opened by a jump from another view
+func (m *ListModel) CurrentRole() (name string, jumped bool) {
+	if m.showDetail && m.detailView != nil {
+		return m.detailView.role.Name, m.jumped
+	}
+	return "", false
+}
+
 type roleDetailsLoadedMsg struct {
 	role *iam.Role
+	err  error
 }
 
 func (m *ListModel) loadRoleDetails(roleName string) tea.Cmd {
@@ -76,21 +191,143 @@ func (m *ListModel) loadRoleDetails(roleName string) tea.Cmd {
 		}
 		ctx := context.Background()
 		role, err := m.roleService.GetRoleDetails(ctx, roleName)
-		if err != nil {
-			// For now, return nil on error
-			return nil
+		return roleDetailsLoadedMsg{role: role, err: err}
+	}
+}
+
+// rolesRefreshedMsg carries the result of re-listing roles
+type rolesRefreshedMsg struct {
+	roles    []iam.Role
+	explicit bool
+	err      error
+}
+
+// detailRefreshedMsg carries fresh details for the role shown in the detail view
+type detailRefreshedMsg struct {
+	role *iam.Role
+	err  error
+}
+
+// refresh reloads the role list and, when open, the displayed role's details.
+// An explicit refresh reloads the account snapshot; watch cycles only list
+// the roles again.
+func (m *ListModel) refresh(explicit bool) tea.Cmd {
+	if m.refreshing || m.roleService == nil {
+		return nil
+	}
+	m.refreshing = true
+	m.statusErr = nil
+
+	roleService := m.roleService
+	cmds := []tea.Cmd{
+		m.spinner.Tick,
+		func() tea.Msg {
+			list := roleService.ListRoleSummaries
+			if explicit {
+				list = roleService.RefreshRoles
+			}
+			roles, err := list(context.Background())
+			return rolesRefreshedMsg{roles: roles, explicit: explicit, err: err}
+		},
+	}
+
+	if m.showDetail && m.detailView != nil {
+		roleName := m.detailView.role.Name
+		cmds = append(cmds, func() tea.Msg {
+			role, err := roleService.GetRoleDetails(context.Background(), roleName)
+			return detailRefreshedMsg{role: role, err: err}
+		})
+	}
+
+	return tea.Batch(cmds...)
+}
+
+// applyRefresh swaps in a fresh role list, keeping the search filter and
+// re-anchoring the cursor on the same role
+func (m *ListModel) applyRefresh(roles []iam.Role) {
+	m.reorder(func() {
+		m.roles = m.applyDeltas(m.withLastUse(roles))
+	})
+	m.rowsChanged()
+}
+
+// reorder applies update to the roles, then re-sorts and re-filters them
+// keeping the cursor on the selected role
+func (m *ListModel) reorder(update func()) {
+	var selectedARN string
+	if m.cursor < len(m.filteredRoles) {
+		selectedARN = m.filteredRoles[m.cursor].ARN
+	}
+
+	update()
+	m.sortRoles()
+	m.filterRoles()
+
+	for i, role := range m.filteredRoles {
+		if role.ARN == selectedARN {
+			m.cursor = i
+			return
 		}
-		return roleDetailsLoadedMsg{role: role}
+	}
+	if m.cursor >= len(m.filteredRoles) {
+		m.cursor = max(0, len(m.filteredRoles)-1)
 	}
 }
 
 func (m ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
+	// Refresh results apply whether or not the detail view is open
+	switch msg := msg.(type) {
+	case rolesRefreshedMsg:
+		m.refreshing = false
+		if msg.err != nil {
+			m.statusErr = msg.err
+			return m, nil
+		}
+		m.applyRefresh(msg.roles)
+		m.refreshLastUse()
+		m.invalidateFindings(msg.explicit)
+		return m, m.startEnrichment()
+	case detailRefreshedMsg:
+		if msg.err != nil {
+			m.statusErr = msg.err
+			return m, nil
+		}
+		if m.detailView != nil && msg.role != nil && m.detailView.role.ARN == msg.role.ARN {
+			m.selectedRole = msg.role
+			cmd = m.detailView.SetRole(msg.role)
+		}
+		if m.details != nil && msg.role != nil {
+			m.details[msg.role.ARN] = msg.role
+			m.rowChanged(msg.role.ARN)
+		}
+		if msg.role != nil {
+			m.recordLastUse(msg.role)
+		}
+		return m, cmd
+	case roleEnrichedMsg:
+		return m, m.handleEnriched(msg)
+	case roleLastUsedMsg:
+		return m, m.handleLastUsed(msg)
+	case roleLintedMsg:
+		return m, m.handleLinted(msg)
+	case watchTickMsg:
+		return m, m.handleWatchTick(msg)
+	case spinner.TickMsg:
+		if !m.refreshing {
+			return m, nil
+		}
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	}
+
 	// Handle detail view updates
 	if m.showDetail && m.detailView != nil {
 		switch msg := msg.(type) {
 		case tea.WindowSizeMsg:
+			m.width = msg.Width
+			m.height = msg.Height
 			// Pass window size to detail view
 			var detailModel tea.Model
 			detailModel, cmd = m.detailView.Update(msg)
@@ -98,17 +335,17 @@ func (m ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, cmd
 		case tea.KeyMsg:
 			// Only handle esc/q to close detail view if we're not viewing a policy document
-			if msg.String() == "esc" && !m.detailView.IsViewingPolicyDocument() {
-				m.showDetail = false
-				m.detailView = nil
-				m.loadingDetail = false
+			if (msg.String() == "esc" && !m.detailView.IsViewingPolicyDocument() && !m.detailView.CapturingInput()) ||
+				(msg.String() == "q" && !m.detailView.CapturingInput()) {
+				jumped := m.jumped
+				m.CloseDetail()
+				if jumped {
+					return m, func() tea.Msg { return ResourceBackMsg{} }
+				}
 				return m, nil
 			}
-			if msg.String() == "q" {
-				m.showDetail = false
-				m.detailView = nil
-				m.loadingDetail = false
-				return m, nil
+			if msg.String() == "r" && !m.detailView.CapturingInput() {
+				return m, m.refresh(true)
 			}
 		}
 
@@ -120,7 +357,19 @@ func (m ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case roleDetailsLoadedMsg:
+		if !m.loadingDetail {
+			return m, nil
+		}
 		m.loadingDetail = false
+		if msg.err != nil {
+			m.statusErr = msg.err
+			if m.jumped {
+				m.jumped = false
+				m.statusErr = nil
+				return m, func() tea.Msg { return ResourceBackMsg{Err: msg.err} }
+			}
+			return m, nil
+		}
 		if msg.role != nil {
 			m.selectedRole = msg.role
 			m.detailView = NewDetailModel(m.selectedRole, m.profile, m.region, m.roleService)
@@ -136,7 +385,7 @@ func (m ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		return m, nil
+		return m, m.lintVisible()
 
 	case tea.KeyMsg:
 		if m.searchMode {
@@ -145,16 +394,17 @@ func (m ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.searchMode = false
 				m.searchInput.SetValue("")
 				m.filteredRoles = m.roles
+				m.filterChanged()
 				m.cursor = 0
-				return m, nil
+				return m, m.lintVisible()
 			case "enter":
 				m.searchMode = false
 				m.filterRoles()
-				return m, nil
+				return m, m.lintVisible()
 			default:
 				m.searchInput, cmd = m.searchInput.Update(msg)
 				m.filterRoles()
-				return m, cmd
+				return m, tea.Batch(cmd, m.lintVisible())
 			}
 		}
 
@@ -180,21 +430,28 @@ func (m ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.searchInput.Focus()
 			return m, textinput.Blink
 		case "enter":
-			if len(m.filteredRoles) > 0 && m.cursor < len(m.filteredRoles) && !m.loadingDetail {
+			if len(m.filteredRoles) > 0 && m.cursor < len(m.filteredRoles) && !m.loadingDetail &&
+				m.deltas[m.filteredRoles[m.cursor].ARN] != deltaDeleted {
 				m.loadingDetail = true
 				roleName := m.filteredRoles[m.cursor].Name
 				return m, m.loadRoleDetails(roleName)
 			}
+		case "N", "C", "L":
+			m.toggleSort(sortKeys[msg.String()])
+		case "R":
+			return m, func() tea.Msg { return CommandMsg{Line: "region"} }
 		case "r":
-			// TODO: Implement refresh
-			return m, nil
+			return m, m.refresh(true)
 		}
+		// Moving, sorting or filtering may bring unlinted roles on screen
+		return m, tea.Batch(cmd, m.lintVisible())
 	}
 
 	return m, cmd
 }
 
 func (m *ListModel) filterRoles() {
+	m.filterChanged()
 	searchTerm := strings.ToLower(m.searchInput.Value())
 	if searchTerm == "" {
 		m.filteredRoles = m.roles
@@ -216,13 +473,32 @@ func (m *ListModel) filterRoles() {
 
 func (m ListModel) View() string {
 	if m.showDetail && m.detailView != nil {
-		return m.detailView.View()
+		return m.withStatus(m.detailView.View())
 	}
 
 	if m.loadingDetail {
 		return "\n  Loading role details... ⚡\n"
 	}
 
+	return m.withStatus(m.listView())
+}
+
+// withStatus appends the refresh spinner or the last refresh error to the
+// help line at the bottom of the view
+func (m ListModel) withStatus(view string) string {
+	if m.watchInterval > 0 {
+		view += "  " + styles.SearchInfo.Render(fmt.Sprintf("⟳ %s", m.watchInterval))
+	}
+	switch {
+	case m.refreshing:
+		return view + "  " + m.spinner.View() + styles.LoadingStyle.Render(" Refreshing...")
+	case m.statusErr != nil:
+		return view + "  " + styles.ErrorStyle.Render(truncate(m.statusErr.Error(), max(20, m.width/2)))
+	}
+	return view
+}
+
+func (m ListModel) listView() string {
 	var content strings.Builder
 	var fullView strings.Builder
 
@@ -252,71 +528,24 @@ func (m ListModel) View() string {
 		availableWidth = 80
 	}
 
-	// Distribute width with fixed spacing between columns
-	// Using fixed column widths with proper spacing
-	roleWidth := 40
-	createdWidth := 12
-	lastUsedWidth := 12
-	// Calculate remaining space for description
-	descWidth := availableWidth - roleWidth - createdWidth - lastUsedWidth - 3 // 3 spaces between columns
-	if descWidth < 20 {
-		descWidth = 20
-	}
+	widths := m.columnWidths(availableWidth)
 
 	// Column headers (inside the border)
-	headers := fmt.Sprintf("%-*s %-*s %-*s %s",
-		roleWidth, "Role Name",
-		createdWidth, "Created",
-		lastUsedWidth, "Last Used",
-		"Description",
-	)
-	content.WriteString(styles.ListHeader.Width(availableWidth).Render(headers))
-	content.WriteString("\n")
-
-	// Calculate visible height accounting for border and header
-	borderHeight := 2 // Reduced from 4
-	headerHeight := 9 // ASCII art (6 lines) + top margin (1) + spacing (2)
-	searchHeight := 1 // Always reserve space for search bar to prevent layout shifts
-	statusHeight := 1 // Reduced from 2
-	helpHeight := 1
-
-	visibleHeight := m.height - borderHeight - headerHeight - searchHeight - statusHeight - helpHeight
-	if visibleHeight < 5 {
-		visibleHeight = 5
+	titles := make([]string, len(m.columns))
+	for i, c := range m.columns {
+		titles[i] = m.sortIndicator(c.Title, c.sort)
 	}
+	content.WriteString(styles.ListHeader.Width(availableWidth).Render(formatRow(titles, widths)))
+	content.WriteString("\n")
 
-	startIdx := 0
-	if m.cursor >= visibleHeight {
-		startIdx = m.cursor - visibleHeight + 1
-	}
-	endIdx := startIdx + visibleHeight
-	if endIdx > len(m.filteredRoles) {
-		endIdx = len(m.filteredRoles)
-	}
+	visibleHeight := m.visibleHeight()
+	startIdx, endIdx := m.visibleRange()
 
 	// Role list (inside the border)
 	for i := startIdx; i < endIdx; i++ {
 		role := m.filteredRoles[i]
 
-		created := role.CreateDate.Format("2006-01-02")
-		lastUsed := "Never"
-		if role.LastUsed != nil {
-			lastUsed = role.LastUsed.Format("2006-01-02")
-		}
-
-		// Truncate fields to exact column widths
-		roleName := truncate(role.Name, roleWidth-1) // -1 for spacing
-		createdStr := truncate(created, createdWidth-1)
-		lastUsedStr := truncate(lastUsed, lastUsedWidth-1)
-		description := truncate(role.Description, descWidth)
-
-		// Build the line with exact spacing
-		line := fmt.Sprintf("%-*s %-*s %-*s %s",
-			roleWidth, roleName,
-			createdWidth, createdStr,
-			lastUsedWidth, lastUsedStr,
-			description,
-		)
+		line := formatRow(m.cells(role), widths)
 
 		// Ensure the entire line doesn't exceed available width
 		line = truncate(line, availableWidth)
@@ -325,8 +554,17 @@ func (m ListModel) View() string {
 			// Apply selection without padding to maintain alignment
 			content.WriteString(styles.SelectedItem.Render(line))
 		} else {
-			// Regular items with padding
-			content.WriteString(styles.ListItem.Render(line))
+			// Regular items with padding, coloured by watch delta
+			switch m.deltas[role.ARN] {
+			case deltaAdded:
+				content.WriteString(styles.DeltaAdded.Render(line))
+			case deltaModified:
+				content.WriteString(styles.DeltaModified.Render(line))
+			case deltaDeleted:
+				content.WriteString(styles.DeltaDeleted.Render(line))
+			default:
+				content.WriteString(styles.ListItem.Render(line))
+			}
 		}
 		content.WriteString("\n")
 	}
@@ -351,9 +589,46 @@ func (m ListModel) View() string {
 	return fullView.String()
 }
 
+// visibleHeight is the number of role rows that fit on screen
+func (m *ListModel) visibleHeight() int {
+	// Calculate visible height accounting for border and header
+	borderHeight := 2 // Reduced from 4
+	headerHeight := 9 // ASCII art (6 lines) + top margin (1) + spacing (2)
+	searchHeight := 1 // Always reserve space for search bar to prevent layout shifts
+	statusHeight := 1 // Reduced from 2
+	helpHeight := 1
+
+	visibleHeight := m.height - borderHeight - headerHeight - searchHeight - statusHeight - helpHeight
+	if visibleHeight < 5 {
+		visibleHeight = 5
+	}
+	return visibleHeight
+}
+
+// visibleRange returns the indexes of the filtered roles shown on screen,
+// end excluded
+func (m *ListModel) visibleRange() (start, end int) {
+	visibleHeight := m.visibleHeight()
+	if m.cursor >= visibleHeight {
+		start = m.cursor - visibleHeight + 1
+	}
+	end = min(start+visibleHeight, len(m.filteredRoles))
+	return start, end
+}
+
 func truncate(s string, max int) string {
-	if len(s) <= max {
+	if lipgloss.Width(s) <= max {
 		return s
 	}
-	return s[:max-3] + "..."
+	var b strings.Builder
+	width := 0
+	for _, r := range s {
+		w := lipgloss.Width(string(r))
+		if width+w > max-3 {
+			break
+		}
+		b.WriteRune(r)
+		width += w
+	}
+	return b.String() + "..."
 }
@@ -0,0 +1,286 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/johnoct/a3s/internal/aws/identity"
+	"github.com/johnoct/a3s/internal/ui/styles"
+)
+
+// PickerItem is a selectable row in a PickerModel
+type PickerItem struct {
+	Value   string
+	Columns []string
+	// Current marks the item that is active right now (e.g. the current profile)
+	Current bool
+}
+
+// PickedMsg is emitted when an item is chosen in a picker
+type PickedMsg struct {
+	Picker string
+	Value  string
+}
+
+// PickerClosedMsg is emitted when a picker is dismissed without a choice
+type PickerClosedMsg struct {
+	Picker string
+}
+
+// PickerModel is a full-screen, searchable selection list used for choosing
+// profiles, regions and similar values
+type PickerModel struct {
+	id      string
+	title   string
+	headers []string
+	items   []PickerItem
+	visible []int
+
+	cursor      int
+	searchMode  bool
+	searchInput textinput.Model
+	status      string
+
+	width    int
+	height   int
+	profile  string
+	region   string
+	identity *identity.Identity
+}
+
+// NewPickerModel creates a picker identified by id. The id is echoed back in
+// PickedMsg and PickerClosedMsg so the owner can tell pickers apart.
+func NewPickerModel(id, title string, headers []string, items []PickerItem) *PickerModel {
+	ti := textinput.New()
+	ti.Placeholder = "Filter..."
+	ti.CharLimit = 100
+
+	m := &PickerModel{
+		id:          id,
+		title:       title,
+		headers:     headers,
+		items:       items,
+		searchInput: ti,
+		width:       80,
+		height:      24,
+	}
+	m.applyFilter()
+
+	// Start on the current item
+	for i, idx := range m.visible {
+		if m.items[idx].Current {
+			m.cursor = i
+			break
+		}
+	}
+
+	return m
+}
+
+// SetContext sets the header information shown above the picker
+func (m *PickerModel) SetContext(profile, region string, id *identity.Identity) {
+	m.profile = profile
+	m.region = region
+	m.identity = id
+}
+
+// SetStatus sets a short note rendered next to the title
+func (m *PickerModel) SetStatus(status string) {
+	m.status = status
+}
+
+// UpdateItem replaces the columns of the item with the given value
+func (m *PickerModel) UpdateItem(value string, columns []string) {
+	for i := range m.items {
+		if m.items[i].Value == value {
+			m.items[i].Columns = columns
+			return
+		}
+	}
+}
+
+// CapturingInput reports whether the filter prompt is reading text
+func (m *PickerModel) CapturingInput() bool {
+	return m.searchMode
+}
+
+func (m *PickerModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *PickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.searchMode {
+			switch msg.String() {
+			case "esc":
+				m.searchMode = false
+				m.searchInput.SetValue("")
+				m.applyFilter()
+				return m, nil
+			case "enter":
+				m.searchMode = false
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.searchInput, cmd = m.searchInput.Update(msg)
+			m.applyFilter()
+			return m, cmd
+		}
+
+		switch msg.String() {
+		case "esc", "q":
+			id := m.id
+			return m, func() tea.Msg { return PickerClosedMsg{Picker: id} }
+		case "j", "down":
+			if m.cursor < len(m.visible)-1 {
+				m.cursor++
+			}
+		case "k", "up":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "g":
+			m.cursor = 0
+		case "G":
+			if len(m.visible) > 0 {
+				m.cursor = len(m.visible) - 1
+			}
+		case "/":
+			m.searchMode = true
+			return m, m.searchInput.Focus()
+		case "enter":
+			if m.cursor < len(m.visible) {
+				id, value := m.id, m.items[m.visible[m.cursor]].Value
+				return m, func() tea.Msg { return PickedMsg{Picker: id, Value: value} }
+			}
+		}
+	}
+
+	return m, nil
+}
+
+func (m *PickerModel) applyFilter() {
+	term := strings.ToLower(m.searchInput.Value())
+	m.visible = m.visible[:0]
+	for i, item := range m.items {
+		if term == "" || strings.Contains(strings.ToLower(item.Value+" "+strings.Join(item.Columns, " ")), term) {
+			m.visible = append(m.visible, i)
+		}
+	}
+	if m.cursor >= len(m.visible) {
+		m.cursor = 0
+	}
+}
+
+func (m *PickerModel) View() string {
+	var content strings.Builder
+	var fullView strings.Builder
+
+	fullView.WriteString(styles.RenderHeader(m.profile, m.region, m.identity, m.width))
+	fullView.WriteString("\n")
+
+	fullView.WriteString("   ")
+	fullView.WriteString(lipgloss.JoinHorizontal(lipgloss.Top,
+		styles.TitleStyle.Render(m.title),
+		"  "+styles.HelpDesc.Render(m.status),
+	))
+	fullView.WriteString("\n")
+
+	// Filter bar - always reserve space to prevent layout shifts
+	if m.searchMode {
+		fullView.WriteString(styles.SearchPrompt.Render(" Filter: "))
+		fullView.WriteString(m.searchInput.View())
+	}
+	fullView.WriteString("\n")
+
+	availableWidth := m.width - 8
+	if availableWidth < 80 {
+		availableWidth = 80
+	}
+
+	widths := m.columnWidths(availableWidth)
+	content.WriteString(styles.ListHeader.Width(availableWidth).Render(formatRow(m.headers, widths)))
+	content.WriteString("\n")
+
+	// Header (6) + title (2) + filter (1) + border (2) + table header (2) + help (1)
+	visibleHeight := m.height - 14
+	if visibleHeight < 5 {
+		visibleHeight = 5
+	}
+
+	startIdx := 0
+	if m.cursor >= visibleHeight {
+		startIdx = m.cursor - visibleHeight + 1
+	}
+	endIdx := min(startIdx+visibleHeight, len(m.visible))
+
+	for i := startIdx; i < endIdx; i++ {
+		item := m.items[m.visible[i]]
+		marker := "  "
+		if item.Current {
+			marker = "* "
+		}
+		cells := append([]string{marker + item.Value}, item.Columns...)
+		line := truncate(formatRow(cells, widths), availableWidth)
+
+		if i == m.cursor {
+			content.WriteString(styles.SelectedItem.Render(line))
+		} else {
+			content.WriteString(styles.ListItem.Render(line))
+		}
+		content.WriteString("\n")
+	}
+
+	for i := endIdx - startIdx; i < visibleHeight; i++ {
+		content.WriteString(strings.Repeat(" ", availableWidth))
+		content.WriteString("\n")
+	}
+
+	borderedContent := styles.GetMainContainer(m.width, visibleHeight+2).Render(strings.TrimRight(content.String(), "\n"))
+	fullView.WriteString(borderedContent)
+	fullView.WriteString("\n")
+
+	help := []string{
+		styles.HelpKey.Render("j/k") + " " + styles.HelpDesc.Render("up/down"),
+		styles.HelpKey.Render("Enter") + " " + styles.HelpDesc.Render("select"),
+		styles.HelpKey.Render("/") + " " + styles.HelpDesc.Render("filter"),
+		styles.HelpKey.Render("Esc") + " " + styles.HelpDesc.Render("cancel"),
+	}
+	fullView.WriteString(styles.HelpStyle.Render(strings.Join(help, " | ")))
+
+	return fullView.String()
+}
+
+// columnWidths fits the columns to the items, including the current marker
+func (m *PickerModel) columnWidths(availableWidth int) []int {
+	rows := make([][]string, len(m.items))
+	for i, item := range m.items {
+		rows[i] = append([]string{"* " + item.Value}, item.Columns...)
+	}
+	return fitColumns(m.headers, rows, availableWidth)
+}
+
+func formatRow(cells []string, widths []int) string {
+	var row strings.Builder
+	for i, w := range widths {
+		cell := ""
+		if i < len(cells) {
+			cell = cells[i]
+		}
+		if i < len(widths)-1 {
+			cell = truncate(cell, w)
+			row.WriteString(cell + strings.Repeat(" ", max(0, w-lipgloss.Width(cell))+1))
+		} else {
+			row.WriteString(truncate(cell, w))
+		}
+	}
+	return row.String()
+}
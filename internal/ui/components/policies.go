@@ -0,0 +1,187 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/johnoct/a3s/internal/aws/iam"
+)
+
+// PolicySource lists managed policies for a ResourceModel. It starts on
+// customer managed policies; "s" toggles AWS managed and all policies.
+type PolicySource struct {
+	api   iam.PolicyAPI
+	scope iam.PolicyScope
+}
+
+var _ ScopedSource = (*PolicySource)(nil)
+
+func NewPolicySource(api iam.PolicyAPI) *PolicySource {
+	return &PolicySource{api: api, scope: iam.PolicyScopeLocal}
+}
+
+func (s *PolicySource) Name() string  { return "policies" }
+func (s *PolicySource) Title() string { return "IAM Policies" }
+
+func (s *PolicySource) Scopes() []string {
+	scopes := make([]string, len(iam.PolicyScopes))
+	for i, scope := range iam.PolicyScopes {
+		scopes[i] = string(scope)
+	}
+	return scopes
+}
+
+func (s *PolicySource) Scope() string         { return string(s.scope) }
+func (s *PolicySource) SetScope(scope string) { s.scope = iam.PolicyScope(scope) }
+
+func (s *PolicySource) Headers() []string {
+	return []string{"Policy Name", "Type", "Attached", "Version", "Updated", "Path"}
+}
+
+func (s *PolicySource) List(ctx context.Context) ([]ResourceRow, error) {
+	policies, err := s.api.ListPolicies(ctx, s.scope)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]ResourceRow, len(policies))
+	for i, p := range policies {
+		rows[i] = ResourceRow{
+			ID: p.ARN,
+			Cells: []string{
+				p.Name,
+				policyType(p.ARN),
+				strconv.Itoa(int(p.AttachmentCount)),
+				p.DefaultVersion,
+				formatDate(&p.UpdateDate, "-"),
+				p.Path,
+			},
+		}
+	}
+	return rows, nil
+}
+
+func (s *PolicySource) Describe(ctx context.Context, policyArn string) (*ResourceDetail, error) {
+	policy, err := s.api.GetPolicyDetails(ctx, policyArn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ResourceDetail{
+		Title: fmt.Sprintf("📜 Policy: %s", policy.Name),
+		Tabs: []DetailTab{
+			{Name: "Overview", Lines: policyOverview(policy)},
+			{Name: "Versions", Lines: s.policyVersions(policy)},
+			{Name: "Attachments", Lines: policyAttachments(policy)},
+		},
+	}, nil
+}
+
+// policyType labels a policy as customer or AWS managed
+func policyType(policyArn string) string {
+	if iam.IsAWSManagedPolicy(policyArn) {
+		return "AWS"
+	}
+	return "Customer"
+}
+
+func policyOverview(policy *iam.Policy) []DetailLine {
+	return []DetailLine{
+		HeadingLine("Policy Information"),
+		FieldLine("ARN", policy.ARN),
+		FieldLine("Policy ID", policy.PolicyID),
+		FieldLine("Type", policyType(policy.ARN)+" managed"),
+		FieldLine("Path", policy.Path),
+		FieldLine("Description", policy.Description),
+		FieldLine("Default Version", policy.DefaultVersion),
+		FieldLine("Versions", strconv.Itoa(len(policy.Versions))),
+		FieldLine("Attachments", strconv.Itoa(int(policy.AttachmentCount))),
+		FieldLine("Boundary Usage", strconv.Itoa(int(policy.BoundaryCount))),
+		FieldLine("Created", formatTimestamp(policy.CreateDate)),
+		FieldLine("Updated", formatTimestamp(policy.UpdateDate)),
+	}
+}
+
+func (s *PolicySource) policyVersions(policy *iam.Policy) []DetailLine {
+	lines := []DetailLine{HeadingLine("Policy Versions")}
+	if len(policy.Versions) == 0 {
+		return append(lines, NoteLine("No versions"))
+	}
+
+	for _, v := range policy.Versions {
+		text := fmt.Sprintf("%-4s %s", v.ID, formatTimestamp(v.CreateDate))
+		if v.IsDefault {
+			text += "  (default)"
+		}
+		lines = append(lines, ItemLine(text, &DetailAction{
+			Title: fmt.Sprintf("Policy Document: %s %s", policy.Name, v.ID),
+			Document: func(ctx context.Context) (string, error) {
+				return s.api.GetPolicyVersionDocument(ctx, policy.ARN, v.ID)
+			},
+		}))
+		if !v.IsDefault && policy.DefaultVersion != "" {
+			lines = append(lines, ItemLine(fmt.Sprintf("  ↳ compare with %s (default)", policy.DefaultVersion), &DetailAction{
+				Title: fmt.Sprintf("Policy Diff: %s %s → %s", policy.Name, v.ID, policy.DefaultVersion),
+				Document: func(ctx context.Context) (string, error) {
+					return s.compareVersions(ctx, policy.ARN, v.ID, policy.DefaultVersion)
+				},
+			}))
+		}
+	}
+	return append(lines, TextLine(""), NoteLine("Press Enter to view the selected version, or its changes up to the default version"))
+}
+
+// compareVersions diffs two versions of a policy, the lines only in from
+// marked "-" and the lines only in to marked "+"
+func (s *PolicySource) compareVersions(ctx context.Context, policyArn, from, to string) (string, error) {
+	fromDoc, err := s.api.GetPolicyVersionDocument(ctx, policyArn, from)
+	if err != nil {
+		return "", err
+	}
+	toDoc, err := s.api.GetPolicyVersionDocument(ctx, policyArn, to)
+	if err != nil {
+		return "", err
+	}
+	if fromDoc == toDoc {
+		return fmt.Sprintf("%s and %s are identical\n\n%s", from, to, toDoc), nil
+	}
+	return diffLines(fromDoc, toDoc), nil
+}
+
+func policyAttachments(policy *iam.Policy) []DetailLine {
+	lines := []DetailLine{HeadingLine("Attached To")}
+	if len(policy.AttachedRoles)+len(policy.AttachedUsers)+len(policy.AttachedGroups) == 0 {
+		return append(lines, NoteLine("Not attached to any role, user or group"))
+	}
+
+	groups := []struct {
+		label    string
+		resource string
+		names    []string
+	}{
+		{"Roles:", "roles", policy.AttachedRoles},
+		{"Users:", "users", policy.AttachedUsers},
+		{"Groups:", "groups", policy.AttachedGroups},
+	}
+	for _, g := range groups {
+		if len(g.names) == 0 {
+			continue
+		}
+		lines = append(lines, LabelLine(g.label))
+		for _, name := range g.names {
+			lines = append(lines, ItemLine(name, &DetailAction{Open: &OpenResourceMsg{Resource: g.resource, ID: name}}))
+		}
+		lines = append(lines, TextLine(""))
+	}
+	return append(lines, NoteLine("Press Enter to open the selected entity"))
+}
+
+// formatTimestamp formats a time to the second, or "-" when unset
+func formatTimestamp(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
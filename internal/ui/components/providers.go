@@ -0,0 +1,228 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/johnoct/a3s/internal/aws/iam"
+)
+
+// samlExpiryWarning is how long before its metadata expires a SAML provider
+// is flagged in the list
+const samlExpiryWarning = 30 * 24 * time.Hour
+
+// OIDCProviderSource lists OpenID Connect identity providers for a
+// ResourceModel
+type OIDCProviderSource struct {
+	api   iam.ProviderAPI
+	roles iam.RoleAPI
+}
+
+func NewOIDCProviderSource(api iam.ProviderAPI, roles iam.RoleAPI) *OIDCProviderSource {
+	return &OIDCProviderSource{api: api, roles: roles}
+}
+
+func (s *OIDCProviderSource) Name() string  { return "oidc" }
+func (s *OIDCProviderSource) Title() string { return "IAM OIDC Providers" }
+
+func (s *OIDCProviderSource) Headers() []string {
+	return []string{"Provider URL", "Client IDs", "Thumbprints", "Created"}
+}
+
+// List fetches every provider, enrichConcurrency at a time, since
+// ListOpenIDConnectProviders reports only ARNs
+func (s *OIDCProviderSource) List(ctx context.Context) ([]ResourceRow, error) {
+	providers, err := s.api.ListOIDCProviders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	details := make([]*iam.OIDCProvider, len(providers))
+	sem := make(chan struct{}, enrichConcurrency)
+	var wg sync.WaitGroup
+	for i, p := range providers {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			// A failed fetch leaves nil, shown as "-"
+			details[i], _ = s.api.GetOIDCProvider(ctx, p.ARN)
+		}()
+	}
+	wg.Wait()
+
+	rows := make([]ResourceRow, len(providers))
+	for i, p := range providers {
+		clientIDs, thumbprints, created := "-", "-", "-"
+		if d := details[i]; d != nil {
+			clientIDs = joinOrDash(d.ClientIDs)
+			thumbprints = strconv.Itoa(len(d.Thumbprints))
+			created = d.CreateDate.Format("2006-01-02")
+		}
+		rows[i] = ResourceRow{
+			ID:    p.ARN,
+			Cells: []string{p.URL, clientIDs, thumbprints, created},
+		}
+	}
+	return rows, nil
+}
+
+func (s *OIDCProviderSource) Describe(ctx context.Context, arn string) (*ResourceDetail, error) {
+	provider, err := s.api.GetOIDCProvider(ctx, arn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ResourceDetail{
+		Title: fmt.Sprintf("🔗 OIDC Provider: %s", provider.URL),
+		Tabs: []DetailTab{
+			{Name: "Overview", Lines: oidcProviderOverview(provider)},
+			{Name: "Roles", Lines: federatedRoleLines(ctx, s.roles, provider.ARN)},
+			{Name: "Tags", Lines: tagLines(provider.Tags)},
+		},
+	}, nil
+}
+
+func oidcProviderOverview(provider *iam.OIDCProvider) []DetailLine {
+	lines := []DetailLine{
+		HeadingLine("OIDC Provider Information"),
+		FieldLine("ARN", provider.ARN),
+		FieldLine("URL", provider.URL),
+		FieldLine("Created", provider.CreateDate.Format("2006-01-02 15:04:05")),
+		TextLine(""),
+		HeadingLine("Client IDs"),
+	}
+	if len(provider.ClientIDs) == 0 {
+		lines = append(lines, NoteLine("No client IDs"))
+	}
+	for _, id := range provider.ClientIDs {
+		lines = append(lines, TextLine("  "+id))
+	}
+
+	lines = append(lines, TextLine(""), HeadingLine("Thumbprints"))
+	if len(provider.Thumbprints) == 0 {
+		lines = append(lines, NoteLine("No thumbprints"))
+	}
+	for _, t := range provider.Thumbprints {
+		lines = append(lines, TextLine("  "+t))
+	}
+	return lines
+}
+
+// SAMLProviderSource lists SAML identity providers for a ResourceModel
+type SAMLProviderSource struct {
+	api   iam.ProviderAPI
+	roles iam.RoleAPI
+}
+
+func NewSAMLProviderSource(api iam.ProviderAPI, roles iam.RoleAPI) *SAMLProviderSource {
+	return &SAMLProviderSource{api: api, roles: roles}
+}
+
+func (s *SAMLProviderSource) Name() string  { return "saml" }
+func (s *SAMLProviderSource) Title() string { return "IAM SAML Providers" }
+
+func (s *SAMLProviderSource) Headers() []string {
+	return []string{"Provider Name", "Valid Until", "Created"}
+}
+
+func (s *SAMLProviderSource) List(ctx context.Context) ([]ResourceRow, error) {
+	providers, err := s.api.ListSAMLProviders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]ResourceRow, len(providers))
+	for i, p := range providers {
+		rows[i] = ResourceRow{
+			ID:    p.ARN,
+			Cells: []string{p.Name, samlValidity(p.ValidUntil), p.CreateDate.Format("2006-01-02")},
+		}
+	}
+	return rows, nil
+}
+
+func (s *SAMLProviderSource) Describe(ctx context.Context, arn string) (*ResourceDetail, error) {
+	provider, err := s.api.GetSAMLProvider(ctx, arn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ResourceDetail{
+		Title: fmt.Sprintf("🔗 SAML Provider: %s", provider.Name),
+		Tabs: []DetailTab{
+			{Name: "Overview", Lines: samlProviderOverview(provider)},
+			{Name: "Roles", Lines: federatedRoleLines(ctx, s.roles, provider.ARN)},
+			{Name: "Tags", Lines: tagLines(provider.Tags)},
+		},
+	}, nil
+}
+
+func samlProviderOverview(provider *iam.SAMLProvider) []DetailLine {
+	return []DetailLine{
+		HeadingLine("SAML Provider Information"),
+		FieldLine("ARN", provider.ARN),
+		FieldLine("Name", provider.Name),
+		FieldLine("Created", provider.CreateDate.Format("2006-01-02 15:04:05")),
+		FieldLine("Valid Until", samlValidity(provider.ValidUntil)),
+	}
+}
+
+// samlValidity formats when a provider's metadata expires, flagging expired
+// metadata and metadata expiring within samlExpiryWarning
+func samlValidity(validUntil time.Time) string {
+	if validUntil.IsZero() {
+		return "-"
+	}
+	date := validUntil.Format("2006-01-02")
+	remaining := time.Until(validUntil)
+	switch {
+	case remaining <= 0:
+		return date + " (expired)"
+	case remaining < samlExpiryWarning:
+		return fmt.Sprintf("%s (expires in %dd)", date, int(remaining.Hours()/24))
+	}
+	return date
+}
+
+// federatedRoleLines lists the roles whose trust policy federates with the
+// provider, each followed by the conditions that restrict who may assume it
+func federatedRoleLines(ctx context.Context, api iam.RoleAPI, providerArn string) []DetailLine {
+	lines := []DetailLine{HeadingLine("Federated Roles")}
+	roles, err := api.ListRoleSummaries(ctx)
+	if err != nil {
+		return append(lines, NoteLine(fmt.Sprintf("Failed to list roles: %v", err)))
+	}
+
+	found := false
+	for _, role := range roles {
+		federates, conditions := iam.FederationConditions(role.TrustPolicy, providerArn)
+		if !federates {
+			continue
+		}
+		found = true
+		lines = append(lines, ItemLine(role.Name, &DetailAction{Open: &OpenResourceMsg{Resource: "roles", ID: role.Name}}))
+		if len(conditions) == 0 {
+			lines = append(lines, NoteLine("    No conditions; any identity from the provider can assume it"))
+		}
+		for _, c := range conditions {
+			lines = append(lines, NoteLine("    "+c))
+		}
+	}
+	if !found {
+		return append(lines, NoteLine("No role trusts this provider"))
+	}
+	return append(lines, TextLine(""), NoteLine("Press Enter to open the selected role"))
+}
+
+func joinOrDash(values []string) string {
+	if len(values) == 0 {
+		return "-"
+	}
+	return strings.Join(values, ", ")
+}
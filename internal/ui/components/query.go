@@ -0,0 +1,264 @@
+package components
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/johnoct/a3s/internal/aws/iam"
+	"github.com/johnoct/a3s/internal/policy"
+	"github.com/johnoct/a3s/internal/ui/styles"
+)
+
+// roleQuery is a parsed "can this role do X?" question
+type roleQuery struct {
+	action   string
+	resource string
+	context  policy.Context
+}
+
+// roleQueryAnsweredMsg carries the answer to a query about the role with
+// roleARN, formatted for the document viewer
+type roleQueryAnsweredMsg struct {
+	roleARN string
+	title   string
+	answer  string
+}
+
+func newQueryInput() textinput.Model {
+	input := textinput.New()
+	input.Placeholder = "s3:GetObject arn:aws:s3:::bucket/key [key=value ...]"
+	input.CharLimit = 512
+	input.Width = 60
+	return input
+}
+
+// parseRoleQuery reads "action [resource] [key=value ...]". The resource
+// defaults to "*"; repeated context keys collect several values.
+func parseRoleQuery(text string) (roleQuery, error) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return roleQuery{}, fmt.Errorf("enter an action, e.g. s3:GetObject")
+	}
+	q := roleQuery{action: fields[0], resource: "*", context: policy.Context{}}
+	if !strings.Contains(q.action, ":") {
+		return q, fmt.Errorf("%q is not an action; use service:Action", q.action)
+	}
+
+	rest := fields[1:]
+	if len(rest) > 0 && !strings.Contains(rest[0], "=") {
+		q.resource, rest = rest[0], rest[1:]
+	}
+	for _, f := range rest {
+		key, value, ok := strings.Cut(f, "=")
+		if !ok || key == "" {
+			return q, fmt.Errorf("%q is not a context key; use key=value", f)
+		}
+		q.context.Set(key, append(q.context.Values(key), value)...)
+	}
+	return q, nil
+}
+
+// startQuery opens the query prompt
+func (m *DetailModel) startQuery() tea.Cmd {
+	if m.roleService == nil {
+		return nil
+	}
+	m.queryMode = true
+	m.queryErr = ""
+	m.queryInput.Focus()
+	return textinput.Blink
+}
+
+// updateQuery handles a key while the query prompt is open
+func (m *DetailModel) updateQuery(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		m.queryMode = false
+		m.queryInput.Blur()
+		return nil
+	case "enter":
+		q, err := parseRoleQuery(m.queryInput.Value())
+		if err != nil {
+			m.queryErr = err.Error()
+			return nil
+		}
+		m.queryMode = false
+		m.queryInput.Blur()
+		return m.runQuery(q)
+	}
+	m.queryErr = ""
+	var cmd tea.Cmd
+	m.queryInput, cmd = m.queryInput.Update(msg)
+	return cmd
+}
+
+// runQuery evaluates the query against every identity policy of the role
+// and its permissions boundary
+func (m *DetailModel) runQuery(q roleQuery) tea.Cmd {
+	if m.querying {
+		return nil
+	}
+	m.querying = true
+	role := m.role
+	roleService := m.roleService
+	documents := m.documents
+	return func() tea.Msg {
+		title := fmt.Sprintf("❓ Can %s do %s?", role.Name, q.action)
+		answer, err := answerRoleQuery(context.Background(), roleService, role, q, documents)
+		if err != nil {
+			answer = fmt.Sprintf("Error evaluating policies: %v", err)
+		}
+		return roleQueryAnsweredMsg{roleARN: role.ARN, title: title, answer: answer}
+	}
+}
+
+// answerRoleQuery fetches and evaluates the role's policies. Service control
+// policies, resource policies and session policies are not known here and
+// are left out.
+func answerRoleQuery(ctx context.Context, api iam.RoleAPI, role *iam.Role, q roleQuery, documents *documentCache) (string, error) {
+	policies, err := documents.rolePolicies(ctx, api, role)
+	if err != nil {
+		return "", err
+	}
+	for i := range policies {
+		if i < len(role.ManagedPolicies) {
+			policies[i].Name += " (managed)"
+		} else {
+			policies[i].Name += " (inline)"
+		}
+	}
+
+	// Keys AWS sets for every request a role makes, unless given explicitly
+	for key, value := range map[string]string{
+		"aws:PrincipalArn":     role.ARN,
+		"aws:PrincipalAccount": accountOf(role.ARN),
+		"aws:PrincipalType":    "AssumedRole",
+	} {
+		if q.context.Values(key) == nil {
+			q.context.Set(key, value)
+		}
+	}
+	req := policy.Request{Principal: role.ARN, Action: q.action, Resource: q.resource, Context: q.context}
+	result := policy.Evaluate(req, policies)
+
+	var boundary *policy.Result
+	boundaryPolicy, err := documents.boundaryPolicy(ctx, api, role)
+	if err != nil {
+		return "", err
+	}
+	if boundaryPolicy != nil {
+		boundaryPolicy.Name += " (boundary)"
+		r := policy.Evaluate(req, []policy.Policy{*boundaryPolicy})
+		boundary = &r
+	}
+
+	return formatQueryAnswer(req, result, boundary, role.PermissionsBoundary), nil
+}
+
+// formatQueryAnswer writes the decision followed by the statements behind
+// it, each with its policy name and source
+func formatQueryAnswer(req policy.Request, result policy.Result, boundary *policy.Result, boundaryInfo *iam.PolicyInfo) string {
+	decision := result.Decision
+	boundaryDenies := boundary != nil && boundary.Decision != policy.Allowed
+	verdict := "ALLOWED"
+	switch {
+	case decision == policy.ExplicitDeny:
+		verdict = "DENIED (explicit deny in an identity policy)"
+	case decision == policy.ImplicitDeny:
+		verdict = "DENIED (no identity policy allows it)"
+	case boundaryDenies && boundary.Decision == policy.ExplicitDeny:
+		verdict = "DENIED (explicit deny in the permissions boundary)"
+	case boundaryDenies:
+		verdict = "DENIED (the permissions boundary does not allow it)"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Decision: %s\n\n", verdict)
+	fmt.Fprintf(&b, "Action:   %s\n", req.Action)
+	fmt.Fprintf(&b, "Resource: %s\n", req.Resource)
+	for _, line := range contextLines(req.Context) {
+		fmt.Fprintf(&b, "Context:  %s\n", line)
+	}
+
+	writeMatches(&b, "Denied by", result.Denies)
+	writeMatches(&b, "Allowed by", result.Allows)
+	writeMatches(&b, "Not applied, conditions not met", result.Unmet)
+
+	if boundary != nil {
+		state := "allows the request"
+		if boundary.Decision == policy.ExplicitDeny {
+			state = "explicitly denies the request"
+		} else if boundary.Decision == policy.ImplicitDeny {
+			state = "does not allow the request"
+		}
+		fmt.Fprintf(&b, "\nPermissions boundary %s %s\n", boundaryInfo.Name, state)
+		writeMatches(&b, "Boundary denied by", boundary.Denies)
+		writeMatches(&b, "Boundary allowed by", boundary.Allows)
+		writeMatches(&b, "Boundary not applied, conditions not met", boundary.Unmet)
+	}
+
+	b.WriteString("\nService control policies, resource policies and session policies are not evaluated.\n")
+	return b.String()
+}
+
+func writeMatches(b *strings.Builder, heading string, matches []policy.Match) {
+	if len(matches) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n%s:\n", heading)
+	for _, m := range matches {
+		label := fmt.Sprintf("statement %d", m.Index+1)
+		if m.Statement.Sid != "" {
+			label += fmt.Sprintf(" (%s)", m.Statement.Sid)
+		}
+		fmt.Fprintf(b, "\n  %s, %s\n", m.Policy, label)
+		for _, line := range strings.Split(indentStatement(m.Statement.Raw), "\n") {
+			fmt.Fprintf(b, "    %s\n", line)
+		}
+	}
+}
+
+// indentStatement pretty-prints a statement's source
+func indentStatement(raw json.RawMessage) string {
+	var v any
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return string(raw)
+	}
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return string(raw)
+	}
+	return string(out)
+}
+
+func contextLines(ctx policy.Context) []string {
+	var lines []string
+	for key, values := range ctx {
+		lines = append(lines, fmt.Sprintf("%s=%s", key, strings.Join(values, ",")))
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+// accountOf returns the account ID in an ARN
+func accountOf(arn string) string {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) < 5 {
+		return ""
+	}
+	return parts[4]
+}
+
+// renderQueryBar draws the query prompt in place of the help line
+func (m *DetailModel) renderQueryBar() string {
+	line := styles.SearchPrompt.Render("can ") + styles.SearchInput.Render(m.queryInput.View())
+	if m.queryErr != "" {
+		line += " " + styles.ErrorStyle.Render(m.queryErr)
+	}
+	return line
+}
@@ -0,0 +1,903 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/johnoct/a3s/internal/aws/iam"
+	"github.com/johnoct/a3s/internal/aws/identity"
+	"github.com/johnoct/a3s/internal/ui/styles"
+)
+
+// ResourceSource supplies the rows and details of one kind of IAM resource to
+// a ResourceModel
+type ResourceSource interface {
+	// Name identifies the resource and matches its ":" command, e.g. "users"
+	Name() string
+	Title() string
+	// Headers are the list column titles; every row has one cell per header
+	Headers() []string
+	List(ctx context.Context) ([]ResourceRow, error)
+	Describe(ctx context.Context, id string) (*ResourceDetail, error)
+}
+
+// ScopedSource is a ResourceSource whose list can be narrowed to a scope,
+// e.g. customer or AWS managed policies. The scope is cycled with "s".
+type ScopedSource interface {
+	ResourceSource
+	Scopes() []string
+	Scope() string
+	SetScope(scope string)
+}
+
+// ResourceRow is one list entry. ID is passed back to Describe.
+type ResourceRow struct {
+	ID    string
+	Cells []string
+}
+
+// ResourceDetail is the tabbed detail view of one resource
+type ResourceDetail struct {
+	Title string
+	Tabs  []DetailTab
+}
+
+// DetailTab is a named page of detail lines
+type DetailTab struct {
+	Name  string
+	Lines []DetailLine
+}
+
+type lineKind int
+
+const (
+	lineText lineKind = iota
+	lineHeading
+	lineLabel
+	lineField
+	lineNote
+	lineItem
+)
+
+// DetailLine is one line of a detail tab. Lines are built with the *Line
+// constructors; item lines with an action can be selected with j/k.
+type DetailLine struct {
+	kind   lineKind
+	label  string
+	text   string
+	action *DetailAction
+}
+
+// DetailAction is what Enter does on a selected item line
+type DetailAction struct {
+	// Title and Document open a document, usually a policy, in the viewer
+	Title    string
+	Document func(ctx context.Context) (string, error)
+	// Open jumps to the detail view of another resource
+	Open *OpenResourceMsg
+}
+
+// OpenResourceMsg asks the App to show the detail view of a resource,
+// possibly of another kind, e.g. a group member's user detail
+type OpenResourceMsg struct {
+	Resource string
+	ID       string
+	// Tab optionally names the detail tab to show instead of the first one
+	Tab string
+}
+
+// ResourceBackMsg asks the App to return to the view a jump came from. Err
+// is set when the jump failed because the target could not be loaded.
+type ResourceBackMsg struct {
+	Err error
+}
+
+// TextLine is plain text; an empty TextLine is a blank line
+func TextLine(text string) DetailLine {
+	return DetailLine{kind: lineText, text: text}
+}
+
+// HeadingLine is a section title followed by a blank line
+func HeadingLine(text string) DetailLine {
+	return DetailLine{kind: lineHeading, text: text}
+}
+
+// LabelLine introduces a group of item lines, e.g. "Managed Policies:"
+func LabelLine(text string) DetailLine {
+	return DetailLine{kind: lineLabel, text: text}
+}
+
+// FieldLine is a "label: value" pair
+func FieldLine(label, value string) DetailLine {
+	return DetailLine{kind: lineField, label: label, text: value}
+}
+
+// NoteLine is muted explanatory text, e.g. "No tags"
+func NoteLine(text string) DetailLine {
+	return DetailLine{kind: lineNote, text: text}
+}
+
+// ItemLine is a bulleted entry; a non-nil action makes it selectable
+func ItemLine(text string, action *DetailAction) DetailLine {
+	return DetailLine{kind: lineItem, text: text, action: action}
+}
+
+// policyLines lists managed and inline policies as items that open the
+// policy document
+func policyLines(managed []iam.PolicyInfo, inline []string,
+	managedDoc func(ctx context.Context, arn string) (string, error),
+	inlineDoc func(ctx context.Context, name string) (string, error)) []DetailLine {
+	lines := []DetailLine{HeadingLine("Attached Policies")}
+	if len(managed) == 0 && len(inline) == 0 {
+		return append(lines, NoteLine("No policies attached"))
+	}
+
+	if len(managed) > 0 {
+		lines = append(lines, LabelLine("Managed Policies:"))
+		for _, p := range managed {
+			lines = append(lines, ItemLine(p.Name, &DetailAction{
+				Title: "Policy Document: " + p.Name,
+				Document: func(ctx context.Context) (string, error) {
+					return managedDoc(ctx, p.ARN)
+				},
+			}))
+		}
+		lines = append(lines, TextLine(""))
+	}
+
+	if len(inline) > 0 {
+		lines = append(lines, LabelLine("Inline Policies:"))
+		for _, name := range inline {
+			lines = append(lines, ItemLine(name, &DetailAction{
+				Title: "Policy Document: " + name,
+				Document: func(ctx context.Context) (string, error) {
+					return inlineDoc(ctx, name)
+				},
+			}))
+		}
+		lines = append(lines, TextLine(""))
+	}
+
+	return append(lines, NoteLine("Press Enter to view the selected policy document"))
+}
+
+func tagLines(tags []iam.Tag) []DetailLine {
+	lines := []DetailLine{HeadingLine("Tags")}
+	if len(tags) == 0 {
+		return append(lines, NoteLine("No tags"))
+	}
+	for _, t := range tags {
+		lines = append(lines, FieldLine(t.Key, t.Value))
+	}
+	return lines
+}
+
+// resourceListedMsg carries the rows of a resource list. seq identifies the
+// request so results of a superseded scope are dropped.
+type resourceListedMsg struct {
+	resource string
+	seq      int
+	rows     []ResourceRow
+	err      error
+}
+
+// resourceDescribedMsg carries the details of one resource. refresh marks a
+// reload of the detail already shown, which keeps the tab and selection.
+type resourceDescribedMsg struct {
+	resource string
+	id       string
+	detail   *ResourceDetail
+	refresh  bool
+	err      error
+}
+
+// resourceDocumentMsg carries a document opened from a detail action
+type resourceDocumentMsg struct {
+	resource string
+	title    string
+	document string
+	err      error
+}
+
+// ResourceModel is a searchable resource list with a tabbed detail view. The
+// resource specific parts come from a ResourceSource, so users, groups and
+// the other IAM resources share one implementation.
+type ResourceModel struct {
+	source  ResourceSource
+	rows    []ResourceRow
+	visible []int
+	cursor  int
+
+	searchMode  bool
+	searchInput textinput.Model
+
+	loading    bool
+	refreshing bool
+	statusErr  error
+	listSeq    int
+
+	// Detail view state
+	detail          *ResourceDetail
+	detailID        string
+	loadingDetail   bool
+	activeTab       int
+	selected        int
+	scrollY         int
+	document        *DocumentViewer
+	loadingDocument bool
+	// jumped is set when the detail was opened from another view; Esc then
+	// returns there instead of to this list
+	jumped bool
+	// openTab names the tab to show once the pending detail has loaded
+	openTab string
+
+	width    int
+	height   int
+	profile  string
+	region   string
+	identity *identity.Identity
+}
+
+// NewResourceModel creates a view over source. Init loads the list.
+func NewResourceModel(source ResourceSource) *ResourceModel {
+	ti := textinput.New()
+	ti.Placeholder = "Filter..."
+	ti.CharLimit = 100
+
+	return &ResourceModel{
+		source:      source,
+		searchInput: ti,
+		width:       80,
+		height:      24,
+	}
+}
+
+// SetContext sets the header information shown above the view
+func (m *ResourceModel) SetContext(profile, region string, id *identity.Identity) {
+	m.profile = profile
+	m.region = region
+	m.identity = id
+	if m.document != nil {
+		m.document.SetContext(profile, region, id)
+	}
+}
+
+// CapturingInput reports whether the filter or document search is reading text
+func (m *ResourceModel) CapturingInput() bool {
+	if m.document != nil {
+		return m.document.CapturingInput()
+	}
+	return m.detail == nil && m.searchMode
+}
+
+// CloseDetail returns from the detail view to the list
+func (m *ResourceModel) CloseDetail() {
+	m.detail = nil
+	m.detailID = ""
+	m.document = nil
+	m.loadingDetail = false
+	m.loadingDocument = false
+	m.jumped = false
+	m.openTab = ""
+}
+
+// Open loads and shows the detail of the resource with the given ID. When
+// jumped is set, Esc from that detail emits ResourceBackMsg instead of
+// returning to the list.
+func (m *ResourceModel) Open(id string, jumped bool) tea.Cmd {
+	m.CloseDetail()
+	m.jumped = jumped
+	m.loadingDetail = true
+	m.detailID = id
+	return m.describe(id, false)
+}
+
+// OpenTab shows the named tab once the detail being opened has loaded
+func (m *ResourceModel) OpenTab(name string) {
+	m.openTab = name
+}
+
+// Current returns the ID of the open detail, empty when the list is shown,
+// and whether it was opened by a jump from another view
+func (m *ResourceModel) Current() (id string, jumped bool) {
+	return m.detailID, m.jumped
+}
+
+func (m *ResourceModel) Init() tea.Cmd {
+	m.loading = true
+	return m.list()
+}
+
+func (m *ResourceModel) list() tea.Cmd {
+	m.listSeq++
+	source, seq := m.source, m.listSeq
+	return func() tea.Msg {
+		rows, err := source.List(context.Background())
+		return resourceListedMsg{resource: source.Name(), seq: seq, rows: rows, err: err}
+	}
+}
+
+// SetScope narrows a ScopedSource to the named scope (matched ignoring case)
+// and reloads the list
+func (m *ResourceModel) SetScope(scope string) (tea.Cmd, error) {
+	scoped, ok := m.source.(ScopedSource)
+	if !ok {
+		return nil, fmt.Errorf("%s cannot be scoped", m.source.Name())
+	}
+	for _, s := range scoped.Scopes() {
+		if strings.EqualFold(s, scope) {
+			scoped.SetScope(s)
+			m.loading = true
+			m.statusErr = nil
+			return m.list(), nil
+		}
+	}
+	return nil, fmt.Errorf("unknown scope %q (expected one of %s)", scope, strings.Join(scoped.Scopes(), ", "))
+}
+
+// nextScope switches a ScopedSource to its next scope and reloads the list
+func (m *ResourceModel) nextScope() tea.Cmd {
+	scoped, ok := m.source.(ScopedSource)
+	if !ok {
+		return nil
+	}
+	scopes := scoped.Scopes()
+	for i, s := range scopes {
+		if s == scoped.Scope() {
+			cmd, _ := m.SetScope(scopes[(i+1)%len(scopes)])
+			return cmd
+		}
+	}
+	return nil
+}
+
+func (m *ResourceModel) describe(id string, refresh bool) tea.Cmd {
+	source := m.source
+	return func() tea.Msg {
+		detail, err := source.Describe(context.Background(), id)
+		return resourceDescribedMsg{resource: source.Name(), id: id, detail: detail, refresh: refresh, err: err}
+	}
+}
+
+// refresh reloads the list and, when open, the displayed detail
+func (m *ResourceModel) refresh() tea.Cmd {
+	if m.refreshing || m.loading {
+		return nil
+	}
+	m.refreshing = true
+	m.statusErr = nil
+	cmds := []tea.Cmd{m.list()}
+	if m.detail != nil {
+		cmds = append(cmds, m.describe(m.detailID, true))
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m *ResourceModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.searchInput.Width = max(20, msg.Width-20)
+		if m.document != nil {
+			m.document.SetSize(m.width, m.height)
+		}
+		return m, nil
+
+	case resourceListedMsg:
+		if msg.resource != m.source.Name() || msg.seq != m.listSeq {
+			return m, nil
+		}
+		m.loading = false
+		m.refreshing = false
+		if msg.err != nil {
+			m.statusErr = msg.err
+			return m, nil
+		}
+		m.setRows(msg.rows)
+		return m, nil
+
+	case resourceDescribedMsg:
+		if msg.resource != m.source.Name() || msg.id != m.detailID {
+			return m, nil
+		}
+		m.loadingDetail = false
+		if msg.err != nil {
+			m.statusErr = msg.err
+			if !msg.refresh {
+				m.detailID = ""
+				if m.jumped {
+					m.jumped = false
+					m.statusErr = nil
+					err := msg.err
+					return m, func() tea.Msg { return ResourceBackMsg{Err: err} }
+				}
+			}
+			return m, nil
+		}
+		m.detail = msg.detail
+		if msg.refresh {
+			m.activeTab = min(m.activeTab, len(m.detail.Tabs)-1)
+			m.selected = min(m.selected, max(0, len(m.actions())-1))
+		} else {
+			m.activeTab, m.selected, m.scrollY = 0, 0, 0
+			for i, tab := range m.detail.Tabs {
+				if tab.Name == m.openTab {
+					m.activeTab = i
+				}
+			}
+			m.openTab = ""
+		}
+		return m, nil
+
+	case resourceDocumentMsg:
+		if msg.resource != m.source.Name() || !m.loadingDocument {
+			return m, nil
+		}
+		m.loadingDocument = false
+		m.openDocument(msg.title, msg.document, msg.err)
+		return m, nil
+	}
+
+	if m.document != nil {
+		closed, cmd := m.document.Update(msg)
+		if closed {
+			m.document = nil
+		}
+		return m, cmd
+	}
+
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		if m.searchMode {
+			var cmd tea.Cmd
+			m.searchInput, cmd = m.searchInput.Update(msg)
+			return m, cmd
+		}
+		return m, nil
+	}
+	if m.detail != nil {
+		return m, m.updateDetail(key)
+	}
+	return m, m.updateList(key)
+}
+
+// setRows swaps in a fresh list, keeping the filter and the selected row
+func (m *ResourceModel) setRows(rows []ResourceRow) {
+	var selectedID string
+	if m.cursor < len(m.visible) {
+		selectedID = m.rows[m.visible[m.cursor]].ID
+	}
+
+	m.rows = rows
+	m.applyFilter()
+	for i, idx := range m.visible {
+		if m.rows[idx].ID == selectedID {
+			m.cursor = i
+			return
+		}
+	}
+}
+
+func (m *ResourceModel) applyFilter() {
+	term := strings.ToLower(m.searchInput.Value())
+	m.visible = m.visible[:0]
+	for i, row := range m.rows {
+		if term == "" || strings.Contains(strings.ToLower(strings.Join(row.Cells, " ")), term) {
+			m.visible = append(m.visible, i)
+		}
+	}
+	if m.cursor >= len(m.visible) {
+		m.cursor = max(0, len(m.visible)-1)
+	}
+}
+
+func (m *ResourceModel) updateList(msg tea.KeyMsg) tea.Cmd {
+	if m.searchMode {
+		switch msg.String() {
+		case "esc":
+			m.searchMode = false
+			m.searchInput.SetValue("")
+			m.applyFilter()
+			return nil
+		case "enter":
+			m.searchMode = false
+			return nil
+		}
+		var cmd tea.Cmd
+		m.searchInput, cmd = m.searchInput.Update(msg)
+		m.applyFilter()
+		return cmd
+	}
+
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return tea.Quit
+	case "j", "down":
+		if m.cursor < len(m.visible)-1 {
+			m.cursor++
+		}
+	case "k", "up":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "g":
+		m.cursor = 0
+	case "G":
+		m.cursor = max(0, len(m.visible)-1)
+	case "/":
+		m.searchMode = true
+		return m.searchInput.Focus()
+	case "enter":
+		if m.cursor < len(m.visible) && !m.loadingDetail {
+			m.loadingDetail = true
+			m.detailID = m.rows[m.visible[m.cursor]].ID
+			return m.describe(m.detailID, false)
+		}
+	case "r":
+		return m.refresh()
+	case "R":
+		return func() tea.Msg { return CommandMsg{Line: "region"} }
+	case "s":
+		return m.nextScope()
+	}
+	return nil
+}
+
+func (m *ResourceModel) updateDetail(msg tea.KeyMsg) tea.Cmd {
+	tabs := len(m.detail.Tabs)
+	switch msg.String() {
+	case "esc", "q":
+		jumped := m.jumped
+		m.CloseDetail()
+		if jumped {
+			return func() tea.Msg { return ResourceBackMsg{} }
+		}
+	case "tab", "l":
+		m.activeTab = (m.activeTab + 1) % tabs
+		m.selected, m.scrollY = 0, 0
+	case "shift+tab", "h":
+		m.activeTab = (m.activeTab - 1 + tabs) % tabs
+		m.selected, m.scrollY = 0, 0
+	case "j", "down":
+		if n := len(m.actions()); n > 0 {
+			m.selected = min(m.selected+1, n-1)
+		} else {
+			m.scrollY++
+		}
+	case "k", "up":
+		if len(m.actions()) > 0 {
+			m.selected = max(0, m.selected-1)
+		} else if m.scrollY > 0 {
+			m.scrollY--
+		}
+	case "g":
+		m.selected, m.scrollY = 0, 0
+	case "G":
+		m.selected = max(0, len(m.actions())-1)
+	case "enter":
+		return m.runAction()
+	case "r":
+		return m.refresh()
+	}
+	return nil
+}
+
+// actions returns the selectable lines of the active tab in display order
+func (m *ResourceModel) actions() []*DetailAction {
+	var actions []*DetailAction
+	for _, line := range m.detail.Tabs[m.activeTab].Lines {
+		if line.action != nil {
+			actions = append(actions, line.action)
+		}
+	}
+	return actions
+}
+
+func (m *ResourceModel) runAction() tea.Cmd {
+	actions := m.actions()
+	if m.selected >= len(actions) || m.loadingDocument {
+		return nil
+	}
+	action := actions[m.selected]
+	if action.Open != nil {
+		open := *action.Open
+		return func() tea.Msg { return open }
+	}
+	if action.Document == nil {
+		return nil
+	}
+
+	m.loadingDocument = true
+	resource := m.source.Name()
+	return func() tea.Msg {
+		doc, err := action.Document(context.Background())
+		return resourceDocumentMsg{resource: resource, title: action.Title, document: doc, err: err}
+	}
+}
+
+// openDocument shows a loaded document, or the error that prevented loading
+// it, in the document viewer
+func (m *ResourceModel) openDocument(title, document string, err error) {
+	if err != nil {
+		document = fmt.Sprintf("Error loading document: %v", err)
+		title = "Error"
+	}
+	m.document = NewDocumentViewer(fmt.Sprintf("📄 %s", title), document, "back to "+strings.ToLower(m.detail.Tabs[m.activeTab].Name))
+	m.document.SetContext(m.profile, m.region, m.identity)
+	m.document.SetSize(m.width, m.height)
+}
+
+// ============================================================================
+// View Rendering
+// ============================================================================
+
+func (m *ResourceModel) View() string {
+	switch {
+	case m.document != nil:
+		return m.document.View()
+	case m.detail != nil:
+		return m.withStatus(m.detailView())
+	case m.loading:
+		return fmt.Sprintf("\n  Loading %s... ⚡\n", m.source.Title())
+	case m.loadingDetail:
+		return "\n  Loading details... ⚡\n"
+	}
+	return m.withStatus(m.listView())
+}
+
+// withStatus appends the refresh indicator or the last error to the help
+// line at the bottom of the view
+func (m *ResourceModel) withStatus(view string) string {
+	switch {
+	case m.refreshing:
+		return view + "  " + styles.LoadingStyle.Render("Refreshing...")
+	case m.loadingDocument:
+		return view + "  " + styles.LoadingStyle.Render("Loading document...")
+	case m.statusErr != nil:
+		return view + "  " + styles.ErrorStyle.Render(truncate(m.statusErr.Error(), max(20, m.width/2)))
+	}
+	return view
+}
+
+func (m *ResourceModel) listView() string {
+	var content strings.Builder
+	var fullView strings.Builder
+
+	fullView.WriteString(styles.RenderHeader(m.profile, m.region, m.identity, m.width))
+	fullView.WriteString("\n")
+
+	name := m.source.Title()
+	if scoped, ok := m.source.(ScopedSource); ok {
+		name += " [" + scoped.Scope() + "]"
+	}
+	title := fmt.Sprintf("%s (%d)", name, len(m.rows))
+	if len(m.visible) != len(m.rows) {
+		title = fmt.Sprintf("%s (%d/%d)", name, len(m.visible), len(m.rows))
+	}
+	fullView.WriteString("   ")
+	fullView.WriteString(styles.TitleStyle.Render(title))
+	fullView.WriteString("\n")
+
+	// Filter bar - always reserve space to prevent layout shifts
+	if m.searchMode {
+		fullView.WriteString(styles.SearchPrompt.Render(" Filter: "))
+		fullView.WriteString(m.searchInput.View())
+	}
+	fullView.WriteString("\n")
+
+	availableWidth := m.width - 8
+	if availableWidth < 80 {
+		availableWidth = 80
+	}
+
+	cells := make([][]string, len(m.rows))
+	for i, row := range m.rows {
+		cells[i] = row.Cells
+	}
+	widths := fitColumns(m.source.Headers(), cells, availableWidth)
+	content.WriteString(styles.ListHeader.Width(availableWidth).Render(formatRow(m.source.Headers(), widths)))
+	content.WriteString("\n")
+
+	// Header (6) + title (2) + filter (1) + border (2) + table header (2) + help (1)
+	visibleHeight := m.height - 14
+	if visibleHeight < 5 {
+		visibleHeight = 5
+	}
+
+	startIdx := 0
+	if m.cursor >= visibleHeight {
+		startIdx = m.cursor - visibleHeight + 1
+	}
+	endIdx := min(startIdx+visibleHeight, len(m.visible))
+
+	for i := startIdx; i < endIdx; i++ {
+		line := truncate(formatRow(m.rows[m.visible[i]].Cells, widths), availableWidth)
+		if i == m.cursor {
+			content.WriteString(styles.SelectedItem.Render(line))
+		} else {
+			content.WriteString(styles.ListItem.Render(line))
+		}
+		content.WriteString("\n")
+	}
+	if len(m.rows) == 0 && m.statusErr == nil {
+		content.WriteString(styles.HelpDesc.Render(" No " + strings.ToLower(m.source.Title()) + " found"))
+		content.WriteString("\n")
+		endIdx++
+	}
+
+	for i := endIdx - startIdx; i < visibleHeight; i++ {
+		content.WriteString(strings.Repeat(" ", availableWidth))
+		content.WriteString("\n")
+	}
+
+	borderedContent := styles.GetMainContainer(m.width, visibleHeight+2).Render(strings.TrimRight(content.String(), "\n"))
+	fullView.WriteString(borderedContent)
+	fullView.WriteString("\n")
+
+	help := []string{
+		styles.HelpKey.Render("j/k") + " " + styles.HelpDesc.Render("up/down"),
+		styles.HelpKey.Render("Enter") + " " + styles.HelpDesc.Render("view"),
+		styles.HelpKey.Render("/") + " " + styles.HelpDesc.Render("filter"),
+		styles.HelpKey.Render("r") + " " + styles.HelpDesc.Render("refresh"),
+	}
+	if _, ok := m.source.(ScopedSource); ok {
+		help = append(help, styles.HelpKey.Render("s")+" "+styles.HelpDesc.Render("scope"))
+	}
+	help = append(help,
+		styles.HelpKey.Render(":")+" "+styles.HelpDesc.Render("command"),
+		styles.HelpKey.Render("q")+" "+styles.HelpDesc.Render("quit"),
+	)
+	fullView.WriteString(styles.HelpStyle.Render(strings.Join(help, " | ")))
+
+	return fullView.String()
+}
+
+func (m *ResourceModel) detailView() string {
+	var content strings.Builder
+	var fullView strings.Builder
+
+	fullView.WriteString(styles.RenderHeader(m.profile, m.region, m.identity, m.width))
+	fullView.WriteString("\n")
+
+	fullView.WriteString("   ")
+	fullView.WriteString(styles.TitleStyle.Render(m.detail.Title))
+	fullView.WriteString("\n")
+
+	var tabs []string
+	for i, tab := range m.detail.Tabs {
+		if i == m.activeTab {
+			tabs = append(tabs, styles.ActiveTab.Render(tab.Name))
+		} else {
+			tabs = append(tabs, styles.InactiveTab.Render(tab.Name))
+		}
+	}
+	fullView.WriteString("   ")
+	fullView.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, tabs...))
+	fullView.WriteString("\n")
+
+	lines, selectedRow := m.renderTab()
+	visibleHeight := calculateVisibleHeight(m.height)
+
+	// Keep the selected item on screen
+	if selectedRow >= 0 {
+		if selectedRow < m.scrollY {
+			m.scrollY = selectedRow
+		} else if selectedRow >= m.scrollY+visibleHeight {
+			m.scrollY = selectedRow - visibleHeight + 1
+		}
+	}
+	m.scrollY = min(m.scrollY, max(0, len(lines)-1))
+
+	endIdx := min(m.scrollY+visibleHeight, len(lines))
+	for i := m.scrollY; i < endIdx; i++ {
+		content.WriteString(lines[i])
+		content.WriteString("\n")
+	}
+
+	availableWidth := m.width - 8
+	if availableWidth < 80 {
+		availableWidth = 80
+	}
+	for i := endIdx - m.scrollY; i < visibleHeight; i++ {
+		content.WriteString(strings.Repeat(" ", availableWidth))
+		content.WriteString("\n")
+	}
+
+	borderedContent := styles.GetMainContainer(m.width, visibleHeight).Render(strings.TrimRight(content.String(), "\n"))
+	fullView.WriteString(borderedContent)
+	fullView.WriteString("\n")
+
+	fullView.WriteString(styles.HelpStyle.Render(strings.Join(m.detailHelp(), " | ")))
+
+	return fullView.String()
+}
+
+// renderTab renders the active tab to screen lines and reports which line
+// holds the selected item, or -1 when the tab has nothing to select
+func (m *ResourceModel) renderTab() ([]string, int) {
+	var s strings.Builder
+	selectedRow := -1
+	actionIndex := 0
+
+	for _, line := range m.detail.Tabs[m.activeTab].Lines {
+		switch line.kind {
+		case lineHeading:
+			s.WriteString(styles.DetailTitle.Render(line.text))
+			s.WriteString("\n")
+		case lineLabel:
+			s.WriteString(styles.DetailLabel.Render(line.text))
+		case lineField:
+			s.WriteString(styles.DetailLabel.Render(line.label + ":"))
+			s.WriteString(" ")
+			s.WriteString(styles.DetailValue.Render(line.text))
+		case lineNote:
+			s.WriteString(styles.HelpDesc.Render(line.text))
+		case lineItem:
+			text := "  • " + line.text
+			if line.action != nil && actionIndex == m.selected {
+				selectedRow = strings.Count(s.String(), "\n")
+				s.WriteString(styles.SelectedItem.Render(text))
+			} else {
+				s.WriteString(styles.ListItem.Render(text))
+			}
+			if line.action != nil {
+				actionIndex++
+			}
+		default:
+			s.WriteString(styles.DetailValue.Render(line.text))
+		}
+		s.WriteString("\n")
+	}
+
+	return strings.Split(strings.TrimRight(s.String(), "\n"), "\n"), selectedRow
+}
+
+func (m *ResourceModel) detailHelp() []string {
+	help := []string{
+		styles.HelpKey.Render("Tab/l") + " " + styles.HelpDesc.Render("next tab"),
+		styles.HelpKey.Render("Shift+Tab/h") + " " + styles.HelpDesc.Render("prev tab"),
+	}
+	if actions := m.actions(); len(actions) > 0 {
+		enter := "view document"
+		if m.selected < len(actions) && actions[m.selected].Open != nil {
+			enter = "open"
+		}
+		help = append(help,
+			styles.HelpKey.Render("j/k")+" "+styles.HelpDesc.Render("navigate"),
+			styles.HelpKey.Render("Enter")+" "+styles.HelpDesc.Render(enter),
+		)
+	} else {
+		help = append(help, styles.HelpKey.Render("j/k")+" "+styles.HelpDesc.Render("scroll"))
+	}
+	return append(help,
+		styles.HelpKey.Render("r")+" "+styles.HelpDesc.Render("refresh"),
+		styles.HelpKey.Render("Esc")+" "+styles.HelpDesc.Render("back"),
+	)
+}
+
+// fitColumns sizes every column to its widest cell; the last column takes
+// whatever space remains
+func fitColumns(headers []string, rows [][]string, availableWidth int) []int {
+	widths := make([]int, len(headers))
+	for i, h := range headers {
+		widths[i] = lipgloss.Width(h)
+	}
+	for _, cells := range rows {
+		for i := 0; i < len(cells) && i < len(widths); i++ {
+			widths[i] = max(widths[i], lipgloss.Width(cells[i]))
+		}
+	}
+
+	used := 0
+	for i := range widths[:len(widths)-1] {
+		widths[i] = max(min(widths[i]+2, 48), 5)
+		used += widths[i] + 1
+	}
+	widths[len(widths)-1] = max(10, availableWidth-used)
+	return widths
+}
@@ -0,0 +1,121 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/johnoct/a3s/internal/aws/iam"
+	"github.com/johnoct/a3s/internal/policy"
+)
+
+// documentCache shares policy documents between the analyses of one role,
+// and managed policy documents between the lint jobs of different roles,
+// since most accounts attach the same few policies widely. A nil cache
+// fetches every document.
+type documentCache struct {
+	mu   sync.Mutex
+	docs map[string]string
+}
+
+// document returns the document cached under key, fetching it on a miss
+func (c *documentCache) document(key string, fetch func() (string, error)) (string, error) {
+	if c == nil {
+		return fetch()
+	}
+	c.mu.Lock()
+	doc, ok := c.docs[key]
+	c.mu.Unlock()
+	if ok {
+		return doc, nil
+	}
+	doc, err := fetch()
+	if err != nil {
+		return "", err
+	}
+	c.mu.Lock()
+	if c.docs == nil {
+		c.docs = make(map[string]string)
+	}
+	c.docs[key] = doc
+	c.mu.Unlock()
+	return doc, nil
+}
+
+func (c *documentCache) managedPolicy(ctx context.Context, api iam.RoleAPI, policyArn string) (string, error) {
+	return c.document(policyArn, func() (string, error) {
+		return api.GetManagedPolicyDocument(ctx, policyArn)
+	})
+}
+
+func (c *documentCache) inlinePolicy(ctx context.Context, api iam.RoleAPI, roleName, policyName string) (string, error) {
+	return c.document("inline/"+roleName+"/"+policyName, func() (string, error) {
+		return api.GetInlinePolicy(ctx, roleName, policyName)
+	})
+}
+
+// forgetRole drops the cached inline policies of a role so they are fetched
+// again
+func (c *documentCache) forgetRole(roleName string) {
+	if c == nil {
+		return
+	}
+	prefix := "inline/" + roleName + "/"
+	c.mu.Lock()
+	for key := range c.docs {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.docs, key)
+		}
+	}
+	c.mu.Unlock()
+}
+
+// rolePolicies fetches and parses every identity policy of a role: the
+// managed policies first, then the inline ones, each in the role's order
+// and named as attached. role must carry its details, as returned by
+// GetRoleDetails.
+func (c *documentCache) rolePolicies(ctx context.Context, api iam.RoleAPI, role *iam.Role) ([]policy.Policy, error) {
+	var policies []policy.Policy
+	add := func(name, document string, err error) error {
+		if err == nil {
+			var doc *policy.Document
+			if doc, err = policy.Parse(document); err == nil {
+				policies = append(policies, policy.Policy{Name: name, Document: doc})
+				return nil
+			}
+		}
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	for _, p := range role.ManagedPolicies {
+		document, err := c.managedPolicy(ctx, api, p.ARN)
+		if err := add(p.Name, document, err); err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range role.InlinePolicies {
+		document, err := c.inlinePolicy(ctx, api, role.Name, name)
+		if err := add(name, document, err); err != nil {
+			return nil, err
+		}
+	}
+	return policies, nil
+}
+
+// boundaryPolicy fetches and parses the role's permissions boundary, nil
+// when it has none
+func (c *documentCache) boundaryPolicy(ctx context.Context, api iam.RoleAPI, role *iam.Role) (*policy.Policy, error) {
+	boundary := role.PermissionsBoundary
+	if boundary == nil {
+		return nil, nil
+	}
+	document, err := c.managedPolicy(ctx, api, boundary.ARN)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", boundary.Name, err)
+	}
+	doc, err := policy.Parse(document)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", boundary.Name, err)
+	}
+	return &policy.Policy{Name: boundary.Name, Document: doc}, nil
+}
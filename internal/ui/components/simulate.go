@@ -0,0 +1,216 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/johnoct/a3s/internal/aws/iam"
+	"github.com/johnoct/a3s/internal/ui/styles"
+)
+
+// simulationDoneMsg carries the decision table of a simulation run against
+// the role with roleARN
+type simulationDoneMsg struct {
+	roleARN    string
+	title      string
+	report     string
+	simulation iam.Simulation
+}
+
+func newSimulationInput() textinput.Model {
+	input := textinput.New()
+	input.Placeholder = "s3:GetObject,s3:PutObject arn:aws:s3:::bucket/* [key=value,value ...]"
+	input.CharLimit = 1024
+	input.Width = 60
+	return input
+}
+
+// parseSimulation reads "actions [resources] [key=values ...]", where
+// actions, resources and a key's values are comma-separated. Resources
+// default to "*"; context key types are inferred from their values.
+func parseSimulation(text string) (iam.Simulation, error) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return iam.Simulation{}, fmt.Errorf("enter actions, e.g. s3:GetObject,s3:PutObject")
+	}
+	var sim iam.Simulation
+	for _, action := range splitList(fields[0]) {
+		if !strings.Contains(action, ":") {
+			return sim, fmt.Errorf("%q is not an action; use service:Action", action)
+		}
+		sim.Actions = append(sim.Actions, action)
+	}
+
+	rest := fields[1:]
+	if len(rest) > 0 && !strings.Contains(rest[0], "=") {
+		sim.Resources, rest = splitList(rest[0]), rest[1:]
+	}
+	for _, f := range rest {
+		key, value, ok := strings.Cut(f, "=")
+		if !ok || key == "" {
+			return sim, fmt.Errorf("%q is not a context key; use key=value", f)
+		}
+		values := splitList(value)
+		sim.Context = append(sim.Context, iam.ContextEntry{Key: key, Type: iam.InferContextType(values), Values: values})
+	}
+	return sim, nil
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// LastSimulation returns the simulation last run against this role, nil
+// when none has been
+func (m *DetailModel) LastSimulation() *iam.Simulation {
+	return m.lastSimulation
+}
+
+// startSimulation opens the simulation prompt
+func (m *DetailModel) startSimulation() tea.Cmd {
+	if m.roleService == nil {
+		return nil
+	}
+	m.simulateMode = true
+	m.simulateErr = ""
+	m.simulateInput.Focus()
+	return textinput.Blink
+}
+
+// updateSimulation handles a key while the simulation prompt is open
+func (m *DetailModel) updateSimulation(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		m.simulateMode = false
+		m.simulateInput.Blur()
+		return nil
+	case "enter":
+		sim, err := parseSimulation(m.simulateInput.Value())
+		if err != nil {
+			m.simulateErr = err.Error()
+			return nil
+		}
+		m.simulateMode = false
+		m.simulateInput.Blur()
+		return m.Simulate(sim)
+	}
+	m.simulateErr = ""
+	var cmd tea.Cmd
+	m.simulateInput, cmd = m.simulateInput.Update(msg)
+	return cmd
+}
+
+// Simulate runs a simulation against the role with the IAM policy
+// simulator and shows the decision table when it returns
+func (m *DetailModel) Simulate(sim iam.Simulation) tea.Cmd {
+	if m.simulating || m.roleService == nil {
+		return nil
+	}
+	m.simulating = true
+	role := m.role
+	roleService := m.roleService
+	return func() tea.Msg {
+		title := fmt.Sprintf("🧪 Simulation: %s", role.Name)
+		results, err := roleService.SimulatePrincipalPolicy(context.Background(), role.ARN, sim)
+		report := ""
+		if err != nil {
+			report = fmt.Sprintf("Error running the policy simulator: %v", err)
+		} else {
+			report = formatSimulation(sim, results)
+		}
+		return simulationDoneMsg{roleARN: role.ARN, title: title, report: report, simulation: sim}
+	}
+}
+
+// formatSimulation writes the decision table followed by the statements and
+// missing context keys behind each decision
+func formatSimulation(sim iam.Simulation, results []iam.SimulationResult) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Actions:   %s\n", strings.Join(sim.Actions, ", "))
+	resources := sim.Resources
+	if len(resources) == 0 {
+		resources = []string{"*"}
+	}
+	fmt.Fprintf(&b, "Resources: %s\n", strings.Join(resources, ", "))
+	for _, e := range sim.Context {
+		fmt.Fprintf(&b, "Context:   %s=%s (%s)\n", e.Key, strings.Join(e.Values, ","), e.Type)
+	}
+	b.WriteString("\n")
+
+	allowed := 0
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ACTION\tRESOURCE\tDECISION\tBOUNDARY")
+	for _, r := range results {
+		boundary := "-"
+		if r.AllowedByBoundary != nil {
+			boundary = "denies"
+			if *r.AllowedByBoundary {
+				boundary = "allows"
+			}
+		}
+		if r.Allowed() {
+			allowed++
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Action, r.Resource, simulationDecision(r.Decision), boundary)
+	}
+	w.Flush()
+	fmt.Fprintf(&b, "\n%d of %d allowed\n", allowed, len(results))
+
+	for _, r := range results {
+		if len(r.Statements) == 0 && len(r.MissingContext) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n%s on %s: %s\n", r.Action, r.Resource, simulationDecision(r.Decision))
+		for _, st := range r.Statements {
+			label := fmt.Sprintf("%s (%s)", st.PolicyID, st.PolicyType)
+			if st.StartLine > 0 {
+				label += fmt.Sprintf(", lines %d-%d", st.StartLine, st.EndLine)
+			}
+			fmt.Fprintf(&b, "\n  %s\n", label)
+			if st.Source == "" {
+				fmt.Fprintf(&b, "    Statement not found in the role's %s policies\n", st.PolicyType)
+				continue
+			}
+			for _, line := range strings.Split(st.Source, "\n") {
+				fmt.Fprintf(&b, "    %s\n", line)
+			}
+		}
+		if len(r.MissingContext) > 0 {
+			fmt.Fprintf(&b, "\n  Missing context keys: %s\n", strings.Join(r.MissingContext, ", "))
+		}
+	}
+
+	b.WriteString("\nService control policies, resource policies and session policies are not simulated.\n")
+	return b.String()
+}
+
+func simulationDecision(decision string) string {
+	switch decision {
+	case "allowed":
+		return "ALLOWED"
+	case "explicitDeny":
+		return "DENIED (explicit)"
+	case "implicitDeny":
+		return "DENIED (implicit)"
+	}
+	return decision
+}
+
+// renderSimulationBar draws the simulation prompt in place of the help line
+func (m *DetailModel) renderSimulationBar() string {
+	line := styles.SearchPrompt.Render("simulate ") + styles.SearchInput.Render(m.simulateInput.View())
+	if m.simulateErr != "" {
+		line += " " + styles.ErrorStyle.Render(m.simulateErr)
+	}
+	return line
+}
@@ -0,0 +1,107 @@
+package components
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/johnoct/a3s/internal/aws/iam"
+)
+
+// sortColumn identifies the column the role list is ordered by
+type sortColumn int
+
+const (
+	// sortNone keeps the order returned by ListRoles
+	sortNone sortColumn = iota
+	sortName
+	sortCreated
+	sortLastUsed
+)
+
+// sortKeys maps the sort keybindings to their columns
+var sortKeys = map[string]sortColumn{
+	"N": sortName,
+	"C": sortCreated,
+	"L": sortLastUsed,
+}
+
+// toggleSort orders by column, flipping the direction when the list is
+// already sorted by it. The cursor stays on the selected role.
+func (m *ListModel) toggleSort(column sortColumn) {
+	if m.sortBy == column {
+		m.sortDesc = !m.sortDesc
+	} else {
+		m.sortBy = column
+		m.sortDesc = false
+	}
+
+	var selectedARN string
+	if m.cursor < len(m.filteredRoles) {
+		selectedARN = m.filteredRoles[m.cursor].ARN
+	}
+
+	m.sortRoles()
+	m.filterRoles()
+
+	for i, role := range m.filteredRoles {
+		if role.ARN == selectedARN {
+			m.cursor = i
+			return
+		}
+	}
+}
+
+// sortRoles orders m.roles by the active sort column. Filtering preserves
+// this order, so filtered views stay sorted too.
+func (m *ListModel) sortRoles() {
+	if m.sortBy == sortNone {
+		return
+	}
+
+	less := func(a, b iam.Role) int {
+		switch m.sortBy {
+		case sortCreated:
+			return a.CreateDate.Compare(b.CreateDate)
+		case sortLastUsed:
+			return lastUsedTime(a).Compare(lastUsedTime(b))
+		default:
+			return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+		}
+	}
+
+	// Sort a copy: m.filteredRoles may share the backing array
+	roles := append([]iam.Role(nil), m.roles...)
+	sort.SliceStable(roles, func(i, j int) bool {
+		c := less(roles[i], roles[j])
+		if c == 0 {
+			// Break ties by name so equal dates keep a predictable order
+			return roles[i].Name < roles[j].Name
+		}
+		if m.sortDesc {
+			return c > 0
+		}
+		return c < 0
+	})
+	m.roles = roles
+}
+
+// lastUsedTime treats roles that were never used as the oldest
+func lastUsedTime(role iam.Role) time.Time {
+	if role.LastUsed == nil {
+		return time.Time{}
+	}
+	return *role.LastUsed
+}
+
+// sortIndicator returns the header label for column with an arrow when the
+// list is sorted by it
+func (m *ListModel) sortIndicator(label string, column sortColumn) string {
+	if column == sortNone || m.sortBy != column {
+		return label
+	}
+	if m.sortDesc {
+		return label + " ▼"
+	}
+	return label + " ▲"
+}
@@ -0,0 +1,208 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/johnoct/a3s/internal/aws/iam"
+)
+
+// UserSource lists IAM users for a ResourceModel
+type UserSource struct {
+	api iam.UserAPI
+}
+
+func NewUserSource(api iam.UserAPI) *UserSource {
+	return &UserSource{api: api}
+}
+
+func (s *UserSource) Name() string  { return "users" }
+func (s *UserSource) Title() string { return "IAM Users" }
+
+func (s *UserSource) Headers() []string {
+	return []string{"User Name", "Created", "Password Last Used", "Oldest Active Key", "Path"}
+}
+
+func (s *UserSource) List(ctx context.Context) ([]ResourceRow, error) {
+	users, err := s.api.ListUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]ResourceRow, len(users))
+	for i, u := range users {
+		rows[i] = ResourceRow{
+			ID: u.Name,
+			Cells: []string{
+				u.Name,
+				u.CreateDate.Format("2006-01-02"),
+				formatDate(u.PasswordLastUsed, "Never"),
+				oldestKeyAge(&u),
+				u.Path,
+			},
+		}
+	}
+	return rows, nil
+}
+
+func (s *UserSource) Describe(ctx context.Context, name string) (*ResourceDetail, error) {
+	user, err := s.api.GetUserDetails(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ResourceDetail{
+		Title: fmt.Sprintf("👤 User: %s", user.Name),
+		Tabs: []DetailTab{
+			{Name: "Overview", Lines: userOverview(user)},
+			{Name: "Groups", Lines: userGroups(user)},
+			{Name: "Policies", Lines: s.userPolicies(user)},
+			{Name: "Access Keys", Lines: userAccessKeys(user)},
+			{Name: "MFA", Lines: userMFADevices(user)},
+			{Name: "Tags", Lines: tagLines(user.Tags)},
+		},
+	}, nil
+}
+
+// staleKeyAge is the age past which an active access key is flagged stale,
+// the rotation period of the CIS AWS Foundations Benchmark
+const staleKeyAge = 90 * 24 * time.Hour
+
+// oldestKeyAge renders the age of a user's oldest active access key, flagged
+// when stale, "-" without active keys and "Unknown" when they could not be
+// listed
+func oldestKeyAge(user *iam.User) string {
+	if user.AccessKeysErr != nil {
+		return "Unknown"
+	}
+	var oldest *iam.AccessKey
+	for i, k := range user.AccessKeys {
+		if k.Status == "Active" && (oldest == nil || k.CreateDate.Before(oldest.CreateDate)) {
+			oldest = &user.AccessKeys[i]
+		}
+	}
+	if oldest == nil {
+		return "-"
+	}
+	age := formatAge(oldest.CreateDate)
+	if time.Since(oldest.CreateDate) > staleKeyAge {
+		age += " (stale)"
+	}
+	return age
+}
+
+func userOverview(user *iam.User) []DetailLine {
+	console := "Disabled"
+	switch {
+	case user.LoginProfileErr != nil:
+		console = "Unknown"
+	case user.LoginProfile != nil:
+		console = "Enabled since " + user.LoginProfile.CreateDate.Format("2006-01-02")
+		if user.LoginProfile.PasswordResetRequired {
+			console += " (password reset required)"
+		}
+	}
+
+	mfa := "Not enabled"
+	if len(user.MFADevices) > 0 {
+		mfa = fmt.Sprintf("%d device(s)", len(user.MFADevices))
+	}
+
+	active := 0
+	for _, k := range user.AccessKeys {
+		if k.Status == "Active" {
+			active++
+		}
+	}
+	keys := fmt.Sprintf("%d active, %d total", active, len(user.AccessKeys))
+	if user.AccessKeysErr != nil {
+		keys = "Unknown"
+	}
+
+	return []DetailLine{
+		HeadingLine("User Information"),
+		FieldLine("ARN", user.ARN),
+		FieldLine("User ID", user.UserID),
+		FieldLine("Path", user.Path),
+		FieldLine("Created", user.CreateDate.Format("2006-01-02 15:04:05")),
+		FieldLine("Console Access", console),
+		FieldLine("Password Last Used", formatDate(user.PasswordLastUsed, "Never")),
+		FieldLine("MFA", mfa),
+		FieldLine("Access Keys", keys),
+		FieldLine("Oldest Active Key", oldestKeyAge(user)),
+	}
+}
+
+func userGroups(user *iam.User) []DetailLine {
+	lines := []DetailLine{HeadingLine("Group Memberships")}
+	if len(user.Groups) == 0 {
+		return append(lines, NoteLine("Not a member of any group"))
+	}
+	for _, g := range user.Groups {
+		lines = append(lines, ItemLine(g, &DetailAction{Open: &OpenResourceMsg{Resource: "groups", ID: g}}))
+	}
+	return append(lines, TextLine(""), NoteLine("Press Enter to open the selected group"))
+}
+
+func (s *UserSource) userPolicies(user *iam.User) []DetailLine {
+	return policyLines(user.ManagedPolicies, user.InlinePolicies, s.api.GetManagedPolicyDocument,
+		func(ctx context.Context, name string) (string, error) {
+			return s.api.GetUserInlinePolicy(ctx, user.Name, name)
+		})
+}
+
+func userAccessKeys(user *iam.User) []DetailLine {
+	lines := []DetailLine{HeadingLine("Access Keys")}
+	if user.AccessKeysErr != nil {
+		return append(lines, NoteLine(fmt.Sprintf("Access keys unknown: %v", user.AccessKeysErr)))
+	}
+	if len(user.AccessKeys) == 0 {
+		return append(lines, NoteLine("No access keys"))
+	}
+
+	for i, k := range user.AccessKeys {
+		if i > 0 {
+			lines = append(lines, TextLine(""))
+		}
+		lastUsed := formatDate(k.LastUsed, "Never")
+		if k.LastUsed != nil {
+			lastUsed += fmt.Sprintf(" (%s in %s)", k.LastUsedService, k.LastUsedRegion)
+		}
+		lines = append(lines,
+			LabelLine(k.ID),
+			FieldLine("  Status", k.Status),
+			FieldLine("  Created", fmt.Sprintf("%s (%s old)", k.CreateDate.Format("2006-01-02"), formatAge(k.CreateDate))),
+			FieldLine("  Last Used", lastUsed),
+		)
+	}
+	return lines
+}
+
+func userMFADevices(user *iam.User) []DetailLine {
+	lines := []DetailLine{HeadingLine("MFA Devices")}
+	if len(user.MFADevices) == 0 {
+		return append(lines, NoteLine("No MFA devices"))
+	}
+	for _, d := range user.MFADevices {
+		lines = append(lines, ItemLine(fmt.Sprintf("%s (enabled %s)", d.SerialNumber, d.EnableDate.Format("2006-01-02")), nil))
+	}
+	return lines
+}
+
+// formatDate formats an optional timestamp as a date, or returns none
+func formatDate(t *time.Time, none string) string {
+	if t == nil || t.IsZero() {
+		return none
+	}
+	return t.Format("2006-01-02")
+}
+
+// formatAge renders the time since t in whole days, e.g. "412 days"
+func formatAge(t time.Time) string {
+	days := int(time.Since(t).Hours() / 24)
+	if days == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", days)
+}
@@ -0,0 +1,134 @@
+package components
+
+import (
+	"sort"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/johnoct/a3s/internal/aws/iam"
+)
+
+// MinWatchInterval is the shortest polling interval accepted by watch mode
+const MinWatchInterval = 2 * time.Second
+
+// delta classifies how a role changed between two watch cycles
+type delta int
+
+const (
+	deltaNone delta = iota
+	deltaAdded
+	deltaModified
+	deltaDeleted
+)
+
+// watchTickMsg triggers a watch cycle. seq ties the tick to the watch
+// session that scheduled it so stale ticks are dropped after a toggle.
+type watchTickMsg struct {
+	seq int
+}
+
+// SetWatch starts polling the role list every interval, or stops polling
+// when interval is zero
+func (m *ListModel) SetWatch(interval time.Duration) tea.Cmd {
+	m.watchSeq++
+	m.watchInterval = interval
+	m.dropGhosts()
+	m.deltas = nil
+	if interval <= 0 {
+		m.snapshot = nil
+		return nil
+	}
+
+	// The current list is the baseline for the first diff
+	m.snapshot = snapshotRoles(m.roles)
+	return m.scheduleWatch()
+}
+
+// WatchInterval returns the active polling interval, or zero when not watching
+func (m *ListModel) WatchInterval() time.Duration {
+	return m.watchInterval
+}
+
+func (m *ListModel) scheduleWatch() tea.Cmd {
+	seq := m.watchSeq
+	return tea.Tick(m.watchInterval, func(time.Time) tea.Msg {
+		return watchTickMsg{seq: seq}
+	})
+}
+
+func (m *ListModel) handleWatchTick(msg watchTickMsg) tea.Cmd {
+	if msg.seq != m.watchSeq || m.watchInterval <= 0 {
+		return nil
+	}
+	return tea.Batch(m.refresh(false), m.scheduleWatch())
+}
+
+// applyDeltas diffs a freshly listed set of roles against the previous
+// snapshot by ARN. Deleted roles are kept as ghost rows for one cycle.
+func (m *ListModel) applyDeltas(roles []iam.Role) []iam.Role {
+	if m.snapshot == nil {
+		return roles
+	}
+
+	m.deltas = make(map[string]delta)
+	current := snapshotRoles(roles)
+
+	for _, role := range roles {
+		prev, ok := m.snapshot[role.ARN]
+		switch {
+		case !ok:
+			m.deltas[role.ARN] = deltaAdded
+		case roleChanged(prev, role):
+			m.deltas[role.ARN] = deltaModified
+		}
+	}
+
+	var ghosts []iam.Role
+	for _, prev := range m.snapshot {
+		if _, ok := current[prev.ARN]; !ok {
+			m.deltas[prev.ARN] = deltaDeleted
+			ghosts = append(ghosts, prev)
+		}
+	}
+	sort.Slice(ghosts, func(i, j int) bool {
+		return ghosts[i].Name < ghosts[j].Name
+	})
+	withGhosts := append(append([]iam.Role(nil), roles...), ghosts...)
+
+	m.snapshot = current
+	return withGhosts
+}
+
+// dropGhosts removes the rows of roles deleted in the last watch cycle, so
+// they neither linger once watch stops nor join the baseline of a new watch
+func (m *ListModel) dropGhosts() {
+	m.reorder(func() {
+		roles := make([]iam.Role, 0, len(m.roles))
+		for _, role := range m.roles {
+			if m.deltas[role.ARN] != deltaDeleted {
+				roles = append(roles, role)
+			}
+		}
+		m.roles = roles
+	})
+}
+
+// roleChanged reports whether a role differs in a way worth highlighting
+func roleChanged(a, b iam.Role) bool {
+	return a.Description != b.Description || !sameTime(a.LastUsed, b.LastUsed)
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func snapshotRoles(roles []iam.Role) map[string]iam.Role {
+	snapshot := make(map[string]iam.Role, len(roles))
+	for _, role := range roles {
+		snapshot[role.ARN] = role
+	}
+	return snapshot
+}
@@ -0,0 +1,108 @@
+package components
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/johnoct/a3s/internal/aws/iam"
+)
+
+func TestSetWatchDropsGhosts(t *testing.T) {
+	roles := []iam.Role{
+		{Name: "app", ARN: "arn:aws:iam::123456789012:role/app"},
+		{Name: "old", ARN: "arn:aws:iam::123456789012:role/old"},
+	}
+	m := NewListModel(roles, "test", "us-east-1")
+	m.SetWatch(MinWatchInterval)
+	m.applyRefresh(roles[:1])
+	if len(m.roles) != 2 || m.deltas[roles[1].ARN] != deltaDeleted {
+		t.Fatalf("after a watch cycle roles = %v, want the deleted role kept as a ghost row", m.roles)
+	}
+
+	tests := []struct {
+		name     string
+		interval time.Duration
+	}{
+		{"stop watching", 0},
+		{"watch again", MinWatchInterval},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := m
+			m.roles = append([]iam.Role(nil), m.roles...)
+			m.deltas = map[string]delta{roles[1].ARN: deltaDeleted}
+			m.SetWatch(tt.interval)
+			if len(m.roles) != 1 || len(m.filteredRoles) != 1 || m.roles[0].ARN != roles[0].ARN {
+				t.Errorf("roles = %v, want only %s", m.roles, roles[0].Name)
+			}
+			if _, ok := m.snapshot[roles[1].ARN]; ok {
+				t.Errorf("snapshot still holds the deleted role")
+			}
+		})
+	}
+}
+
+// runCmd runs cmd and feeds its messages back into the list until no
+// command is left, leaving out timers
+func runCmd(t *testing.T, m ListModel, cmd tea.Cmd) ListModel {
+	t.Helper()
+	if cmd == nil {
+		return m
+	}
+	switch msg := cmd().(type) {
+	case tea.BatchMsg:
+		for _, c := range msg {
+			m = runCmd(t, m, c)
+		}
+	case spinner.TickMsg, watchTickMsg, nil:
+	default:
+		model, next := m.Update(msg)
+		m = runCmd(t, model.(ListModel), next)
+	}
+	return m
+}
+
+func TestWatchFetchesLastUseEachCycle(t *testing.T) {
+	arn := "arn:aws:iam::123456789012:role/app"
+	backend := func(lastUsed time.Time) *iam.FixtureBackend {
+		b, err := iam.NewFixtureBackend(&iam.Fixture{Roles: []iam.FixtureRole{
+			{Name: "app", ARN: arn, LastUsed: &lastUsed, LastUsedRegion: "us-east-1"},
+		}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+	first := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	second := first.Add(time.Hour)
+
+	api := backend(first)
+	roles, _ := api.ListRoles(context.Background())
+	m := NewListModel(roles, "test", "us-east-1")
+	m.SetRoleService(api)
+	m = runCmd(t, m, m.startEnrichment())
+	m.SetWatch(MinWatchInterval)
+
+	tests := []struct {
+		name      string
+		lastUsed  time.Time
+		wantDelta delta
+	}{
+		{"unchanged", first, deltaNone},
+		{"used since", second, deltaModified},
+		{"unchanged again", second, deltaNone},
+	}
+	for _, tt := range tests {
+		m.SetRoleService(backend(tt.lastUsed))
+		m = runCmd(t, m, m.refresh(false))
+		if got := m.deltas[arn]; got != tt.wantDelta {
+			t.Errorf("%s: delta = %v, want %v", tt.name, got, tt.wantDelta)
+		}
+		if got := m.roles[0].LastUsed; got == nil || !got.Equal(tt.lastUsed) {
+			t.Errorf("%s: last used = %v, want %v", tt.name, got, tt.lastUsed)
+		}
+	}
+}
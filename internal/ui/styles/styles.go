@@ -9,161 +9,252 @@ import (
 )
 
 var (
-	// Colors
-	primaryColor   = lipgloss.Color("#FF9500") // AWS Orange
-	secondaryColor = lipgloss.Color("#232F3E") // AWS Dark Blue
-	accentColor    = lipgloss.Color("#146EB4") // AWS Light Blue
-	successColor   = lipgloss.Color("#00C853")
-	warningColor   = lipgloss.Color("#FFA000")
-	errorColor     = lipgloss.Color("#D32F2F")
-	mutedColor     = lipgloss.Color("#666666")
-	highlightColor = lipgloss.Color("#FFE082")
+	// Colors, set from the active theme
+	primaryColor   lipgloss.Color
+	secondaryColor lipgloss.Color
+	accentColor    lipgloss.Color
+	successColor   lipgloss.Color
+	warningColor   lipgloss.Color
+	errorColor     lipgloss.Color
+	mutedColor     lipgloss.Color
+	highlightColor lipgloss.Color
+	textColor      lipgloss.Color
+	// mono replaces colour highlights with reverse video
+	mono bool
+)
 
+// Styles, rebuilt by ApplyTheme
+var (
+	BaseStyle          lipgloss.Style
+	TitleStyle         lipgloss.Style
+	HeaderStyle        lipgloss.Style
+	ListHeader         lipgloss.Style
+	ListItem           lipgloss.Style
+	SelectedItem       lipgloss.Style
+	DeltaAdded         lipgloss.Style
+	DeltaModified      lipgloss.Style
+	DeltaDeleted       lipgloss.Style
+	StatusBar          lipgloss.Style
+	StatusKey          lipgloss.Style
+	StatusValue        lipgloss.Style
+	HelpStyle          lipgloss.Style
+	HelpKey            lipgloss.Style
+	HelpDesc           lipgloss.Style
+	DetailTitle        lipgloss.Style
+	DetailLabel        lipgloss.Style
+	DetailValue        lipgloss.Style
+	CodeBlock          lipgloss.Style
+	SearchPrompt       lipgloss.Style
+	SearchInput        lipgloss.Style
+	SearchMatch        lipgloss.Style
+	SearchCurrentMatch lipgloss.Style
+	SearchInfo         lipgloss.Style
+	ActiveTab          lipgloss.Style
+	InactiveTab        lipgloss.Style
+	ErrorStyle         lipgloss.Style
+	LoadingStyle       lipgloss.Style
+	MainContainer      lipgloss.Style
+	ASCIIArtStyle      lipgloss.Style
+	HeaderKey          lipgloss.Style
+	HeaderValue        lipgloss.Style
+)
+
+func init() {
+	ApplyTheme(DefaultTheme)
+}
+
+// buildStyles derives every style from the current colors
+func buildStyles() {
 	// Base styles
 	BaseStyle = lipgloss.NewStyle()
 
 	// Title and header styles
 	TitleStyle = BaseStyle.
-			Bold(true).
-			Foreground(primaryColor).
-			MarginBottom(1)
+		Bold(true).
+		Foreground(primaryColor).
+		MarginBottom(1)
 
 	HeaderStyle = BaseStyle.
-			Bold(true).
-			Foreground(lipgloss.Color("#FFFFFF")).
-			Background(secondaryColor).
-			Padding(0, 1)
+		Bold(true).
+		Foreground(textColor).
+		Background(secondaryColor).
+		Padding(0, 1)
 
 	// List styles
 	ListHeader = BaseStyle.
-			Bold(true).
-			Foreground(accentColor).
-			BorderStyle(lipgloss.NormalBorder()).
-			BorderBottom(true).
-			BorderForeground(mutedColor)
+		Bold(true).
+		Foreground(accentColor).
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderBottom(true).
+		BorderForeground(mutedColor)
 
 	ListItem = BaseStyle.
-			PaddingLeft(1)
+		PaddingLeft(1)
 
 	SelectedItem = BaseStyle.
-			Foreground(lipgloss.Color("#000000")).
-			Background(highlightColor).
-			PaddingLeft(1). // Same padding as ListItem for alignment
-			Bold(true)
+		Foreground(lipgloss.Color("#000000")).
+		Background(highlightColor).
+		PaddingLeft(1). // Same padding as ListItem for alignment
+		Bold(true)
+
+	// Watch mode delta styles (k9s-like change colouring)
+	DeltaAdded = ListItem.
+		Foreground(successColor)
+
+	DeltaModified = ListItem.
+		Foreground(warningColor)
+
+	DeltaDeleted = ListItem.
+		Foreground(errorColor).
+		Strikethrough(true)
 
 	// Status bar styles
 	StatusBar = BaseStyle.
-			Foreground(lipgloss.Color("#FFFFFF")).
-			Background(secondaryColor)
+		Foreground(textColor).
+		Background(secondaryColor)
 
 	StatusKey = BaseStyle.
-			Bold(true).
-			Foreground(primaryColor).
-			Background(secondaryColor).
-			Padding(0, 1)
+		Bold(true).
+		Foreground(primaryColor).
+		Background(secondaryColor).
+		Padding(0, 1)
 
 	StatusValue = BaseStyle.
-			Foreground(lipgloss.Color("#FFFFFF")).
-			Background(secondaryColor).
-			Padding(0, 1)
+		Foreground(textColor).
+		Background(secondaryColor).
+		Padding(0, 1)
 
 	// Help styles
 	HelpStyle = BaseStyle.
-			Foreground(mutedColor)
+		Foreground(mutedColor)
 
 	HelpKey = BaseStyle.
 		Bold(true).
 		Foreground(accentColor)
 
 	HelpDesc = BaseStyle.
-			Foreground(mutedColor)
+		Foreground(mutedColor)
 
 	// Detail view styles
 	DetailTitle = BaseStyle.
-			Bold(true).
-			Foreground(primaryColor).
-			MarginBottom(1).
-			BorderStyle(lipgloss.NormalBorder()).
-			BorderBottom(true).
-			BorderForeground(mutedColor)
+		Bold(true).
+		Foreground(primaryColor).
+		MarginBottom(1).
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderBottom(true).
+		BorderForeground(mutedColor)
 
 	DetailLabel = BaseStyle.
-			Bold(true).
-			Foreground(accentColor).
-			Width(20)
+		Bold(true).
+		Foreground(accentColor).
+		Width(20)
 
 	DetailValue = BaseStyle.
-			Foreground(lipgloss.Color("#FFFFFF"))
+		Foreground(textColor)
 
 	// Code/JSON styles
 	CodeBlock = BaseStyle.
-			Background(lipgloss.Color("#1E1E1E")).
-			Foreground(lipgloss.Color("#D4D4D4")).
-			Padding(1).
-			MarginTop(1).
-			MarginBottom(1)
+		Background(lipgloss.Color("#1E1E1E")).
+		Foreground(lipgloss.Color("#D4D4D4")).
+		Padding(1).
+		MarginTop(1).
+		MarginBottom(1)
 
 	// Search styles
 	SearchPrompt = BaseStyle.
-			Foreground(primaryColor).
-			Bold(true)
+		Foreground(primaryColor).
+		Bold(true)
 
 	SearchInput = BaseStyle.
-			Foreground(lipgloss.Color("#FFFFFF"))
+		Foreground(textColor)
 
 	SearchMatch = BaseStyle.
-			Background(lipgloss.Color("#FFE082")).
-			Foreground(lipgloss.Color("#000000"))
+		Background(highlightColor).
+		Foreground(lipgloss.Color("#000000"))
 
 	SearchCurrentMatch = BaseStyle.
-				Background(lipgloss.Color("#FF5722")).
-				Foreground(lipgloss.Color("#FFFFFF")).
-				Bold(true)
+		Background(lipgloss.Color("#FF5722")).
+		Foreground(textColor).
+		Bold(true)
 
 	SearchInfo = BaseStyle.
-			Foreground(accentColor).
-			Bold(true)
+		Foreground(accentColor).
+		Bold(true)
 
 	// Tab styles
 	ActiveTab = BaseStyle.
-			Bold(true).
-			Foreground(lipgloss.Color("#000000")).
-			Background(primaryColor).
-			Padding(0, 2)
+		Bold(true).
+		Foreground(lipgloss.Color("#000000")).
+		Background(primaryColor).
+		Padding(0, 2)
 
 	InactiveTab = BaseStyle.
-			Foreground(mutedColor).
-			Background(lipgloss.Color("#333333")).
-			Padding(0, 2)
+		Foreground(mutedColor).
+		Background(lipgloss.Color("#333333")).
+		Padding(0, 2)
 
 	// Error styles
 	ErrorStyle = BaseStyle.
-			Foreground(errorColor).
-			Bold(true)
+		Foreground(errorColor).
+		Bold(true)
 
 	// Loading styles
 	LoadingStyle = BaseStyle.
-			Foreground(accentColor).
-			Bold(true)
+		Foreground(accentColor).
+		Bold(true)
 
 	// Container with border (like k9s) - base style without width
 	MainContainer = BaseStyle.
-			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(accentColor).
-			Padding(0, 1)
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(accentColor).
+		Padding(0, 1)
 
 	// Header styles for k9s-like display
 	ASCIIArtStyle = BaseStyle.
-			Foreground(primaryColor).
-			Bold(true)
+		Foreground(primaryColor).
+		Bold(true)
 
 	HeaderKey = BaseStyle.
-			Foreground(mutedColor)
+		Foreground(mutedColor)
 
 	HeaderValue = BaseStyle.
-			Foreground(accentColor).
-			Bold(true)
-)
+		Foreground(accentColor).
+		Bold(true)
+
+	if mono {
+		buildMonoStyles()
+	}
+}
+
+// buildMonoStyles replaces the styles that use fixed colours with ones
+// relying on bold and reverse video
+func buildMonoStyles() {
+	SelectedItem = BaseStyle.
+		Reverse(true).
+		PaddingLeft(1).
+		Bold(true)
+
+	CodeBlock = BaseStyle.
+		Padding(1).
+		MarginTop(1).
+		MarginBottom(1)
+
+	SearchMatch = BaseStyle.
+		Reverse(true)
+
+	SearchCurrentMatch = BaseStyle.
+		Reverse(true).
+		Bold(true).
+		Underline(true)
+
+	ActiveTab = BaseStyle.
+		Reverse(true).
+		Bold(true).
+		Padding(0, 2)
+
+	InactiveTab = BaseStyle.
+		Padding(0, 2)
+}
 
 // Helper functions
 func GetMainContainer(width, height int) lipgloss.Style {
@@ -202,7 +293,10 @@ func RenderHelp() string {
 		HelpKey.Render("j/k") + " " + HelpDesc.Render("up/down"),
 		HelpKey.Render("Enter") + " " + HelpDesc.Render("view"),
 		HelpKey.Render("/") + " " + HelpDesc.Render("search"),
+		HelpKey.Render("N/C/L") + " " + HelpDesc.Render("sort"),
 		HelpKey.Render("r") + " " + HelpDesc.Render("refresh"),
+		HelpKey.Render("R") + " " + HelpDesc.Render("region"),
+		HelpKey.Render(":") + " " + HelpDesc.Render("command"),
 		HelpKey.Render("q") + " " + HelpDesc.Render("quit"),
 		HelpKey.Render("?") + " " + HelpDesc.Render("help"),
 	}
@@ -0,0 +1,91 @@
+package styles
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme is a colour palette the styles are built from
+type Theme struct {
+	Primary   lipgloss.Color
+	Secondary lipgloss.Color
+	Accent    lipgloss.Color
+	Success   lipgloss.Color
+	Warning   lipgloss.Color
+	Error     lipgloss.Color
+	Muted     lipgloss.Color
+	Highlight lipgloss.Color
+	Text      lipgloss.Color
+	// Mono drops the fixed colours too and marks selections, the active
+	// tab and search matches with reverse video instead
+	Mono bool
+}
+
+// DefaultTheme is the theme used when none is configured
+const DefaultTheme = "aws"
+
+// Themes are the built-in palettes, selectable by name in the config file
+var Themes = map[string]Theme{
+	"aws": {
+		Primary:   "#FF9500", // AWS Orange
+		Secondary: "#232F3E", // AWS Dark Blue
+		Accent:    "#146EB4", // AWS Light Blue
+		Success:   "#00C853",
+		Warning:   "#FFA000",
+		Error:     "#D32F2F",
+		Muted:     "#666666",
+		Highlight: "#FFE082",
+		Text:      "#FFFFFF",
+	},
+	// light suits terminals with a light background
+	"light": {
+		Primary:   "#C45500",
+		Secondary: "#E8EDF3",
+		Accent:    "#0B5394",
+		Success:   "#1B7F3B",
+		Warning:   "#B26A00",
+		Error:     "#B71C1C",
+		Muted:     "#777777",
+		Highlight: "#FFD54F",
+		Text:      "#1F1F1F",
+	},
+	// mono leaves colours to the terminal and relies on bold and reverse
+	"mono": {
+		Mono: true,
+	},
+}
+
+// ThemeNames lists the built-in theme names, sorted
+func ThemeNames() []string {
+	names := make([]string, 0, len(Themes))
+	for name := range Themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ApplyTheme rebuilds all styles from the named theme. It must be called
+// before the UI starts rendering.
+func ApplyTheme(name string) error {
+	t, ok := Themes[name]
+	if !ok {
+		return fmt.Errorf("unknown theme %q (available: %v)", name, ThemeNames())
+	}
+
+	primaryColor = t.Primary
+	secondaryColor = t.Secondary
+	accentColor = t.Accent
+	successColor = t.Success
+	warningColor = t.Warning
+	errorColor = t.Error
+	mutedColor = t.Muted
+	highlightColor = t.Highlight
+	textColor = t.Text
+	mono = t.Mono
+
+	buildStyles()
+	return nil
+}